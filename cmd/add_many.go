@@ -2,9 +2,8 @@ package cmd
 
 import (
 	"fmt"
-	"time"
 
-	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/hooks"
 	"github.com/sandepten/work-obsidian-noter/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -24,17 +23,16 @@ func init() {
 }
 
 func runAddMany(cmd *cobra.Command, args []string) error {
-	today := time.Now().Truncate(24 * time.Hour)
-
-	// Ask which workplace this task belongs to
-	selectedWorkplace, err := prompter.SelectWorkplace(cfg.Workplaces)
+	// Choose the workplace this task belongs to
+	selectedWorkplace, err := chooseWorkplace("")
 	if err != nil {
-		return fmt.Errorf("error selecting workplace: %w", err)
+		return err
 	}
+	today := todayFor(selectedWorkplace)
 
 	// Create parser and writer for the selected workplace
-	workplaceParser := notes.NewParser(cfg.WorkNotesLocation, selectedWorkplace)
-	workplaceWriter := notes.NewWriter(cfg.WorkNotesLocation, selectedWorkplace)
+	workplaceParser := newParser(selectedWorkplace)
+	workplaceWriter := newWriter(selectedWorkplace)
 
 	// Get or create today's note for the selected workplace
 	todayNote, err := workplaceParser.FindTodayNote(today)
@@ -42,8 +40,12 @@ func runAddMany(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error finding today's note: %w", err)
 	}
 
-	if todayNote == nil {
-		todayNote = workplaceWriter.CreateTodayNote(today)
+	created := todayNote == nil
+	if created {
+		todayNote, err = workplaceWriter.CreateTodayNote(today)
+		if err != nil {
+			return fmt.Errorf("error creating today's note: %w", err)
+		}
 		prompter.DisplayMessage(fmt.Sprintf("Creating today's note for %s...", selectedWorkplace))
 	}
 
@@ -91,6 +93,14 @@ func runAddMany(cmd *cobra.Command, args []string) error {
 		if err := workplaceWriter.WriteNote(todayNote); err != nil {
 			return fmt.Errorf("error saving note: %w", err)
 		}
+		if created {
+			if err := emitNote(hooks.NoteCreated, selectedWorkplace, todayNote, nil); err != nil {
+				return err
+			}
+		}
+		if err := emitNote(hooks.ItemAdded, selectedWorkplace, todayNote, lastItems(todayNote.PendingWork, len(addedTasks))); err != nil {
+			return err
+		}
 
 		// Show summary
 		fmt.Println()
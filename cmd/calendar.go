@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/sandepten/work-obsidian-noter/internal/stats"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	calendarWorkplaces []string
+	calendarMonth      bool
+	calendarDay        string
+)
+
+var calendarCmd = &cobra.Command{
+	Use:   "calendar [YYYY-MM]",
+	Short: "Show a heatmap of completed work",
+	Long: `Show a contribution-style heatmap of the items completed each day over the
+past year, or a calendar of a single month when one is given or with --month.
+Workdays without a note are shown in red. All workplaces are counted unless
+--workplace is given.
+
+After the calendar you can enter a day to see its summary and items, or pass
+--day to show one directly.
+
+Examples:
+  worklog calendar
+  worklog calendar --month
+  worklog calendar 2025-03 -w Acme
+  worklog calendar --day yesterday`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCalendar,
+}
+
+func init() {
+	calendarCmd.Flags().StringSliceVarP(&calendarWorkplaces, "workplace", "w", nil, "Only count these workplaces (repeatable)")
+	calendarCmd.Flags().BoolVarP(&calendarMonth, "month", "m", false, "Show this month instead of the past year")
+	calendarCmd.Flags().StringVarP(&calendarDay, "day", "d", "", "Show the summary of a day")
+	rootCmd.AddCommand(calendarCmd)
+}
+
+// calendarDayInfo is the combined activity of the selected workplaces on a day
+type calendarDayInfo struct {
+	completed int
+	hasNote   bool
+}
+
+func runCalendar(cmd *cobra.Command, args []string) error {
+	workplaces := cfg.Workplaces
+	if len(calendarWorkplaces) > 0 {
+		for _, wp := range calendarWorkplaces {
+			if !isWorkplace(wp) {
+				return fmt.Errorf("workplace '%s' not found", wp)
+			}
+		}
+		workplaces = calendarWorkplaces
+	}
+	today := todayAcross(workplaces)
+
+	if calendarDay != "" {
+		day, err := parseDateArg(calendarDay, today)
+		if err != nil {
+			return err
+		}
+		return showCalendarDay(day, workplaces)
+	}
+
+	monthView := calendarMonth || len(args) > 0
+	var from, to time.Time
+	if monthView {
+		month := today
+		if len(args) > 0 {
+			m, err := time.Parse("2006-01", args[0])
+			if err != nil {
+				return fmt.Errorf("invalid month %q (use YYYY-MM)", args[0])
+			}
+			month = m
+		}
+		from = time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+		to = from.AddDate(0, 1, -1)
+	} else {
+		// 53 weeks, Monday first, ending with the current week
+		monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		from = monday.AddDate(0, 0, -52*7)
+		to = monday.AddDate(0, 0, 6)
+	}
+
+	days, err := calendarActivity(workplaces, from, to, today)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	title := "📅 Worklog Calendar"
+	if len(workplaces) == 1 {
+		title += "  " + ui.MutedStyle.Render("•") + "  " + ui.InfoStyle.Render(workplaces[0])
+	}
+	fmt.Println(ui.TitleStyle.Render(title))
+	fmt.Println()
+
+	if monthView {
+		renderMonthCalendar(days, from, today)
+	} else {
+		renderYearHeatmap(days, from, to, today)
+	}
+
+	if !isInteractive() {
+		return nil
+	}
+	for {
+		input, err := prompter.PromptForDay(func(s string) error {
+			_, err := parseDateArg(s, today)
+			return err
+		})
+		if err != nil || input == "" {
+			return err
+		}
+		day, _ := parseDateArg(input, today)
+		if err := showCalendarDay(day, workplaces); err != nil {
+			return err
+		}
+	}
+}
+
+// calendarActivity combines the daily activity of the workplaces
+func calendarActivity(workplaces []string, from, to, today time.Time) (map[string]*calendarDayInfo, error) {
+	days := make(map[string]*calendarDayInfo)
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		days[d.Format("2006-01-02")] = &calendarDayInfo{}
+	}
+
+	for _, wp := range workplaces {
+		report, err := stats.Compute(wp, newParser(wp), from, to, today)
+		if err != nil {
+			return nil, err
+		}
+		for _, day := range report.Days {
+			info := days[day.Date.Format("2006-01-02")]
+			info.completed += day.Completed
+			info.hasNote = info.hasNote || day.HasNote
+		}
+	}
+
+	return days, nil
+}
+
+// calendarCell describes how to draw a day
+func calendarCell(days map[string]*calendarDayInfo, date, firstNote, today time.Time, max int) (level int, missing, future bool) {
+	if date.After(today) {
+		return 0, false, true
+	}
+	info := days[date.Format("2006-01-02")]
+	weekend := date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
+	missing = !info.hasNote && !weekend && !firstNote.IsZero() && !date.Before(firstNote)
+	return ui.HeatLevel(info.completed, max), missing, false
+}
+
+// calendarTotals returns the busiest day, the first day with a note and totals
+func calendarTotals(days map[string]*calendarDayInfo, from, to, today time.Time) (max int, firstNote time.Time, completed, notes, missing int) {
+	for d := from; !d.After(to) && !d.After(today); d = d.AddDate(0, 0, 1) {
+		info := days[d.Format("2006-01-02")]
+		if info.completed > max {
+			max = info.completed
+		}
+		completed += info.completed
+		if info.hasNote {
+			notes++
+			if firstNote.IsZero() {
+				firstNote = d
+			}
+		}
+	}
+	for d := firstNote; !firstNote.IsZero() && !d.After(to) && !d.After(today); d = d.AddDate(0, 0, 1) {
+		if _, isMissing, _ := calendarCell(days, d, firstNote, today, max); isMissing {
+			missing++
+		}
+	}
+	return max, firstNote, completed, notes, missing
+}
+
+// renderYearHeatmap draws weeks as columns and weekdays as rows
+func renderYearHeatmap(days map[string]*calendarDayInfo, from, to, today time.Time) {
+	max, firstNote, completed, notes, missing := calendarTotals(days, from, to, today)
+	weeks := int(to.Sub(from).Hours()/24)/7 + 1
+
+	// Month labels above the first week of each month
+	labels := []rune(strings.Repeat(" ", weeks*2+2))
+	lastMonth := time.Month(0)
+	for w := 0; w < weeks; w++ {
+		monday := from.AddDate(0, 0, w*7)
+		if monday.Month() != lastMonth && w*2+3 <= len(labels) {
+			copy(labels[w*2:], []rune(monday.Format("Jan")))
+			lastMonth = monday.Month()
+		}
+	}
+	fmt.Println("    " + ui.MutedStyle.Render(strings.TrimRight(string(labels), " ")))
+
+	rowLabels := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	for row := 0; row < 7; row++ {
+		var b strings.Builder
+		b.WriteString(ui.MutedStyle.Render(fmt.Sprintf("%-3s ", rowLabels[row])))
+		for w := 0; w < weeks; w++ {
+			level, isMissing, future := calendarCell(days, from.AddDate(0, 0, w*7+row), firstNote, today, max)
+			switch {
+			case future:
+				b.WriteString("  ")
+			case isMissing:
+				b.WriteString(ui.RenderHeatCell("□", 0, true) + " ")
+			default:
+				b.WriteString(ui.RenderHeatCell("■", level, false) + " ")
+			}
+		}
+		fmt.Println(b.String())
+	}
+
+	fmt.Println()
+	printCalendarLegend()
+	fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("%d item(s) completed in the last year • %d note(s) • %d workday(s) without a note", completed, notes, missing)))
+	fmt.Println()
+}
+
+// renderMonthCalendar draws a month as a classic calendar grid
+func renderMonthCalendar(days map[string]*calendarDayInfo, first, today time.Time) {
+	last := first.AddDate(0, 1, -1)
+	max, firstNote, completed, notes, missing := calendarTotals(days, first, last, today)
+
+	fmt.Println(ui.HeaderStyle.Render(first.Format("January 2006")))
+	fmt.Println(ui.MutedStyle.Render(" Mo  Tu  We  Th  Fr  Sa  Su"))
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat("    ", (int(first.Weekday())+6)%7))
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		level, isMissing, future := calendarCell(days, d, firstNote, today, max)
+		text := fmt.Sprintf("%3d", d.Day())
+		switch {
+		case future:
+			b.WriteString(ui.MutedStyle.Render(text))
+		case isMissing:
+			b.WriteString(ui.RenderHeatCell(text, 0, true))
+		case level == 0:
+			b.WriteString(text)
+		default:
+			b.WriteString(ui.RenderHeatCell(text, level, false))
+		}
+		b.WriteString(" ")
+		if d.Weekday() == time.Sunday {
+			fmt.Println(b.String())
+			b.Reset()
+		}
+	}
+	if b.Len() > 0 {
+		fmt.Println(b.String())
+	}
+
+	fmt.Println()
+	printCalendarLegend()
+	fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("%d item(s) completed • %d note(s) • %d workday(s) without a note", completed, notes, missing)))
+	fmt.Println()
+}
+
+// printCalendarLegend explains the heatmap colors
+func printCalendarLegend() {
+	var b strings.Builder
+	b.WriteString(ui.MutedStyle.Render("Less "))
+	for level := range ui.HeatColors {
+		b.WriteString(ui.RenderHeatCell("■", level, false) + " ")
+	}
+	b.WriteString(ui.MutedStyle.Render("More   "))
+	b.WriteString(ui.RenderHeatCell("□", 0, true))
+	b.WriteString(ui.MutedStyle.Render(" no note"))
+	fmt.Println(b.String())
+}
+
+// showCalendarDay prints the summary and items of each workplace's note for a day
+func showCalendarDay(day time.Time, workplaces []string) error {
+	found := false
+	for _, wp := range workplaces {
+		note, err := newParser(wp).FindTodayNote(day)
+		if err != nil {
+			return fmt.Errorf("error reading note for %s: %w", wp, err)
+		}
+		if note == nil {
+			continue
+		}
+		found = true
+
+		fmt.Println()
+		fmt.Printf("%s  %s  %s\n", ui.TitleStyle.Render("📅 "+day.Format("Mon, Jan 2 2006")), ui.MutedStyle.Render("•"), ui.InfoStyle.Render(wp))
+		if note.Summary != "" {
+			fmt.Println(ui.RenderSummary("Summary", note.Summary))
+		}
+		if note.YesterdaySummary != "" {
+			fmt.Println(ui.RenderSummary("Yesterday", note.YesterdaySummary))
+		}
+		prompter.DisplayWorkItems(note.PendingWork, note.CompletedWork)
+	}
+
+	if !found {
+		prompter.DisplayWarning(fmt.Sprintf("No note found for %s", day.Format("Mon, Jan 2 2006")))
+	}
+	return nil
+}
+
+// isInteractive reports whether stdin is a terminal
+func isInteractive() bool {
+	return isatty.IsTerminal(os.Stdin.Fd())
+}
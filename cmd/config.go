@@ -0,0 +1,547 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/sandepten/work-obsidian-noter/internal/config"
+	"github.com/sandepten/work-obsidian-noter/internal/hooks"
+	"github.com/sandepten/work-obsidian-noter/internal/recurring"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configWorkplace string
+	configOffline   bool
+	configPathState bool
+	configPathCache bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change settings",
+	Long: `View and change the settings in config.yaml.
+
+Settings are named by their key in the file, such as notes_dir or ai.model, or
+by their environment variable, such as AI_MODEL. Use --workplace to read or
+change a setting in a workplace's section instead of the global one. Lists
+such as tags are given comma-separated.
+
+Without a subcommand the settings are listed.
+
+Examples:
+  worklog config get ai.model
+  worklog config set timezone Europe/Berlin -w Acme
+  worklog config set tags acme,client
+  worklog config unset layout -w Acme
+  worklog config validate`,
+	Args: cobra.NoArgs,
+	RunE: runConfigList,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <setting>",
+	Short: "Print the value of a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <setting> <value>",
+	Short: "Change a setting",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <setting>",
+	Short: "Remove a setting so its default applies",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigUnset,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every setting with its value and where it comes from",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigList,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the config file in your editor",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigEdit,
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the config file",
+	Long: `Print the path of the config file, or with --state or --cache the folder
+worklog keeps its own data in (time log, scheduled items) or its cache.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigPath,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config for problems",
+	Long: `Check the config file and every workplace: that the file can be read, the
+workplace names are unique and can be used in file names, the notes folders are
+writable, the note settings are valid and the AI server responds.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
+func init() {
+	configCmd.PersistentFlags().StringVarP(&configWorkplace, "workplace", "w", "", "Use the workplace's section instead of the global settings")
+	configValidateCmd.Flags().BoolVar(&configOffline, "offline", false, "Don't check that the AI server responds")
+	configPathCmd.Flags().BoolVar(&configPathState, "state", false, "Print the state folder instead")
+	configPathCmd.Flags().BoolVar(&configPathCache, "cache", false, "Print the cache folder instead")
+	configPathCmd.MarkFlagsMutuallyExclusive("state", "cache")
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// isConfigCommand reports whether cmd is 'worklog config' or one of its subcommands
+func isConfigCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == configCmd {
+			return true
+		}
+	}
+	return false
+}
+
+// requireConfig returns why the config couldn't be loaded, if it couldn't
+func requireConfig() error {
+	if cfgErr != nil {
+		return fmt.Errorf("%w (run 'worklog config edit' to fix it)", cfgErr)
+	}
+	if configWorkplace != "" && !isWorkplace(configWorkplace) {
+		return fmt.Errorf("workplace '%s' not found", configWorkplace)
+	}
+	return nil
+}
+
+// findSetting returns the option named by a key or environment variable
+func findSetting(name string) (*config.Option, error) {
+	opt := config.FindOption(name)
+	if opt == nil {
+		return nil, fmt.Errorf("unknown setting %q (see 'worklog config list')", name)
+	}
+	return opt, nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	if err := requireConfig(); err != nil {
+		return err
+	}
+	opt, err := findSetting(args[0])
+	if err != nil {
+		return err
+	}
+
+	value, source := cfg.Lookup(opt, configWorkplace)
+	if source == config.SourceDefault {
+		value = opt.Default
+	}
+	fmt.Println(value)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	if err := requireConfig(); err != nil {
+		return err
+	}
+	opt, err := findSetting(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.Set(opt, configWorkplace, args[1]); err != nil {
+		return err
+	}
+	if err := checkSettings(); err != nil {
+		return fmt.Errorf("%w, %s was not changed", err, opt.Key)
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Set %s to %s%s", opt.Key, args[1], workplaceSuffix(configWorkplace))))
+	warnOverridden(opt, configWorkplace)
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	if err := requireConfig(); err != nil {
+		return err
+	}
+	opt, err := findSetting(args[0])
+	if err != nil {
+		return err
+	}
+
+	removed, err := cfg.Unset(opt, configWorkplace)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		fmt.Println(ui.RenderWarning(fmt.Sprintf("%s isn't set%s", opt.Key, workplaceSuffix(configWorkplace))))
+		return nil
+	}
+	if err := checkSettings(); err != nil {
+		return fmt.Errorf("%w, %s was not removed", err, opt.Key)
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Removed %s%s", opt.Key, workplaceSuffix(configWorkplace))))
+	warnOverridden(opt, configWorkplace)
+	return nil
+}
+
+// checkSettings validates the settings of every workplace after a change
+func checkSettings() error {
+	if err := validateWorkplaceSettings(""); err != nil {
+		return fmt.Errorf("invalid setting: %w", err)
+	}
+	for _, wp := range cfg.Workplaces {
+		if err := validateWorkplaceSettings(wp); err != nil {
+			return fmt.Errorf("invalid setting for %s: %w", wp, err)
+		}
+	}
+	if _, err := cfg.Focus(); err != nil {
+		return err
+	}
+	return checkDefaultWorkplace()
+}
+
+// checkDefaultWorkplace checks that default_workplace names an active workplace
+func checkDefaultWorkplace() error {
+	if wp := cfg.DefaultWorkplace; wp != "" && !isWorkplace(wp) {
+		return fmt.Errorf("default_workplace: workplace '%s' not found", wp)
+	}
+	return nil
+}
+
+// workplaceSuffix names the workplace a setting was changed for
+func workplaceSuffix(workplace string) string {
+	if workplace == "" {
+		return ""
+	}
+	return " for " + workplace
+}
+
+// warnOverridden warns when an environment variable hides the file's value
+func warnOverridden(opt *config.Option, workplace string) {
+	_, source := cfg.Lookup(opt, workplace)
+	if source == config.SourceEnvironment || source == config.SourceWorkplaceEnvironment {
+		fmt.Println(ui.RenderWarning(fmt.Sprintf("An environment variable overrides %s, so the change has no effect while it is set", opt.Key)))
+	}
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	if err := requireConfig(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(ui.MutedStyle.Render(config.GetConfigPath()))
+	fmt.Println()
+
+	if configWorkplace != "" {
+		fmt.Println(ui.HeaderStyle.Render(configWorkplace))
+		fmt.Println(settingsTable(configWorkplace, false).Render())
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Println(ui.HeaderStyle.Render("Global"))
+	fmt.Println(settingsTable("", false).Render())
+	fmt.Println()
+
+	// Workplaces only list what they override
+	for _, wp := range cfg.Workplaces {
+		fmt.Println(ui.HeaderStyle.Render(wp))
+		if overrides := settingsTable(wp, true); overrides != nil {
+			fmt.Println(overrides.Render())
+		} else {
+			fmt.Println(ui.MutedStyle.Render("  Uses the global settings"))
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// settingsTable lists the settings of a workplace, or the global ones. With
+// overridesOnly it only lists what the workplace sets itself, and returns nil
+// when that is nothing.
+func settingsTable(workplace string, overridesOnly bool) *table.Table {
+	table := statsTable().Headers("Setting", "Value", "From")
+	rows := 0
+	for i := range config.Options {
+		opt := &config.Options[i]
+		if workplace != "" && opt.Global() {
+			continue
+		}
+		value, source := cfg.Lookup(opt, workplace)
+		from := string(source)
+		switch source {
+		case config.SourceDefault:
+			value = opt.Default
+		case config.SourceEnvironment:
+			from = "$" + opt.Env
+		case config.SourceWorkplaceEnvironment:
+			from = "$" + opt.WorkplaceEnv(workplace)
+		}
+		if overridesOnly && source != config.SourceWorkplace && source != config.SourceWorkplaceEnvironment {
+			continue
+		}
+		table.Row(opt.Key, truncateValue(value, 50), from)
+		rows++
+	}
+	if rows == 0 {
+		return nil
+	}
+	return table
+}
+
+// truncateValue shortens long values such as prompts to one table line
+func truncateValue(value string, max int) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if len([]rune(value)) <= max {
+		return value
+	}
+	return string([]rune(value)[:max-1]) + "…"
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	path := config.GetConfigPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(configSample), 0644); err != nil {
+			return err
+		}
+	}
+
+	if err := openInEditor(path); err != nil {
+		return err
+	}
+
+	// Check the file right away rather than on the next command
+	if cfg, cfgErr = config.Load(); cfgErr != nil {
+		return fmt.Errorf("%w (run 'worklog config edit' again to fix it)", cfgErr)
+	}
+	if err := checkSettings(); err != nil {
+		return fmt.Errorf("%w (run 'worklog config edit' again to fix it)", err)
+	}
+
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Saved %s", path)))
+	return nil
+}
+
+// configSample is written when editing a config file that doesn't exist yet
+const configSample = `# Worklog configuration
+#
+# notes_dir: ~/Documents/obsidian-notes/Inbox/work
+# ai:
+#   model: claude-sonnet-4
+# workplaces:
+#   Acme:
+#     timezone: Europe/Berlin
+workplaces:
+  Work: {}
+`
+
+func runConfigPath(cmd *cobra.Command, args []string) error {
+	switch {
+	case configPathState:
+		fmt.Println(config.StateDir())
+	case configPathCache:
+		fmt.Println(config.CacheDir())
+	default:
+		fmt.Println(config.GetConfigPath())
+	}
+	return nil
+}
+
+// configProblem is something 'worklog config validate' found wrong
+type configProblem struct {
+	Subject string
+	Message string
+	Warning bool // Worth knowing, but nothing fails because of it
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	fmt.Println()
+	fmt.Println(ui.TitleStyle.Render("🔧 Config"))
+	fmt.Println(ui.MutedStyle.Render(config.GetConfigPath()))
+	fmt.Println(ui.RenderDivider(50))
+	fmt.Println()
+
+	var problems []configProblem
+	if cfgErr != nil {
+		problems = append(problems, configProblem{Subject: "config file", Message: cfgErr.Error()})
+	} else {
+		problems = validateConfig()
+	}
+
+	errorCount := 0
+	for _, p := range problems {
+		subject := ui.InfoStyle.Render(p.Subject)
+		if p.Warning {
+			fmt.Printf("%s %s: %s\n", ui.WarningStyle.Render(ui.IconWarning), subject, p.Message)
+		} else {
+			fmt.Printf("%s %s: %s\n", ui.ErrorStyle.Render(ui.IconError), subject, p.Message)
+			errorCount++
+		}
+	}
+	if len(problems) > 0 {
+		fmt.Println()
+	}
+
+	if errorCount > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("found %d problem(s) in the config", errorCount)
+	}
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Config is valid (%d workplace(s))", len(cfg.Workplaces))))
+	fmt.Println()
+	return nil
+}
+
+// validateConfig checks the workplaces, their settings and folders, and the AI servers
+func validateConfig() []configProblem {
+	var problems []configProblem
+	add := func(subject string, warning bool, format string, a ...any) {
+		problems = append(problems, configProblem{Subject: subject, Message: fmt.Sprintf(format, a...), Warning: warning})
+	}
+
+	seen := make(map[string]string)
+	for _, wp := range cfg.Workplaces {
+		if err := validateWorkplaceName(wp); err != nil {
+			add(fmt.Sprintf("workplace %q", wp), false, "%v", err)
+		}
+		// Case-insensitive file systems would mix up the notes of both
+		if other, ok := seen[strings.ToLower(wp)]; ok {
+			add(fmt.Sprintf("workplace %q", wp), false, "same name as workplace %q", other)
+		}
+		seen[strings.ToLower(wp)] = wp
+	}
+
+	if err := validateWorkplaceSettings(""); err != nil {
+		add("global settings", false, "%v", err)
+	}
+	for _, wp := range cfg.Workplaces {
+		if err := validateWorkplaceSettings(wp); err != nil {
+			add(wp, false, "%v", err)
+		}
+		dir := cfg.NotesDirFor(wp)
+		if missing, err := checkWritable(dir); err != nil {
+			add(wp, false, "notes folder %s: %v", dir, err)
+		} else if missing {
+			add(wp, true, "notes folder %s doesn't exist yet and will be created", dir)
+		}
+		if _, err := recurring.Load(cfg.RecurringFileFor(wp)); err != nil {
+			add(wp, false, "%v", err)
+		}
+	}
+
+	if _, err := cfg.Focus(); err != nil {
+		add("focus", false, "%v", err)
+	}
+	if err := checkDefaultWorkplace(); err != nil {
+		add("global settings", false, "%v", err)
+	}
+
+	hookList, err := hooks.Parse(cfg.Hooks())
+	if err != nil {
+		add("hooks", false, "%v", err)
+	}
+	for _, hook := range hookList {
+		if _, err := exec.LookPath(hook.Command); err != nil {
+			add("hooks", false, "%s hook %s: %v", hook.Event, hook.Command, err)
+		}
+		for _, wp := range hook.Workplaces {
+			if !isWorkplace(wp) {
+				add("hooks", true, "%s hook %s: workplace '%s' not found", hook.Event, hook.Name(), wp)
+			}
+		}
+	}
+
+	// Summarizer plugins are checked even offline, as they only need to be found
+	checked := make(map[string]bool)
+	for _, wp := range cfg.Workplaces {
+		ai := cfg.AIFor(wp)
+		if ai.Summarizer != "" {
+			if !checked[ai.Summarizer] {
+				checked[ai.Summarizer] = true
+				if err := aiClientFor(wp).TestConnection(); err != nil {
+					add("ai.summarizer", false, "%v", err)
+				}
+			}
+			continue
+		}
+		if configOffline || checked[ai.Server] {
+			continue
+		}
+		checked[ai.Server] = true
+
+		client := aiClientFor(wp)
+		client.SetTimeout(5 * time.Second)
+		if err := client.TestConnection(); err != nil {
+			add("ai.server", false, "%s: %v", ai.Server, err)
+		}
+	}
+
+	return problems
+}
+
+// checkWritable reports whether notes can be written to dir. A folder that
+// doesn't exist yet is fine if it can be created, and reported as missing.
+func checkWritable(dir string) (bool, error) {
+	info, err := os.Stat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		// Check the closest folder that exists instead
+		parent := filepath.Dir(dir)
+		for parent != filepath.Dir(parent) {
+			if _, err := os.Stat(parent); err == nil {
+				break
+			}
+			parent = filepath.Dir(parent)
+		}
+		_, err := checkWritable(parent)
+		return true, err
+	}
+	if err != nil {
+		return false, err
+	}
+	if !info.IsDir() {
+		return false, fmt.Errorf("not a folder")
+	}
+
+	file, err := os.CreateTemp(dir, ".worklog-check-*")
+	if err != nil {
+		return false, fmt.Errorf("not writable")
+	}
+	file.Close()
+	os.Remove(file.Name())
+	return false, nil
+}
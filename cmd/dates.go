@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// todayFor returns today's date in the time zone configured for a workplace
+func todayFor(workplace string) time.Time {
+	return dateFor(workplace, time.Now())
+}
+
+// todayAcross returns today's date for a command covering several
+// workplaces: that of the workplace when there is only one, and in the global
+// time zone otherwise
+func todayAcross(workplaces []string) time.Time {
+	if len(workplaces) == 1 {
+		return todayFor(workplaces[0])
+	}
+	return todayFor("")
+}
+
+// dateFor returns the date of a moment in the time zone configured for a
+// workplace
+func dateFor(workplace string, t time.Time) time.Time {
+	loc, err := cfg.LocationFor(workplace)
+	if err != nil || loc == nil {
+		// Time zones are validated in initConfig
+		return dateOf(t.Local())
+	}
+	return dateOf(t.In(loc))
+}
+
+// dateOf returns the calendar date of a moment in its own time zone, at
+// midnight UTC like note dates
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// parseDateArg parses a date given on the command line relative to today.
+// It accepts YYYY-MM-DD, today, yesterday, tomorrow, relative offsets such as
+// +3d, -2w or 1m, and weekday names (the next such day after today).
+func parseDateArg(value string, today time.Time) (time.Time, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	switch value {
+	case "":
+		return time.Time{}, fmt.Errorf("date cannot be empty")
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	// Relative offsets: +3d, -2w, 1m
+	if unit := value[len(value)-1]; unit == 'd' || unit == 'w' || unit == 'm' {
+		if n, err := strconv.Atoi(strings.TrimPrefix(value[:len(value)-1], "+")); err == nil {
+			switch unit {
+			case 'd':
+				return today.AddDate(0, 0, n), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*n), nil
+			case 'm':
+				return today.AddDate(0, n, 0), nil
+			}
+		}
+	}
+
+	// Weekday names: the next occurrence after today
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if value == name || value == name[:3] {
+			days := (int(d) - int(today.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, days), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD, today, yesterday, tomorrow, +3d, -2w or a weekday)", value)
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	deferUntil     string
+	deferWorkplace string
+	deferDate      string
+)
+
+var deferCmd = &cobra.Command{
+	Use:   "defer <item>",
+	Short: "Park a pending item until a later date",
+	Long: `Park a pending item and bring it back later.
+
+The item stays in its note marked "- [>]", and is added to Pending again when
+the note for that date is created, or by 'worklog start' if the note already
+exists. Inline fields and sub-items are kept.
+
+Items are chosen by number (as shown by 'worklog list') or by part of their text.
+
+Examples:
+  worklog defer 2 --until 2026-11-01
+  worklog defer "quarterly review" --until +2w
+  worklog defer 1 --until monday -d yesterday`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDefer,
+}
+
+func init() {
+	deferCmd.Flags().StringVarP(&deferUntil, "until", "u", "", "Date the item comes back (YYYY-MM-DD, tomorrow, +1w, friday…)")
+	deferCmd.Flags().StringVarP(&deferWorkplace, "workplace", "w", "", "Workplace of the note")
+	deferCmd.Flags().StringVarP(&deferDate, "date", "d", "today", "Date of the note the item is in")
+	deferCmd.MarkFlagRequired("until")
+	rootCmd.AddCommand(deferCmd)
+}
+
+func runDefer(cmd *cobra.Command, args []string) error {
+	selectedWorkplace, err := chooseWorkplace(deferWorkplace)
+	if err != nil {
+		return err
+	}
+
+	today := todayFor(selectedWorkplace)
+	until, err := parseDeferDate(deferUntil, today)
+	if err != nil {
+		return err
+	}
+	date, err := parseDateArg(deferDate, today)
+	if err != nil {
+		return err
+	}
+
+	note, err := newParser(selectedWorkplace).FindTodayNote(date)
+	if err != nil {
+		return fmt.Errorf("error finding note: %w", err)
+	}
+	if note == nil {
+		prompter.DisplayWarning(fmt.Sprintf("No note found for %s in %s.", date.Format("Mon, Jan 2"), selectedWorkplace))
+		return nil
+	}
+
+	index, err := resolveItem(note.PendingWork, args[0])
+	if err != nil {
+		return err
+	}
+	item := note.PendingWork[index]
+	if !item.Active() {
+		return fmt.Errorf("\"%s\" is %s; only open or blocked items can be deferred", item.Title(), item.State)
+	}
+
+	// Schedule first: a failure then leaves a duplicate, never a lost item
+	if err := deferItems(selectedWorkplace, []notes.WorkItem{item}, []time.Time{until}, today, note.FilePath); err != nil {
+		return err
+	}
+	markDeferred(&note.PendingWork[index], until)
+	if err := newWriter(selectedWorkplace).WriteNote(note); err != nil {
+		return fmt.Errorf("error saving note (the item was also scheduled): %w", err)
+	}
+
+	fmt.Println()
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Deferred \"%s\" until %s", item.Title(), until.Format("Mon, Jan 2"))))
+	fmt.Println()
+	return nil
+}
+
+// markDeferred marks an item left behind in its note as deferred, noting when it comes back
+func markDeferred(item *notes.WorkItem, until time.Time) {
+	item.SetState(notes.StateDeferred, "")
+	item.SetField("until", until.Format("2006-01-02"))
+}
+
+// parseDeferDate parses the date an item is deferred until, which must be after today
+func parseDeferDate(value string, today time.Time) (time.Time, error) {
+	until, err := parseDateArg(value, today)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !until.After(today) {
+		return time.Time{}, fmt.Errorf("items can only be deferred to a date after today")
+	}
+	return until, nil
+}
+
+// deferItems adds items to a workplace's scheduled items, each until its own date
+func deferItems(workplace string, items []notes.WorkItem, until []time.Time, today time.Time, from string) error {
+	ledger, err := notes.LoadLedger(cfg.ScheduledFileFor(workplace))
+	if err != nil {
+		return fmt.Errorf("error reading scheduled items: %w", err)
+	}
+	for i, item := range items {
+		ledger.Add(item, until[i], today, relToNotes(from))
+	}
+	if err := ledger.Save(); err != nil {
+		return fmt.Errorf("error saving scheduled items: %w", err)
+	}
+	return nil
+}
+
+// unscheduleItem drops an item from a workplace's scheduled items
+func unscheduleItem(workplace string, item notes.WorkItem) error {
+	ledger, err := notes.LoadLedger(cfg.ScheduledFileFor(workplace))
+	if err != nil {
+		return fmt.Errorf("error reading scheduled items: %w", err)
+	}
+	if !ledger.Remove(item.Text) {
+		return nil
+	}
+	if err := ledger.Save(); err != nil {
+		return fmt.Errorf("error saving scheduled items: %w", err)
+	}
+	return nil
+}
@@ -5,8 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
-	"time"
 
+	"github.com/sandepten/work-obsidian-noter/internal/hooks"
 	"github.com/sandepten/work-obsidian-noter/internal/notes"
 	"github.com/sandepten/work-obsidian-noter/internal/ui"
 	"github.com/spf13/cobra"
@@ -31,17 +31,16 @@ func init() {
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
-	today := time.Now().Truncate(24 * time.Hour)
-
-	// Ask which workplace
-	selectedWorkplace, err := prompter.SelectWorkplace(cfg.Workplaces)
+	// Choose the workplace
+	selectedWorkplace, err := chooseWorkplace("")
 	if err != nil {
-		return fmt.Errorf("error selecting workplace: %w", err)
+		return err
 	}
+	today := todayFor(selectedWorkplace)
 
 	// Create parser and writer for the selected workplace
-	workplaceParser := notes.NewParser(cfg.WorkNotesLocation, selectedWorkplace)
-	workplaceWriter := notes.NewWriter(cfg.WorkNotesLocation, selectedWorkplace)
+	workplaceParser := newParser(selectedWorkplace)
+	workplaceWriter := newWriter(selectedWorkplace)
 
 	// Get today's note
 	todayNote, err := workplaceParser.FindTodayNote(today)
@@ -92,6 +91,11 @@ func deleteEntireWorklog(todayNote *notes.Note, selectedWorkplace string) error
 	if err := os.Remove(todayNote.FilePath); err != nil {
 		return fmt.Errorf("error deleting note: %w", err)
 	}
+	if items := append(append([]notes.WorkItem{}, todayNote.PendingWork...), todayNote.CompletedWork...); len(items) > 0 {
+		if err := emitNote(hooks.ItemDeleted, selectedWorkplace, todayNote, items); err != nil {
+			return err
+		}
+	}
 
 	fmt.Println()
 	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Deleted today's worklog for %s", selectedWorkplace)))
@@ -114,10 +118,14 @@ func deleteSpecificTasks(todayNote *notes.Note, workplaceWriter *notes.Writer, s
 	}
 
 	var pendingDeleted, completedDeleted int
+	var deleted []notes.WorkItem
 
 	// Delete pending tasks
 	if todayNote.HasPendingWork() {
-		pendingIndices, err := prompter.SelectTasksToDelete(todayNote.PendingWork, "pending")
+		pendingIndices, err := prompter.SelectTasksToDelete(todayNote.PendingWork, ui.MsgTaskTypePending)
+		if cancelled(err) {
+			return nil
+		}
 		if err != nil {
 			return fmt.Errorf("error selecting pending tasks: %w", err)
 		}
@@ -126,6 +134,7 @@ func deleteSpecificTasks(todayNote *notes.Note, workplaceWriter *notes.Writer, s
 		sort.Sort(sort.Reverse(sort.IntSlice(pendingIndices)))
 
 		for _, idx := range pendingIndices {
+			deleted = append(deleted, todayNote.PendingWork[idx])
 			todayNote.RemovePendingItem(idx)
 		}
 		pendingDeleted = len(pendingIndices)
@@ -135,7 +144,10 @@ func deleteSpecificTasks(todayNote *notes.Note, workplaceWriter *notes.Writer, s
 
 	// Delete completed tasks
 	if todayNote.HasCompletedWork() {
-		completedIndices, err := prompter.SelectTasksToDelete(todayNote.CompletedWork, "completed")
+		completedIndices, err := prompter.SelectTasksToDelete(todayNote.CompletedWork, ui.MsgTaskTypeCompleted)
+		if cancelled(err) {
+			return nil
+		}
 		if err != nil {
 			return fmt.Errorf("error selecting completed tasks: %w", err)
 		}
@@ -144,6 +156,7 @@ func deleteSpecificTasks(todayNote *notes.Note, workplaceWriter *notes.Writer, s
 		sort.Sort(sort.Reverse(sort.IntSlice(completedIndices)))
 
 		for _, idx := range completedIndices {
+			deleted = append(deleted, todayNote.CompletedWork[idx])
 			todayNote.RemoveCompletedItem(idx)
 		}
 		completedDeleted = len(completedIndices)
@@ -162,6 +175,9 @@ func deleteSpecificTasks(todayNote *notes.Note, workplaceWriter *notes.Writer, s
 	if err := workplaceWriter.WriteNote(todayNote); err != nil {
 		return fmt.Errorf("error saving note: %w", err)
 	}
+	if err := emitNote(hooks.ItemDeleted, selectedWorkplace, todayNote, deleted); err != nil {
+		return err
+	}
 
 	fmt.Println()
 	fmt.Println(ui.RenderDivider(50))
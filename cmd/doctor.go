@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sandepten/work-obsidian-noter/internal/doctor"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorFix       bool
+	doctorWorkplace string
+	doctorJSON      bool
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check notes for problems and optionally repair them",
+	Long: `Scan every note of every workplace and report problems such as missing
+frontmatter, an id or date that doesn't match the file, duplicated sections,
+checked items under pending work, unnormalised tags and notes that more than
+one workplace reads as its own.
+
+Use --fix to repair everything that can be repaired automatically. Notes with
+problems that can't be fixed safely are left untouched.`,
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Repair fixable problems")
+	doctorCmd.Flags().StringVarP(&doctorWorkplace, "workplace", "w", "", "Only check this workplace")
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Print diagnostics as JSON")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	workplaces := cfg.Workplaces
+	if doctorWorkplace != "" {
+		if !isWorkplace(doctorWorkplace) {
+			return fmt.Errorf("workplace '%s' not found", doctorWorkplace)
+		}
+		workplaces = []string{doctorWorkplace}
+	}
+
+	// A note listed by more than one workplace would be changed by all of them
+	owners := make(map[string][]string)
+	for _, wp := range cfg.AllWorkplaces() {
+		files, err := newParser(wp).ListNotes()
+		if err != nil {
+			continue // Reported below for the workplaces being checked
+		}
+		for _, nf := range files {
+			owners[nf.Path] = append(owners[nf.Path], wp)
+		}
+	}
+
+	var all []doctor.Diagnostic
+	checked, fixed := 0, 0
+
+	for _, wp := range workplaces {
+		workplaceParser := newParser(wp)
+		workplaceWriter := newWriter(wp)
+		markers := markersFor(wp)
+		tags := cfg.NoteTagsFor(wp)
+
+		files, err := workplaceParser.ListNotes()
+		if err != nil {
+			return fmt.Errorf("error finding notes for %s: %w", wp, err)
+		}
+
+		for _, nf := range files {
+			file, err := doctor.LoadFile(nf.Path, wp, nf.Date, markers, tags)
+			if err != nil {
+				return fmt.Errorf("error reading %s: %w", relToNotes(nf.Path), err)
+			}
+			for _, owner := range owners[nf.Path] {
+				if owner != wp {
+					file.SharedWith = append(file.SharedWith, owner)
+				}
+			}
+			checked++
+
+			diagnostics := doctor.Check(file)
+			if doctorFix && canFix(diagnostics) {
+				note, err := workplaceParser.ParseFile(nf.Path)
+				if err != nil {
+					return fmt.Errorf("error parsing %s: %w", relToNotes(nf.Path), err)
+				}
+				if doctor.Fix(file, note, diagnostics) {
+					if err := workplaceWriter.WriteNote(note); err != nil {
+						return fmt.Errorf("error saving %s: %w", relToNotes(nf.Path), err)
+					}
+					fixed++
+
+					// Only report what is still wrong after the fix
+					if file, err = doctor.LoadFile(nf.Path, wp, nf.Date, markers, tags); err == nil {
+						diagnostics = doctor.Check(file)
+					}
+				}
+			}
+
+			all = append(all, diagnostics...)
+		}
+	}
+
+	if doctorJSON {
+		if all == nil {
+			all = []doctor.Diagnostic{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(all)
+	}
+
+	printDiagnostics(all, checked, fixed)
+	return nil
+}
+
+// canFix reports whether a note can be rewritten safely
+func canFix(diagnostics []doctor.Diagnostic) bool {
+	fixable := false
+	for _, d := range diagnostics {
+		if !d.Fixable && d.Severity == doctor.SeverityError {
+			return false
+		}
+		if d.Fixable {
+			fixable = true
+		}
+	}
+	return fixable
+}
+
+// printDiagnostics shows diagnostics grouped by file
+func printDiagnostics(diagnostics []doctor.Diagnostic, checked, fixed int) {
+	fmt.Println()
+	fmt.Println(ui.TitleStyle.Render("🩺 Worklog Doctor"))
+	fmt.Println(ui.RenderDivider(50))
+	fmt.Println()
+
+	errors, warnings, fixable := 0, 0, 0
+	lastFile := ""
+	for _, d := range diagnostics {
+		if d.File != lastFile {
+			if lastFile != "" {
+				fmt.Println()
+			}
+			fmt.Println(ui.InfoStyle.Render(relToNotes(d.File)))
+			lastFile = d.File
+		}
+
+		location := ui.MutedStyle.Render(fmt.Sprintf("  %4d", d.Line))
+		rule := ui.MutedStyle.Render("[" + d.Rule + "]")
+		switch d.Severity {
+		case doctor.SeverityError:
+			fmt.Printf("%s %s %s %s\n", location, ui.ErrorStyle.Render(ui.IconError), d.Message, rule)
+			errors++
+		default:
+			fmt.Printf("%s %s %s %s\n", location, ui.WarningStyle.Render(ui.IconWarning), d.Message, rule)
+			warnings++
+		}
+		if d.Fixable {
+			fixable++
+		}
+	}
+
+	if len(diagnostics) > 0 {
+		fmt.Println()
+		fmt.Println(ui.RenderDivider(50))
+	}
+
+	if fixed > 0 {
+		fmt.Println(ui.RenderSuccess(fmt.Sprintf("Repaired %d note(s)", fixed)))
+	}
+
+	if len(diagnostics) == 0 {
+		fmt.Println(ui.RenderSuccess(fmt.Sprintf("Checked %d note(s), no problems found", checked)))
+	} else {
+		fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("Checked %d note(s): %d error(s), %d warning(s)", checked, errors, warnings)))
+		if fixable > 0 && !doctorFix {
+			fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("💡 %d problem(s) can be repaired with 'worklog doctor --fix'", fixable)))
+		}
+	}
+	fmt.Println()
+}
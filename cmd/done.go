@@ -2,9 +2,8 @@ package cmd
 
 import (
 	"fmt"
-	"time"
 
-	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/hooks"
 	"github.com/sandepten/work-obsidian-noter/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -21,17 +20,16 @@ func init() {
 }
 
 func runDone(cmd *cobra.Command, args []string) error {
-	today := time.Now().Truncate(24 * time.Hour)
-
-	// Ask which workplace
-	selectedWorkplace, err := prompter.SelectWorkplace(cfg.Workplaces)
+	// Choose the workplace
+	selectedWorkplace, err := chooseWorkplace("")
 	if err != nil {
-		return fmt.Errorf("error selecting workplace: %w", err)
+		return err
 	}
+	today := todayFor(selectedWorkplace)
 
 	// Create parser and writer for the selected workplace
-	workplaceParser := notes.NewParser(cfg.WorkNotesLocation, selectedWorkplace)
-	workplaceWriter := notes.NewWriter(cfg.WorkNotesLocation, selectedWorkplace)
+	workplaceParser := newParser(selectedWorkplace)
+	workplaceWriter := newWriter(selectedWorkplace)
 
 	// Get today's note
 	todayNote, err := workplaceParser.FindTodayNote(today)
@@ -44,7 +42,8 @@ func runDone(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if !todayNote.HasPendingWork() {
+	active, indices := activeItems(todayNote)
+	if len(active) == 0 {
 		fmt.Println()
 		fmt.Println(ui.RenderSuccess(fmt.Sprintf("No pending items in %s — you're all caught up! 🎉", selectedWorkplace)))
 		fmt.Println()
@@ -57,10 +56,14 @@ func runDone(cmd *cobra.Command, args []string) error {
 	fmt.Println(ui.RenderDivider(50))
 	fmt.Println()
 
-	completedIndices, err := prompter.SelectPendingItems(todayNote.PendingWork)
+	selected, err := prompter.SelectPendingItems(active)
+	if cancelled(err) {
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("error selecting items: %w", err)
 	}
+	completedIndices := noteIndices(selected, indices)
 
 	if len(completedIndices) == 0 {
 		fmt.Println()
@@ -79,6 +82,9 @@ func runDone(cmd *cobra.Command, args []string) error {
 	if err := workplaceWriter.WriteNote(todayNote); err != nil {
 		return fmt.Errorf("error saving note: %w", err)
 	}
+	if err := emitNote(hooks.ItemCompleted, selectedWorkplace, todayNote, lastItems(todayNote.CompletedWork, len(completedIndices))); err != nil {
+		return err
+	}
 
 	fmt.Println()
 	fmt.Println(ui.RenderDivider(50))
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	editWorkplace string
+	editDate      string
+	editCompleted bool
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit [item] [new text]",
+	Short: "Edit work items inline or in your editor",
+	Long: `Edit the items of a note.
+
+With an item and new text, the item's text is replaced in place. Inline fields
+such as [due:: 2025-01-31] and indented sub-items are kept unless the new text
+brings its own fields.
+
+With only an item, the item opens in $VISUAL or $EDITOR. Without arguments the
+whole list of pending and completed items opens there; reorder lines, fix typos
+or tick checkboxes and save to apply.
+
+Items are chosen by number (as shown by 'worklog list') or by part of their text.
+
+Examples:
+  worklog edit 2 "Fix the login bug on Safari"
+  worklog edit login
+  worklog edit --completed 1
+  worklog edit --date yesterday`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runEdit,
+}
+
+func init() {
+	editCmd.Flags().StringVarP(&editWorkplace, "workplace", "w", "", "Workplace of the note")
+	editCmd.Flags().StringVarP(&editDate, "date", "d", "today", "Date of the note")
+	editCmd.Flags().BoolVarP(&editCompleted, "completed", "c", false, "Pick the item from completed work")
+	rootCmd.AddCommand(editCmd)
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	selectedWorkplace, err := chooseWorkplace(editWorkplace)
+	if err != nil {
+		return err
+	}
+
+	date, err := parseDateArg(editDate, todayFor(selectedWorkplace))
+	if err != nil {
+		return err
+	}
+
+	workplaceParser := newParser(selectedWorkplace)
+	workplaceWriter := newWriter(selectedWorkplace)
+
+	note, err := workplaceParser.FindTodayNote(date)
+	if err != nil {
+		return fmt.Errorf("error finding note: %w", err)
+	}
+	if note == nil {
+		prompter.DisplayWarning(fmt.Sprintf("No note found for %s in %s.", date.Format("Mon, Jan 2"), selectedWorkplace))
+		return nil
+	}
+
+	switch {
+	case len(args) == 0:
+		if err := editAllItems(note); err != nil {
+			return err
+		}
+	case len(args) == 1:
+		if err := editItemInEditor(note, args[0]); err != nil {
+			return err
+		}
+	default:
+		items, kind := sectionItems(note, editCompleted)
+		index, err := resolveItem(items, args[0])
+		if err != nil {
+			return err
+		}
+		if err := note.EditItem(kind, index, strings.Join(args[1:], " ")); err != nil {
+			return err
+		}
+	}
+
+	if err := workplaceWriter.WriteNote(note); err != nil {
+		return fmt.Errorf("error saving note: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Println(ui.RenderSuccess("Note updated!"))
+	prompter.DisplayWorkItems(note.PendingWork, note.CompletedWork)
+	return nil
+}
+
+// editItemInEditor opens a single item, with its sub-items, in the editor
+func editItemInEditor(note *notes.Note, ref string) error {
+	items, kind := sectionItems(note, editCompleted)
+	index, err := resolveItem(items, ref)
+	if err != nil {
+		return err
+	}
+
+	edited, err := editText("worklog-item-*.md", notes.FormatItem(items[index]))
+	if err != nil {
+		return err
+	}
+
+	parsed, err := notes.ParseItems(strings.NewReader(edited))
+	if err != nil {
+		return err
+	}
+	switch len(parsed) {
+	case 0:
+		return fmt.Errorf("no item left after editing; use 'worklog delete' to remove items")
+	case 1:
+	default:
+		return fmt.Errorf("edit one item at a time, or run 'worklog edit' without arguments to edit the whole list")
+	}
+
+	// The item stays in its list; tick it with 'worklog done' instead
+	parsed[0].Completed = kind == notes.SectionCompleted
+	items[index] = parsed[0]
+	return nil
+}
+
+// editAllItems opens every item of the note in the editor
+func editAllItems(note *notes.Note) error {
+	var sb strings.Builder
+	sb.WriteString("<!-- Edit, reorder or tick items, then save and close. Lines without a checkbox are ignored. -->\n\n")
+	for _, item := range note.PendingWork {
+		sb.WriteString(notes.FormatItem(item))
+	}
+	for _, item := range note.CompletedWork {
+		sb.WriteString(notes.FormatItem(item))
+	}
+
+	edited, err := editText("worklog-items-*.md", sb.String())
+	if err != nil {
+		return err
+	}
+
+	items, err := notes.ParseItems(strings.NewReader(edited))
+	if err != nil {
+		return err
+	}
+	note.SetItems(items)
+	return nil
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sandepten/work-obsidian-noter/internal/hooks"
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/timelog"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// pomodoroField is the inline field and note property counting pomodoros
+const pomodoroField = "pomodoros"
+
+var (
+	focusWorkplace string
+	focusWork      time.Duration
+	focusBreak     time.Duration
+	focusLongBreak time.Duration
+	focusCount     int
+)
+
+var focusCmd = &cobra.Command{
+	Use:   "focus <item>",
+	Short: "Run a Pomodoro timer on a pending item",
+	Long: `Run a Pomodoro timer on a pending item of today's note.
+
+Work and break phases alternate, with a long break after every few pomodoros.
+Focused time is logged like 'worklog track', and each completed pomodoro is
+counted in the item's [pomodoros:: N] field and in the note's "pomodoros"
+property. When the session ends you're offered to mark the item as done.
+
+Lengths default to the focus section of the config file: work_minutes (25),
+break_minutes (5), long_break_minutes (15) and long_break_every (4).
+
+Examples:
+  worklog focus 2
+  worklog focus "code review" -n 2
+  worklog focus 1 --work 50m --break 10m`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFocus,
+}
+
+func init() {
+	focusCmd.Flags().StringVarP(&focusWorkplace, "workplace", "w", "", "Workplace of the item")
+	focusCmd.Flags().DurationVar(&focusWork, "work", 0, "Length of a pomodoro (e.g. 25m)")
+	focusCmd.Flags().DurationVar(&focusBreak, "break", 0, "Length of a short break")
+	focusCmd.Flags().DurationVar(&focusLongBreak, "long-break", 0, "Length of a long break")
+	focusCmd.Flags().IntVarP(&focusCount, "count", "n", 0, "Stop after this many pomodoros (0 runs until you quit)")
+	rootCmd.AddCommand(focusCmd)
+}
+
+func runFocus(cmd *cobra.Command, args []string) error {
+	settings, err := cfg.Focus()
+	if err != nil {
+		return err
+	}
+	for _, flag := range []struct {
+		value  time.Duration
+		target *time.Duration
+	}{{focusWork, &settings.Work}, {focusBreak, &settings.ShortBreak}, {focusLongBreak, &settings.LongBreak}} {
+		if flag.value < 0 {
+			return fmt.Errorf("lengths must be positive")
+		}
+		if flag.value > 0 {
+			*flag.target = flag.value
+		}
+	}
+	if focusCount < 0 {
+		return fmt.Errorf("--count must not be negative")
+	}
+
+	selectedWorkplace, err := chooseWorkplace(focusWorkplace)
+	if err != nil {
+		return err
+	}
+
+	note, err := newParser(selectedWorkplace).FindTodayNote(todayFor(selectedWorkplace))
+	if err != nil {
+		return fmt.Errorf("error finding today's note: %w", err)
+	}
+	if note == nil {
+		prompter.DisplayWarning(fmt.Sprintf("No note found for today in %s. Use 'worklog start' to create one.", selectedWorkplace))
+		return nil
+	}
+
+	index, err := resolveItem(note.PendingWork, args[0])
+	if err != nil {
+		return err
+	}
+	item := note.PendingWork[index]
+	if !item.Active() {
+		return fmt.Errorf("\"%s\" is %s; only open or blocked items can be focused on", item.Title(), item.State)
+	}
+
+	log := timelog.Open(cfg.TimeLogPath())
+	// Stop a running timer here so its time is noted before the screen is taken over
+	stopped, err := log.Stop(time.Now())
+	if err != nil {
+		return fmt.Errorf("error writing time log: %w", err)
+	}
+	if stopped != nil {
+		fmt.Println()
+		if err := recordTrackedTime(stopped); err != nil {
+			return err
+		}
+	}
+
+	result, err := prompter.Focus(ui.FocusSession{
+		Item:           item.Title(),
+		Work:           settings.Work,
+		ShortBreak:     settings.ShortBreak,
+		LongBreak:      settings.LongBreak,
+		LongBreakEvery: settings.LongBreakEvery,
+		Target:         focusCount,
+		OnWorkStart: func(at time.Time) error {
+			_, err := log.Start(selectedWorkplace, item.Title(), item.Tags(), at)
+			return err
+		},
+		OnWorkEnd: func(start, end time.Time, completed bool) error {
+			entry, err := log.Stop(end)
+			if err != nil || entry == nil {
+				return err
+			}
+			_, err = updateTrackedItem(entry, func(note *notes.Note, item *notes.WorkItem) {
+				addTrackedTime(item, entry.Duration(end))
+				if completed {
+					addPomodoro(note, item)
+				}
+			})
+			return err
+		},
+	})
+	if errors.Is(err, ui.ErrNoTerminal) {
+		return fmt.Errorf("%w; use 'worklog track' to time items without one", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Focused on \"%s\" for %s · %d pomodoros", item.Title(), timelog.FormatDuration(result.Focused), result.Pomodoros)))
+	fmt.Println()
+
+	if result.Pomodoros == 0 {
+		return nil
+	}
+	done, err := prompter.ConfirmAction(ui.Tf(ui.MsgFocusMarkDone, item.Title()))
+	if err != nil || !done {
+		return err
+	}
+	return completeByTitle(selectedWorkplace, item.Title())
+}
+
+// addPomodoro counts a completed pomodoro on an item and in its note
+func addPomodoro(note *notes.Note, item *notes.WorkItem) {
+	value, _ := item.Field(pomodoroField)
+	n, _ := strconv.Atoi(value)
+	item.SetField(pomodoroField, strconv.Itoa(n+1))
+
+	value, _ = note.FrontmatterValue(pomodoroField)
+	n, _ = strconv.Atoi(value)
+	note.SetFrontmatterValue(pomodoroField, strconv.Itoa(n+1))
+}
+
+// completeByTitle marks the pending item with the given title in today's note as done
+func completeByTitle(workplace, title string) error {
+	note, err := newParser(workplace).FindTodayNote(todayFor(workplace))
+	if err != nil {
+		return fmt.Errorf("error finding today's note: %w", err)
+	}
+	index := -1
+	if note != nil {
+		index = note.FindPendingItem(title)
+	}
+	if index < 0 {
+		prompter.DisplayWarning(fmt.Sprintf("\"%s\" is no longer pending in today's note.", title))
+		return nil
+	}
+
+	note.MarkItemCompleted(index)
+	if err := newWriter(workplace).WriteNote(note); err != nil {
+		return fmt.Errorf("error saving note: %w", err)
+	}
+	if err := emitNote(hooks.ItemCompleted, workplace, note, lastItems(note.CompletedWork, 1)); err != nil {
+		return err
+	}
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Marked \"%s\" as done", title)))
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sandepten/work-obsidian-noter/internal/config"
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/recurring"
+	"github.com/sandepten/work-obsidian-noter/internal/summarizer"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+)
+
+// cancelled reports whether err is the user cancelling a checklist, and if so
+// tells them nothing was changed
+func cancelled(err error) bool {
+	if !errors.Is(err, ui.ErrCancelled) {
+		return false
+	}
+	fmt.Println()
+	fmt.Println(ui.MutedStyle.Render("Cancelled"))
+	fmt.Println()
+	return true
+}
+
+// layoutFor returns the note path layout configured for a workplace
+func layoutFor(workplace string) notes.Layout {
+	layout, err := notes.ParseLayout(cfg.NoteLayoutFor(workplace))
+	if err != nil {
+		// Layouts are validated in initConfig, so this only guards against misuse
+		return notes.DefaultLayout
+	}
+	return layout
+}
+
+// markersFor returns the note markers configured for a workplace
+func markersFor(workplace string) notes.Markers {
+	mc := cfg.MarkersFor(workplace)
+	localeMarkers := notes.MarkersForLocale(mc.Locale)
+
+	markers := localeMarkers
+	if len(mc.Pending) > 0 {
+		markers.Pending = mc.Pending
+	}
+	if len(mc.Completed) > 0 {
+		markers.Completed = mc.Completed
+	}
+	if len(mc.Summary) > 0 {
+		markers.Summary = mc.Summary
+	}
+	if len(mc.YesterdaySummary) > 0 {
+		markers.YesterdaySummary = mc.YesterdaySummary
+	}
+
+	// Keep reading notes written before the markers or locale were changed
+	return markers.WithAliases(localeMarkers).WithAliases(notes.DefaultMarkers)
+}
+
+// templateFor loads the note template configured for a workplace, or nil if none is set
+func templateFor(workplace string) (*notes.Template, error) {
+	path := cfg.NoteTemplateFor(workplace)
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading note template: %w", err)
+	}
+
+	return notes.ParseTemplate(filepath.Base(path), string(content))
+}
+
+// validateWorkplaceSettings checks the note settings of a workplace
+func validateWorkplaceSettings(workplace string) error {
+	if _, err := notes.ParseLayout(cfg.NoteLayoutFor(workplace)); err != nil {
+		return fmt.Errorf("note layout: %w", err)
+	}
+
+	if locale := cfg.MarkersFor(workplace).Locale; !notes.SupportedLocale(locale) {
+		return fmt.Errorf("unsupported locale %q", locale)
+	}
+
+	markers := markersFor(workplace)
+	for _, pending := range markers.Pending {
+		for _, completed := range markers.Completed {
+			if pending == completed {
+				return fmt.Errorf("%q cannot be both a pending and a completed heading", pending)
+			}
+		}
+	}
+
+	if _, err := cfg.LocationFor(workplace); err != nil {
+		return err
+	}
+
+	if _, err := templateFor(workplace); err != nil {
+		return err
+	}
+
+	if workplace != "" {
+		if _, other := sharedNotePaths([]string{workplace}, layoutFor(workplace)); other != "" {
+			return fmt.Errorf("notes would be written to the same files as %s's; add {workplace} to the note layout or use another notes folder", other)
+		}
+	}
+
+	return nil
+}
+
+// sharedNotePaths looks for a workplace whose notes would resolve to the same
+// files as those of one of workplaces once they use layout, and returns both,
+// or empty strings if there is none. Layouts that include the workplace name
+// never share files.
+func sharedNotePaths(workplaces []string, layout notes.Layout) (string, string) {
+	if layout.PerWorkplace() {
+		return "", ""
+	}
+
+	for _, wp := range workplaces {
+		dir := filepath.Clean(cfg.NotesDirFor(wp))
+		for _, other := range cfg.AllWorkplaces() {
+			if other == wp || filepath.Clean(cfg.NotesDirFor(other)) != dir {
+				continue
+			}
+			if contains(workplaces, other) || layoutFor(other).String() == layout.String() {
+				return wp, other
+			}
+		}
+	}
+	return "", ""
+}
+
+// newParser creates a note parser for the given workplace
+func newParser(workplace string) *notes.Parser {
+	p := notes.NewParser(cfg.NotesDirFor(workplace), workplace)
+	p.SetLayout(layoutFor(workplace))
+	p.SetMarkers(markersFor(workplace))
+	return p
+}
+
+// newWriter creates a note writer for the given workplace
+func newWriter(workplace string) *notes.Writer {
+	w := notes.NewWriter(cfg.NotesDirFor(workplace), workplace)
+	w.SetLayout(layoutFor(workplace))
+	w.SetMarkers(markersFor(workplace))
+	w.SetTags(cfg.NoteTagsFor(workplace))
+	// Templates are validated in initConfig
+	if template, err := templateFor(workplace); err == nil {
+		w.SetTemplate(template)
+	}
+	// Broken recurring files are reported in initConfig
+	if tasks, err := recurring.Load(cfg.RecurringFileFor(workplace)); err == nil {
+		w.SetRecurring(tasks)
+	}
+	w.SetLedger(cfg.ScheduledFileFor(workplace))
+	return w
+}
+
+// relToNotes returns a path relative to its notes directory for display
+func relToNotes(path string) string {
+	dirs := []string{cfg.WorkNotesLocation}
+	for _, wp := range cfg.AllWorkplaces() {
+		dirs = append(dirs, cfg.NotesDirFor(wp))
+	}
+	for _, dir := range dirs {
+		if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return path
+}
+
+// aiClientFor creates a summarizer with the AI settings of a workplace: its
+// summarizer plugin if it has one, or else the OpenCode server
+func aiClientFor(workplace string) summarizer.Summarizer {
+	ai := cfg.AIFor(workplace)
+	if ai.Summarizer != "" {
+		plugin := summarizer.NewPlugin(ai.Summarizer)
+		plugin.SetPrompt(ai.Prompt)
+		return plugin
+	}
+	client := summarizer.NewClient(ai.Server, ai.Provider, ai.Model)
+	client.SetPrompt(ai.Prompt)
+	return client
+}
+
+// isWorkplace reports whether a workplace is configured
+func isWorkplace(name string) bool {
+	for _, wp := range cfg.Workplaces {
+		if wp == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isArchived reports whether a workplace is archived
+func isArchived(name string) bool {
+	return contains(cfg.Archived, name)
+}
+
+// contains reports whether a list holds a value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// workplaceEnv picks the workplace of every command it is set for
+const workplaceEnv = "WORKLOG_WORKPLACE"
+
+// currentWorkplace returns the workplace commands use without asking, and
+// where it was picked: the WORKLOG_WORKPLACE variable, 'worklog use' in this
+// terminal session, a .worklog file in the current folder or above, or the
+// default_workplace setting, in that order. It returns "" when none is set.
+func currentWorkplace() (string, string, error) {
+	check := func(workplace, source string) (string, string, error) {
+		if !isWorkplace(workplace) {
+			if isArchived(workplace) {
+				return "", "", fmt.Errorf("workplace '%s' from %s is archived", workplace, source)
+			}
+			return "", "", fmt.Errorf("workplace '%s' from %s not found", workplace, source)
+		}
+		return workplace, source, nil
+	}
+
+	if workplace := strings.TrimSpace(os.Getenv(workplaceEnv)); workplace != "" {
+		return check(workplace, "$"+workplaceEnv)
+	}
+	if workplace := cfg.SessionWorkplace(); workplace != "" && isWorkplace(workplace) {
+		return workplace, "'worklog use'", nil
+	}
+	if dir, err := os.Getwd(); err == nil {
+		workplace, path, err := config.FindMarker(dir)
+		if err != nil {
+			return "", "", fmt.Errorf("error reading %s: %w", config.MarkerFile, err)
+		}
+		if workplace != "" {
+			return check(workplace, path)
+		}
+	}
+	if cfg.DefaultWorkplace != "" {
+		return check(cfg.DefaultWorkplace, "default_workplace")
+	}
+	return "", "", nil
+}
+
+// chooseWorkplace returns the workplace given by a flag or picked by
+// currentWorkplace, or asks for one
+func chooseWorkplace(flag string) (string, error) {
+	if flag != "" {
+		if !isWorkplace(flag) {
+			return "", fmt.Errorf("workplace '%s' not found", flag)
+		}
+		return flag, nil
+	}
+
+	workplace, _, err := currentWorkplace()
+	if err != nil || workplace != "" {
+		return workplace, err
+	}
+
+	workplace, err = prompter.SelectWorkplace(cfg.Workplaces)
+	if err != nil {
+		return "", fmt.Errorf("error selecting workplace: %w", err)
+	}
+	return workplace, nil
+}
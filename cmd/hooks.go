@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/sandepten/work-obsidian-noter/internal/hooks"
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var hooksWorkplace string
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "List the hooks run on worklog events",
+	Long: `List the hooks of the config file: executables run when something happens,
+with the event as JSON on stdin.
+
+Events: ` + strings.Join(hooks.Events, ", ") + `
+
+Hooks run one after the other in the order they are listed. Each may run
+for its timeout (10s by default) before it is stopped. When a hook fails,
+on_failure decides what happens: warn (the default) reports it, ignore
+carries on silently, and abort skips the remaining hooks and makes the
+command fail.
+
+  hooks:
+    - event: item.completed
+      run: ~/bin/ping-status
+      args: [--quiet]
+      timeout: 5s
+      on_failure: warn
+      workplaces: [Acme]`,
+	Args: cobra.NoArgs,
+	RunE: runHooksList,
+}
+
+var hooksTestCmd = &cobra.Command{
+	Use:   "test <event>",
+	Short: "Run the hooks of an event with a sample payload",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHooksTest,
+}
+
+func init() {
+	hooksTestCmd.Flags().StringVarP(&hooksWorkplace, "workplace", "w", "", "Workplace of the sample event")
+	hooksCmd.AddCommand(hooksTestCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
+
+func runHooksList(cmd *cobra.Command, args []string) error {
+	fmt.Println()
+	found := false
+	for _, event := range hooks.Events {
+		list := hookRunner.Hooks(event, "")
+		if len(list) == 0 {
+			continue
+		}
+		found = true
+		fmt.Println(ui.HeaderStyle.Render(event))
+		for i, hook := range list {
+			line := fmt.Sprintf("  %d. %s", i+1, strings.Join(append([]string{hook.Command}, hook.Args...), " "))
+			details := fmt.Sprintf("%s, on failure %s", hook.Timeout, hook.OnFailure)
+			if len(hook.Workplaces) > 0 {
+				details += ", only " + strings.Join(hook.Workplaces, ", ")
+			}
+			fmt.Printf("%s  %s\n", line, ui.MutedStyle.Render(details))
+			if _, err := exec.LookPath(hook.Command); err != nil {
+				fmt.Println(ui.RenderWarning(fmt.Sprintf("     %s can't be run: %v", hook.Command, err)))
+			}
+		}
+		fmt.Println()
+	}
+	if !found {
+		fmt.Println(ui.MutedStyle.Render("No hooks configured. Add them under hooks: in the config file (see 'worklog hooks --help')."))
+		fmt.Println()
+	}
+	return nil
+}
+
+func runHooksTest(cmd *cobra.Command, args []string) error {
+	event := args[0]
+	workplace, err := chooseWorkplace(hooksWorkplace)
+	if err != nil {
+		return err
+	}
+	list := hookRunner.Hooks(event, workplace)
+	if len(list) == 0 {
+		return fmt.Errorf("no hooks run on %s for %s", event, workplace)
+	}
+
+	sample := hooks.Event{Event: event, Workplace: workplace, Date: todayFor(workplace).Format("2006-01-02")}
+	switch event {
+	case hooks.WorkplaceRenamed:
+		sample.OldName, sample.NewName = workplace, workplace
+	case hooks.SummaryGenerated:
+		sample.Summary = "Sample summary from 'worklog hooks test'."
+	case hooks.ItemAdded, hooks.ItemCompleted, hooks.ItemDeleted:
+		sample.Items = hooks.ItemsOf([]notes.WorkItem{{Text: "Sample item from 'worklog hooks test'", Completed: event == hooks.ItemCompleted}})
+	}
+	if err := emit(sample); err != nil {
+		return err
+	}
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Ran %d hook(s) on %s", len(list), event)))
+	return nil
+}
+
+// emit runs the hooks of an event. Only a failing hook with on_failure: abort
+// returns an error; other failures are reported as warnings.
+func emit(event hooks.Event) error {
+	if hookRunner == nil {
+		return nil
+	}
+	event.Profile = cfg.Profile
+	return hookRunner.Run(event)
+}
+
+// lastItems returns the last n items of a section, which are those just
+// added or completed
+func lastItems(items []notes.WorkItem, n int) []notes.WorkItem {
+	return items[len(items)-n:]
+}
+
+// emitNote runs the hooks of an event about items of a note
+func emitNote(event, workplace string, note *notes.Note, items []notes.WorkItem) error {
+	e := hooks.Event{Event: event, Workplace: workplace}
+	if note != nil {
+		e.Note = note.FilePath
+		e.Date = note.Date.Format("2006-01-02")
+	}
+	if len(items) > 0 {
+		e.Items = hooks.ItemsOf(items)
+	}
+	return emit(e)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+)
+
+// resolveItem finds an item by its 1-based number or by (part of) its text
+func resolveItem(items []notes.WorkItem, ref string) (int, error) {
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(items) {
+			return -1, fmt.Errorf("item %d does not exist (the list has %d item(s))", n, len(items))
+		}
+		return n - 1, nil
+	}
+
+	needle := strings.ToLower(strings.TrimSpace(ref))
+	var matches []int
+	for i, item := range items {
+		text := strings.ToLower(item.Text)
+		if text == needle {
+			return i, nil
+		}
+		if strings.Contains(text, needle) {
+			matches = append(matches, i)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return -1, fmt.Errorf("no item matches %q", ref)
+	case 1:
+		return matches[0], nil
+	}
+
+	var candidates []string
+	for _, i := range matches {
+		candidates = append(candidates, fmt.Sprintf("  %d. %s", i+1, items[i].Text))
+	}
+	return -1, fmt.Errorf("%q matches several items, use its number:\n%s", ref, strings.Join(candidates, "\n"))
+}
+
+// sectionItems returns the pending or completed items of a note
+func sectionItems(note *notes.Note, completed bool) ([]notes.WorkItem, notes.SectionKind) {
+	if completed {
+		return note.CompletedWork, notes.SectionCompleted
+	}
+	return note.PendingWork, notes.SectionPending
+}
+
+// activeItems returns the pending items that still need doing, with their
+// indices in the note's pending list
+func activeItems(note *notes.Note) ([]notes.WorkItem, []int) {
+	indices := note.ActivePending()
+	items := make([]notes.WorkItem, len(indices))
+	for i, idx := range indices {
+		items[i] = note.PendingWork[idx]
+	}
+	return items, indices
+}
+
+// noteIndices maps positions in a list from activeItems back to pending indices
+func noteIndices(positions, indices []int) []int {
+	mapped := make([]int, len(positions))
+	for i, pos := range positions {
+		mapped[i] = indices[pos]
+	}
+	return mapped
+}
+
+// countItems returns the number of items matching a predicate
+func countItems(items []notes.WorkItem, match func(notes.WorkItem) bool) int {
+	n := 0
+	for _, item := range items {
+		if match(item) {
+			n++
+		}
+	}
+	return n
+}
+
+// editorCommand returns the user's editor, from $VISUAL or $EDITOR
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// openInEditor opens a file in the user's editor and waits for it to close
+func openInEditor(path string) error {
+	editor := editorCommand()
+	c := exec.Command(editor[0], append(editor[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("error running %s: %w", editor[0], err)
+	}
+	return nil
+}
+
+// editText lets the user edit text in their editor and returns the result
+func editText(pattern, text string) (string, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	if err := openInEditor(file.Name()); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(edited), nil
+}
@@ -5,54 +5,104 @@ import (
 	"time"
 
 	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/timelog"
 	"github.com/sandepten/work-obsidian-noter/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
 	pendingOnly bool
+	listAll     bool
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List today's work items",
-	Long:  `Display all pending and completed work items from today's note. You will be prompted to select a workplace if multiple are configured.`,
-	RunE:  runList,
+	Long: `Display all pending and completed work items from today's note. You will be prompted to select a workplace if multiple are configured.
+
+With --all, today's items of every workplace are shown, one workplace after the other.`,
+	RunE: runList,
 }
 
 func init() {
 	listCmd.Flags().BoolVarP(&pendingOnly, "pending", "p", false, "Show only pending tasks")
+	listCmd.Flags().BoolVarP(&listAll, "all", "a", false, "Show the items of every workplace")
 	rootCmd.AddCommand(listCmd)
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	today := time.Now().Truncate(24 * time.Hour)
+	workplaces := cfg.Workplaces
+	if !listAll {
+		// Choose the workplace
+		selectedWorkplace, err := chooseWorkplace("")
+		if err != nil {
+			return err
+		}
+		workplaces = []string{selectedWorkplace}
+	}
 
-	// Ask which workplace
-	selectedWorkplace, err := prompter.SelectWorkplace(cfg.Workplaces)
-	if err != nil {
-		return fmt.Errorf("error selecting workplace: %w", err)
+	found := false
+	for i, wp := range workplaces {
+		if i > 0 {
+			fmt.Println(ui.RenderDivider(50))
+		}
+		ok, err := listWorkplace(wp, listAll)
+		if err != nil {
+			return err
+		}
+		found = found || ok
 	}
 
+	// Show tip at the end
+	if found {
+		fmt.Println(ui.MutedStyle.Render("💡 Use 'worklog add \"task\"' to add items"))
+	}
+
+	return nil
+}
+
+// listWorkplace shows today's items of a workplace and reports whether it has
+// a note for today. A missing note is only mentioned briefly when brief is set.
+func listWorkplace(selectedWorkplace string, brief bool) (bool, error) {
+	today := todayFor(selectedWorkplace)
+
 	// Create parser for the selected workplace
-	workplaceParser := notes.NewParser(cfg.WorkNotesLocation, selectedWorkplace)
+	workplaceParser := newParser(selectedWorkplace)
 
 	// Get today's note
 	todayNote, err := workplaceParser.FindTodayNote(today)
 	if err != nil {
-		return fmt.Errorf("error finding today's note: %w", err)
+		return false, fmt.Errorf("error finding today's note: %w", err)
 	}
 
 	if todayNote == nil {
+		if brief {
+			fmt.Printf("%s  %s\n", ui.InfoStyle.Render(selectedWorkplace), ui.MutedStyle.Render("No note for today"))
+			fmt.Println()
+			return false, nil
+		}
 		prompter.DisplayWarning(fmt.Sprintf("No note found for today in %s. Use 'worklog start' to create one.", selectedWorkplace))
-		return nil
+		return false, nil
 	}
-
 	// Display date header with stats inline
 	dateStr := today.Format("Mon, Jan 2")
-	statsStr := fmt.Sprintf("%d pending · %d done", len(todayNote.PendingWork), len(todayNote.CompletedWork))
+	statsStr := fmt.Sprintf("%d pending · %d done", len(todayNote.ActivePending()), len(todayNote.DoneWork()))
+	if blocked := countItems(todayNote.PendingWork, notes.WorkItem.Blocked); blocked > 0 {
+		statsStr += fmt.Sprintf(" · %d blocked", blocked)
+	}
+	if cancelled := countItems(todayNote.PendingWork, notes.WorkItem.Cancelled); cancelled > 0 {
+		statsStr += fmt.Sprintf(" · %d cancelled", cancelled)
+	}
+	if pomodoros, ok := todayNote.FrontmatterValue(pomodoroField); ok {
+		statsStr += fmt.Sprintf(" · 🍅 %s", pomodoros)
+	}
 	fmt.Printf("%s  %s  %s\n", ui.TitleStyle.Render("📅 "+dateStr), ui.MutedStyle.Render("•"), ui.InfoStyle.Render(selectedWorkplace))
 	fmt.Println(ui.MutedStyle.Render(statsStr))
+	if entries, err := timelog.Open(cfg.TimeLogPath()).Entries(); err == nil {
+		if line := runningTimerLine(entries, selectedWorkplace, time.Now()); line != "" {
+			fmt.Println(line)
+		}
+	}
 
 	// Show yesterday's summary only if NOT using --pending flag
 	if !pendingOnly && todayNote.YesterdaySummary != "" {
@@ -66,8 +116,5 @@ func runList(cmd *cobra.Command, args []string) error {
 		prompter.DisplayWorkItems(todayNote.PendingWork, todayNote.CompletedWork)
 	}
 
-	// Show tip at the end
-	fmt.Println(ui.MutedStyle.Render("💡 Use 'worklog add \"task\"' to add items"))
-
-	return nil
+	return true, nil
 }
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/sandepten/work-obsidian-noter/internal/config"
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateFrom      string
+	migrateTo        string
+	migrateWorkplace string
+	migrateDryRun    bool
+)
+
+var migrateLayoutCmd = &cobra.Command{
+	Use:   "migrate-layout",
+	Short: "Move existing notes to a new path layout",
+	Long: `Move existing notes from one path layout to another.
+
+Layouts are paths relative to the notes directory built from tokens such as
+{YYYY}, {MM}, {DD}, {MMM}, {ddd}, {WW} (ISO week), {GGGG} (ISO week-year)
+and {workplace}. For example:
+
+  {YYYY}-{MM}-{DD}-{workplace}.md              (default)
+  Work/{workplace}/{YYYY}/{MM}/{YYYY}-{MM}-{DD}.md
+  {workplace}/{GGGG}/W{WW}/{YYYY}-{MM}-{DD}.md
+
+All moves are checked before anything is touched, and if any move fails the
+ones already made are rolled back. By default every workplace is migrated
+from its currently configured layout.`,
+	RunE: runMigrateLayout,
+}
+
+func init() {
+	migrateLayoutCmd.Flags().StringVar(&migrateTo, "to", "", "Layout to move notes to (required)")
+	migrateLayoutCmd.Flags().StringVar(&migrateFrom, "from", "", "Layout notes are currently stored in (defaults to the configured layout)")
+	migrateLayoutCmd.Flags().StringVarP(&migrateWorkplace, "workplace", "w", "", "Only migrate this workplace")
+	migrateLayoutCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show the planned moves without changing anything")
+	migrateLayoutCmd.MarkFlagRequired("to")
+	rootCmd.AddCommand(migrateLayoutCmd)
+}
+
+func runMigrateLayout(cmd *cobra.Command, args []string) error {
+	toLayout, err := notes.ParseLayout(migrateTo)
+	if err != nil {
+		return fmt.Errorf("invalid --to layout: %w", err)
+	}
+
+	var fromLayout *notes.Layout
+	if migrateFrom != "" {
+		layout, err := notes.ParseLayout(migrateFrom)
+		if err != nil {
+			return fmt.Errorf("invalid --from layout: %w", err)
+		}
+		fromLayout = &layout
+	}
+
+	workplaces := cfg.Workplaces
+	if migrateWorkplace != "" {
+		if !isWorkplace(migrateWorkplace) {
+			return fmt.Errorf("workplace '%s' not found", migrateWorkplace)
+		}
+		workplaces = []string{migrateWorkplace}
+	}
+	if wp, other := sharedNotePaths(workplaces, toLayout); other != "" {
+		return fmt.Errorf("%s and %s share a notes folder, so the new layout needs {workplace} or {workplace_lower}", wp, other)
+	}
+
+	fmt.Println()
+	fmt.Println(ui.TitleStyle.Render("🗂️  Migrate Note Layout"))
+	fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("New layout: %s", toLayout)))
+	fmt.Println(ui.RenderDivider(50))
+	fmt.Println()
+
+	// Plan every move before touching the filesystem
+	tx := notes.NewTransaction()
+	for _, wp := range workplaces {
+		from := layoutFor(wp)
+		if fromLayout != nil {
+			from = *fromLayout
+		}
+
+		files, err := notes.FindNoteFiles(cfg.NotesDirFor(wp), wp, from)
+		if err != nil {
+			return fmt.Errorf("error finding notes for %s: %w", wp, err)
+		}
+
+		for _, file := range files {
+			newPath := filepath.Join(cfg.NotesDirFor(wp), toLayout.Path(file.Date, wp))
+			if filepath.Clean(newPath) == filepath.Clean(file.Path) {
+				continue
+			}
+			tx.Move(file.Path, newPath)
+		}
+	}
+
+	moves := tx.Moves()
+	if len(moves) == 0 {
+		fmt.Println(ui.MutedStyle.Render("No notes need to be moved."))
+		fmt.Println()
+		return nil
+	}
+
+	for _, m := range moves {
+		fmt.Printf("  %s %s %s\n", relToNotes(m.From), ui.MutedStyle.Render(ui.IconArrow), ui.InfoStyle.Render(relToNotes(m.To)))
+	}
+	fmt.Println()
+
+	if err := tx.Validate(); err != nil {
+		return fmt.Errorf("cannot migrate: %w", err)
+	}
+
+	if migrateDryRun {
+		fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("Dry run: %d note(s) would be moved.", len(moves))))
+		fmt.Println()
+		return nil
+	}
+
+	confirmed, err := prompter.ConfirmAction(fmt.Sprintf("Move %d note(s)", len(moves)))
+	if err != nil {
+		return fmt.Errorf("error confirming action: %w", err)
+	}
+	if !confirmed {
+		fmt.Println(ui.RenderWarning("Cancelled"))
+		return nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migration failed, no notes were moved: %w", err)
+	}
+	for _, wp := range workplaces {
+		tx.PruneEmptyDirs(cfg.NotesDirFor(wp))
+	}
+
+	fmt.Println()
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Moved %d note(s)", len(moves))))
+
+	// Point the config at the new layout so worklog finds the moved notes
+	update, err := prompter.ConfirmAction("Use the new layout in your config")
+	if err != nil {
+		return fmt.Errorf("error confirming action: %w", err)
+	}
+	if update {
+		if err := saveMigratedLayout(workplaces, toLayout.String()); err != nil {
+			return fmt.Errorf("failed to update config: %w", err)
+		}
+		fmt.Println(ui.RenderSuccess("Config updated"))
+	} else {
+		fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("  Remember to set layout: %s in %s", toLayout, config.GetConfigPath())))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// saveMigratedLayout stores the new layout globally or for the migrated workplace
+func saveMigratedLayout(workplaces []string, layout string) error {
+	if migrateWorkplace != "" {
+		return cfg.SetNoteLayout(migrateWorkplace, layout)
+	}
+
+	// Workplaces with their own layout need their override updated as well
+	for _, wp := range workplaces {
+		if cfg.NoteLayoutFor(wp) != cfg.NoteLayout {
+			if err := cfg.SetNoteLayout(wp, layout); err != nil {
+				return err
+			}
+		}
+	}
+	return cfg.SetNoteLayout("", layout)
+}
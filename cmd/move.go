@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sandepten/work-obsidian-noter/internal/hooks"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	moveUp            bool
+	moveDown          bool
+	moveTo            int
+	moveWorkplace     string
+	moveDate          string
+	moveFromWorkplace string
+	moveFromDate      string
+	moveCompleted     bool
+)
+
+var moveCmd = &cobra.Command{
+	Use:   "move <item>",
+	Short: "Reorder an item or move it to another workplace or day",
+	Long: `Move a work item within its list, or to the note of another workplace or day.
+
+Items are chosen by number (as shown by 'worklog list') or by part of their
+text. Inline fields and sub-items move with the item.
+
+Examples:
+  worklog move 3 --up
+  worklog move "write docs" --to 1
+  worklog move 2 --date tomorrow
+  worklog move deploy --workplace Personal
+  worklog move 1 --from-date yesterday --date today`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMove,
+}
+
+func init() {
+	moveCmd.Flags().BoolVar(&moveUp, "up", false, "Move the item up one place")
+	moveCmd.Flags().BoolVar(&moveDown, "down", false, "Move the item down one place")
+	moveCmd.Flags().IntVar(&moveTo, "to", 0, "Move the item to this position")
+	moveCmd.Flags().StringVarP(&moveWorkplace, "workplace", "w", "", "Move the item to this workplace")
+	moveCmd.Flags().StringVarP(&moveDate, "date", "d", "", "Move the item to the note of this date")
+	moveCmd.Flags().StringVar(&moveFromWorkplace, "from-workplace", "", "Workplace the item is in")
+	moveCmd.Flags().StringVar(&moveFromDate, "from-date", "today", "Date of the note the item is in")
+	moveCmd.Flags().BoolVarP(&moveCompleted, "completed", "c", false, "Pick the item from completed work")
+	rootCmd.AddCommand(moveCmd)
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	reorders := 0
+	for _, set := range []bool{moveUp, moveDown, moveTo != 0} {
+		if set {
+			reorders++
+		}
+	}
+	transfer := moveWorkplace != "" || moveDate != ""
+
+	switch {
+	case reorders > 1:
+		return fmt.Errorf("use only one of --up, --down and --to")
+	case reorders == 1 && transfer:
+		return fmt.Errorf("--up, --down and --to can't be combined with --workplace or --date")
+	case reorders == 0 && !transfer:
+		return fmt.Errorf("say where to move the item with --up, --down, --to, --workplace or --date")
+	}
+
+	fromWorkplace, err := chooseWorkplace(moveFromWorkplace)
+	if err != nil {
+		return err
+	}
+
+	today := todayFor(fromWorkplace)
+	fromDate, err := parseDateArg(moveFromDate, today)
+	if err != nil {
+		return err
+	}
+
+	sourceWriter := newWriter(fromWorkplace)
+	source, err := newParser(fromWorkplace).FindTodayNote(fromDate)
+	if err != nil {
+		return fmt.Errorf("error finding note: %w", err)
+	}
+	if source == nil {
+		prompter.DisplayWarning(fmt.Sprintf("No note found for %s in %s.", fromDate.Format("Mon, Jan 2"), fromWorkplace))
+		return nil
+	}
+
+	items, kind := sectionItems(source, moveCompleted)
+	index, err := resolveItem(items, args[0])
+	if err != nil {
+		return err
+	}
+
+	if !transfer {
+		to := moveTo - 1
+		switch {
+		case moveUp:
+			to = index - 1
+		case moveDown:
+			to = index + 1
+		}
+		if err := source.MoveItem(kind, index, to); err != nil {
+			return err
+		}
+		if err := sourceWriter.WriteNote(source); err != nil {
+			return fmt.Errorf("error saving note: %w", err)
+		}
+
+		fmt.Println()
+		fmt.Println(ui.RenderSuccess(fmt.Sprintf("Moved item to position %d", to+1)))
+		prompter.DisplayWorkItems(source.PendingWork, source.CompletedWork)
+		return nil
+	}
+
+	toWorkplace := fromWorkplace
+	if moveWorkplace != "" {
+		if !isWorkplace(moveWorkplace) {
+			return fmt.Errorf("workplace '%s' not found", moveWorkplace)
+		}
+		toWorkplace = moveWorkplace
+	}
+	toDate := fromDate
+	if moveDate != "" {
+		if toDate, err = parseDateArg(moveDate, todayFor(toWorkplace)); err != nil {
+			return err
+		}
+	}
+	if toWorkplace == fromWorkplace && toDate.Equal(fromDate) {
+		return fmt.Errorf("the item is already in that note")
+	}
+
+	targetWriter := newWriter(toWorkplace)
+	target, err := newParser(toWorkplace).FindTodayNote(toDate)
+	if err != nil {
+		return fmt.Errorf("error finding target note: %w", err)
+	}
+	created := target == nil
+	if created {
+		if target, err = targetWriter.CreateTodayNote(toDate); err != nil {
+			return fmt.Errorf("error creating target note: %w", err)
+		}
+		fmt.Println(ui.InfoStyle.Render(fmt.Sprintf("Creating note for %s in %s...", toDate.Format("Mon, Jan 2"), toWorkplace)))
+	}
+
+	item, err := source.TakeItem(kind, index)
+	if err != nil {
+		return err
+	}
+	if err := target.PutItem(kind, item); err != nil {
+		return err
+	}
+
+	// Write the target first: a failure then leaves a duplicate, never a lost item
+	if err := targetWriter.WriteNote(target); err != nil {
+		return fmt.Errorf("error saving target note: %w", err)
+	}
+	if err := sourceWriter.WriteNote(source); err != nil {
+		return fmt.Errorf("error saving note (the item was copied to %s): %w", relToNotes(target.FilePath), err)
+	}
+	if created {
+		if err := emitNote(hooks.NoteCreated, toWorkplace, target, nil); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Moved \"%s\" to %s (%s)", item.Text, toWorkplace, toDate.Format("Mon, Jan 2"))))
+	fmt.Println()
+	return nil
+}
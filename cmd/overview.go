@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/timelog"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var overviewCmd = &cobra.Command{
+	Use:   "overview",
+	Short: "Show today's counts for every workplace",
+	Long: `Show one line per workplace with today's pending, done and blocked items,
+the deferred items waiting to come back and the time tracked today.
+
+Use 'worklog list --all' to see the items themselves.`,
+	Args: cobra.NoArgs,
+	RunE: runOverview,
+}
+
+func init() {
+	rootCmd.AddCommand(overviewCmd)
+}
+
+func runOverview(cmd *cobra.Command, args []string) error {
+	entries, err := timelog.Open(cfg.TimeLogPath()).Entries()
+	if err != nil {
+		return fmt.Errorf("error reading time log: %w", err)
+	}
+	now := time.Now()
+	today := todayAcross(cfg.Workplaces)
+	tracked := make(map[string]time.Duration)
+	for _, row := range timelog.NewReport(entries, today, today, now, timelog.ByWorkplace, nil).Rows {
+		tracked[row.Key] = row.Total
+	}
+
+	overview := statsTable().Headers("Workplace", "Pending", "Done", "Blocked", "Scheduled", "Tracked")
+	var pending, done, blocked, scheduled int
+	var total time.Duration
+	for _, wp := range cfg.Workplaces {
+		ledger, err := notes.LoadLedger(cfg.ScheduledFileFor(wp))
+		if err != nil {
+			return fmt.Errorf("error reading scheduled items of %s: %w", wp, err)
+		}
+		scheduled += len(ledger.Items)
+		total += tracked[wp]
+
+		todayNote, err := newParser(wp).FindTodayNote(todayFor(wp))
+		if err != nil {
+			return fmt.Errorf("error finding today's note for %s: %w", wp, err)
+		}
+		if todayNote == nil {
+			overview.Row(wp, "no note", "", "", countCell(len(ledger.Items)), durationCell(tracked[wp]))
+			continue
+		}
+
+		p := len(todayNote.ActivePending())
+		d := len(todayNote.DoneWork())
+		b := countItems(todayNote.PendingWork, notes.WorkItem.Blocked)
+		pending, done, blocked = pending+p, done+d, blocked+b
+		overview.Row(wp, countCell(p), countCell(d), countCell(b), countCell(len(ledger.Items)), durationCell(tracked[wp]))
+	}
+	if len(cfg.Workplaces) > 1 {
+		overview.Row("Total", countCell(pending), countCell(done), countCell(blocked), countCell(scheduled), durationCell(total))
+	}
+
+	fmt.Println()
+	fmt.Println(ui.TitleStyle.Render("📋 Overview · " + today.Format("Mon, Jan 2")))
+	fmt.Println(overview.Render())
+	if line := runningTimerLine(entries, "", now); line != "" {
+		fmt.Println(line)
+	}
+	fmt.Println(ui.MutedStyle.Render("💡 Use 'worklog list --all' to see the items"))
+	fmt.Println()
+	return nil
+}
+
+// countCell shows a count, with zero as a dot so the numbers stand out
+func countCell(n int) string {
+	if n == 0 {
+		return "·"
+	}
+	return strconv.Itoa(n)
+}
+
+// durationCell shows a tracked duration, with none as a dot
+func durationCell(d time.Duration) string {
+	if d <= 0 {
+		return "·"
+	}
+	return timelog.FormatDuration(d)
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/sandepten/work-obsidian-noter/internal/config"
+	"github.com/sandepten/work-obsidian-noter/internal/summarizer"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// pluginPrefix starts the names of executables on PATH that worklog runs as
+// subcommands, so worklog-standup is run by 'worklog standup'
+const pluginPrefix = "worklog-"
+
+// pluginBinEnv holds the path of the worklog executable for plugins to call back
+const pluginBinEnv = "WORKLOG_BIN"
+
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "List the plugins found on PATH",
+	Long: `List the plugins found on PATH.
+
+Any executable named worklog-<name> on PATH runs as 'worklog <name>', with
+the remaining arguments passed on, like git and kubectl plugins. Built-in
+commands win over plugins of the same name. A plugin is given the path of
+worklog in ` + pluginBinEnv + `, and --profile and --config as ` + config.ProfileEnv + ` and
+` + config.ConfigEnv + `, so it can call worklog back with the same setup.
+
+Executables named ` + summarizer.PluginPrefix + `<name> are summarizer plugins,
+used for summaries instead of the OpenCode server with:
+
+  worklog config set ai.summarizer <name>
+
+They read a JSON request with the completed items from stdin and write
+{"summary": "..."} to stdout. See the README for the protocol.`,
+	Args: cobra.NoArgs,
+	RunE: runPlugins,
+}
+
+func init() {
+	rootCmd.AddCommand(pluginsCmd)
+}
+
+// plugin is an executable found on PATH
+type plugin struct {
+	Name string // Name after the prefix
+	Path string
+}
+
+// findPlugins returns the executables on PATH whose names start with prefix,
+// sorted by name. Like the shell, the first one on PATH wins.
+func findPlugins(prefix string) []plugin {
+	seen := make(map[string]bool)
+	var found []plugin
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := pluginName(entry.Name(), prefix)
+			if name == "" || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if _, err := exec.LookPath(path); err != nil {
+				continue
+			}
+			seen[name] = true
+			found = append(found, plugin{Name: name, Path: path})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+	return found
+}
+
+// pluginName returns the name of a plugin executable after its prefix, or ""
+// when the file isn't one
+func pluginName(file, prefix string) string {
+	if !strings.HasPrefix(file, prefix) {
+		return ""
+	}
+	name := strings.TrimPrefix(file, prefix)
+	if ext := filepath.Ext(name); ext != "" && strings.EqualFold(ext, ".exe") {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+// isSummarizerPlugin reports whether a command plugin's name is that of a
+// summarizer plugin, which isn't run as a command
+func isSummarizerPlugin(name string) bool {
+	return strings.HasPrefix(pluginPrefix+name, summarizer.PluginPrefix)
+}
+
+func runPlugins(cmd *cobra.Command, args []string) error {
+	var commands []plugin
+	for _, p := range findPlugins(pluginPrefix) {
+		if !isSummarizerPlugin(p.Name) {
+			commands = append(commands, p)
+		}
+	}
+	summarizers := findPlugins(summarizer.PluginPrefix)
+
+	fmt.Println()
+	if len(commands) == 0 && len(summarizers) == 0 {
+		fmt.Println(ui.MutedStyle.Render("No plugins found. Put executables named worklog-<name> on PATH (see 'worklog plugins --help')."))
+		fmt.Println()
+		return nil
+	}
+
+	if len(commands) > 0 {
+		fmt.Println(ui.HeaderStyle.Render("Commands"))
+		for _, p := range commands {
+			fmt.Printf("  %-20s %s\n", p.Name, ui.MutedStyle.Render(p.Path))
+			if isBuiltinCommand(p.Name) {
+				fmt.Println(ui.RenderWarning(fmt.Sprintf("    Never run: the built-in '%s' command wins", p.Name)))
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(summarizers) > 0 {
+		fmt.Println(ui.HeaderStyle.Render("Summarizers"))
+		for _, p := range summarizers {
+			fmt.Printf("  %-20s %s\n", p.Name, ui.MutedStyle.Render(p.Path))
+		}
+		fmt.Println(ui.MutedStyle.Render("💡 Use one with 'worklog config set ai.summarizer <name>'"))
+		fmt.Println()
+	}
+	return nil
+}
+
+// isBuiltinCommand reports whether a command name belongs to worklog itself
+func isBuiltinCommand(name string) bool {
+	rootCmd.InitDefaultHelpCmd()
+	rootCmd.InitDefaultCompletionCmd()
+	found, _, err := rootCmd.Find([]string{name})
+	return err == nil && found != rootCmd
+}
+
+// runPluginCommand runs a plugin when the command line names one instead of a
+// built-in command. It reports whether it did, and the plugin's exit code.
+func runPluginCommand(args []string) (bool, int) {
+	// --profile and --config may come before the plugin's name
+	env := os.Environ()
+	for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(args[0], "--"), "=")
+		if name != "profile" && name != "config" {
+			return false, 0
+		}
+		if !hasValue {
+			if len(args) < 2 {
+				return false, 0
+			}
+			value, args = args[1], args[1:]
+		}
+		args = args[1:]
+
+		if name == "profile" {
+			env = append(env, config.ProfileEnv+"="+value)
+		} else {
+			env = append(env, config.ConfigEnv+"="+config.AbsPath(value))
+		}
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || isSummarizerPlugin(args[0]) || isBuiltinCommand(args[0]) {
+		return false, 0
+	}
+	path, err := exec.LookPath(pluginPrefix + args[0])
+	if err != nil {
+		return false, 0
+	}
+
+	if self, err := os.Executable(); err == nil {
+		env = append(env, pluginBinEnv+"="+self)
+	}
+	plugin := exec.Command(path, args[1:]...)
+	plugin.Stdin = os.Stdin
+	plugin.Stdout = os.Stdout
+	plugin.Stderr = os.Stderr
+	plugin.Env = env
+	if err := plugin.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if exitErr.ExitCode() >= 0 {
+				return true, exitErr.ExitCode()
+			}
+			// Killed by a signal, reported the way shells do
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+				return true, 128 + int(status.Signal())
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Error running plugin %s: %v\n", args[0], err)
+		return true, 1
+	}
+	return true, 0
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/sandepten/work-obsidian-noter/internal/config"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profileNotesDir  string
+	profileWorkplace string
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage profiles for separate vaults",
+	Long: `Manage profiles. Each profile has its own config file, and so its own notes
+folder, workplaces, AI settings and templates, along with its own recurring,
+scheduled and tracked items.
+
+Choose a profile for a command with --profile, or for a whole shell with
+WORKLOG_PROFILE. Without either, the default profile is used.
+
+Examples:
+  worklog profile create personal --notes-dir ~/vaults/personal/Journal
+  worklog --profile personal config set ai.provider anthropic
+  WORKLOG_PROFILE=personal worklog start`,
+	// Profiles are managed without loading one, so a missing profile can be created
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		prompter = ui.NewPrompter()
+	},
+	RunE: runProfileList,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the profiles",
+	Args:  cobra.NoArgs,
+	RunE:  runProfileList,
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a profile",
+	Long: `Create a profile with its own config file, keeping its notes in --notes-dir
+and starting with one workplace. Change its other settings with
+'worklog --profile <name> config set'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProfileCreate,
+}
+
+func init() {
+	profileCreateCmd.Flags().StringVar(&profileNotesDir, "notes-dir", "", "Folder the profile's notes are kept in")
+	profileCreateCmd.Flags().StringVar(&profileWorkplace, "workplace", "Work", "Name of the profile's first workplace")
+	profileCreateCmd.MarkFlagRequired("notes-dir")
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+	rootCmd.AddCommand(profileCmd)
+}
+
+func runProfileList(cmd *cobra.Command, args []string) error {
+	if err := config.SetProfile(profileName); err != nil {
+		return err
+	}
+	profiles, err := config.Profiles()
+	if err != nil {
+		return fmt.Errorf("error reading profiles: %w", err)
+	}
+
+	active := config.ActiveProfile()
+	fmt.Println()
+	for _, name := range append([]string{""}, profiles...) {
+		label := name
+		if name == "" {
+			label = "default"
+		}
+		if name == active {
+			fmt.Printf("  %s %s\n", ui.SuccessStyle.Render(ui.IconSuccess), ui.InfoStyle.Render(label))
+		} else {
+			fmt.Printf("    %s\n", label)
+		}
+	}
+	if active != "" && !config.ProfileExists(active) {
+		fmt.Println()
+		fmt.Println(ui.RenderWarning(fmt.Sprintf("The active profile '%s' doesn't exist yet", active)))
+	}
+	fmt.Println()
+	return nil
+}
+
+func runProfileCreate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := validateWorkplaceName(profileWorkplace); err != nil {
+		return err
+	}
+
+	// ~ is expanded when the config is read; other paths are made absolute
+	notesDir := profileNotesDir
+	if !strings.HasPrefix(notesDir, "~") {
+		abs, err := filepath.Abs(notesDir)
+		if err != nil {
+			return fmt.Errorf("invalid notes folder: %w", err)
+		}
+		notesDir = abs
+	}
+	path, err := config.CreateProfile(name, notesDir, profileWorkplace)
+	if err != nil {
+		return fmt.Errorf("failed to create profile: %w", err)
+	}
+
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Created profile '%s'", name)))
+	fmt.Println(ui.MutedStyle.Render("  " + path))
+	fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("  Use it with 'worklog --profile %s' or %s=%s", name, config.ProfileEnv, name)))
+	return nil
+}
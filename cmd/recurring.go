@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sandepten/work-obsidian-noter/internal/recurring"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var recurringWorkplace string
+
+// recurringSample is written to new recurring task files
+const recurringSample = `# Recurring work items, added to Pending when a note is created on a day they're due.
+# One per line: <schedule> | <item text>
+#
+# Schedules are RRULEs, five-field cron expressions or shortcuts:
+#   @daily, @weekdays, @weekly (Mondays), @monthly (the 1st), @yearly
+#   FREQ=WEEKLY;BYDAY=FR
+#   FREQ=WEEKLY;INTERVAL=2;BYDAY=MO;DTSTART=2025-01-06
+#   FREQ=MONTHLY;BYDAY=-1FR
+#   0 9 * * 1-5
+#
+# @weekdays | Check the on-call dashboard
+# FREQ=WEEKLY;BYDAY=FR | Send the weekly report
+`
+
+var recurringCmd = &cobra.Command{
+	Use:   "recurring",
+	Short: "Show recurring work items",
+	Long: `Show the recurring work items of a workplace and when they're next due.
+
+Recurring items live in a file per workplace, one per line as
+"<schedule> | <item text>". When a note is created on a day an item is due,
+the item is added to Pending, unless it's already there or carried over.
+
+Schedules are RRULEs (FREQ=WEEKLY;BYDAY=FR), five-field cron expressions
+(0 9 * * 1-5) or shortcuts (@daily, @weekdays, @weekly, @monthly, @yearly).
+
+Examples:
+  worklog recurring
+  worklog recurring edit -w Acme
+  worklog recurring path`,
+	RunE: runRecurring,
+}
+
+var recurringEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the recurring work items in your editor",
+	RunE:  runRecurringEdit,
+}
+
+var recurringPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the recurring work items file",
+	RunE:  runRecurringPath,
+}
+
+func init() {
+	recurringCmd.PersistentFlags().StringVarP(&recurringWorkplace, "workplace", "w", "", "Workplace of the recurring items")
+	recurringCmd.AddCommand(recurringEditCmd)
+	recurringCmd.AddCommand(recurringPathCmd)
+	rootCmd.AddCommand(recurringCmd)
+}
+
+func runRecurring(cmd *cobra.Command, args []string) error {
+	selectedWorkplace, err := chooseWorkplace(recurringWorkplace)
+	if err != nil {
+		return err
+	}
+
+	path := cfg.RecurringFileFor(selectedWorkplace)
+	tasks, err := recurring.Load(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(ui.HeaderStyle.Render(fmt.Sprintf("Recurring items · %s", selectedWorkplace)))
+	fmt.Println(ui.MutedStyle.Render(path))
+	fmt.Println()
+
+	if len(tasks) == 0 {
+		fmt.Println(ui.MutedStyle.Render("No recurring items yet. Add some with 'worklog recurring edit'."))
+		fmt.Println()
+		return nil
+	}
+
+	today := todayFor(selectedWorkplace)
+	for _, task := range tasks {
+		next := "not due within a year"
+		if dates := task.Next(today, 3); len(dates) > 0 {
+			var days []string
+			for _, d := range dates {
+				days = append(days, d.Format("Mon Jan 2"))
+			}
+			next = "next: " + strings.Join(days, ", ")
+		}
+
+		fmt.Println(ui.PendingItemStyle.Render("↻ " + task.Text))
+		fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("    %s · %s", task.Expr, next)))
+	}
+	fmt.Println()
+	return nil
+}
+
+func runRecurringEdit(cmd *cobra.Command, args []string) error {
+	selectedWorkplace, err := chooseWorkplace(recurringWorkplace)
+	if err != nil {
+		return err
+	}
+
+	path := cfg.RecurringFileFor(selectedWorkplace)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(recurringSample), 0644); err != nil {
+			return err
+		}
+	}
+
+	if err := openInEditor(path); err != nil {
+		return err
+	}
+
+	// Check the file right away rather than on the next note
+	tasks, err := recurring.Load(path)
+	if err != nil {
+		return fmt.Errorf("%w (run 'worklog recurring edit' again to fix it)", err)
+	}
+
+	fmt.Println()
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Saved %d recurring item(s) for %s", len(tasks), selectedWorkplace)))
+	fmt.Println()
+	return nil
+}
+
+func runRecurringPath(cmd *cobra.Command, args []string) error {
+	selectedWorkplace, err := chooseWorkplace(recurringWorkplace)
+	if err != nil {
+		return err
+	}
+	fmt.Println(cfg.RecurringFileFor(selectedWorkplace))
+	return nil
+}
@@ -4,38 +4,47 @@ import (
 	"fmt"
 	"path/filepath"
 	"sort"
-	"time"
 
+	"github.com/sandepten/work-obsidian-noter/internal/hooks"
 	"github.com/sandepten/work-obsidian-noter/internal/notes"
 	"github.com/sandepten/work-obsidian-noter/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+var reviewAll bool
+
 var reviewCmd = &cobra.Command{
 	Use:   "review",
 	Short: "Review pending items from previous notes",
 	Long: `Manually review and process pending items from previous notes
 without creating a new note or generating summaries.
-You will be prompted to select a workplace if multiple are configured.`,
+You will be prompted to select a workplace if multiple are configured.
+
+With --all, the pending items of the previous note of every workplace are
+reviewed together in one list.`,
 	RunE: runReview,
 }
 
 func init() {
+	reviewCmd.Flags().BoolVarP(&reviewAll, "all", "a", false, "Review the previous notes of every workplace together")
 	rootCmd.AddCommand(reviewCmd)
 }
 
 func runReview(cmd *cobra.Command, args []string) error {
-	today := time.Now().Truncate(24 * time.Hour)
+	if reviewAll {
+		return runReviewAll()
+	}
 
-	// Ask which workplace
-	selectedWorkplace, err := prompter.SelectWorkplace(cfg.Workplaces)
+	// Choose the workplace
+	selectedWorkplace, err := chooseWorkplace("")
 	if err != nil {
-		return fmt.Errorf("error selecting workplace: %w", err)
+		return err
 	}
+	today := todayFor(selectedWorkplace)
 
 	// Create parser and writer for the selected workplace
-	workplaceParser := notes.NewParser(cfg.WorkNotesLocation, selectedWorkplace)
-	workplaceWriter := notes.NewWriter(cfg.WorkNotesLocation, selectedWorkplace)
+	workplaceParser := newParser(selectedWorkplace)
+	workplaceWriter := newWriter(selectedWorkplace)
 
 	// Find the most recent previous note
 	previousNote, err := workplaceParser.FindMostRecentNote(today)
@@ -54,7 +63,8 @@ func runReview(cmd *cobra.Command, args []string) error {
 	fmt.Println(ui.RenderDivider(50))
 	fmt.Println()
 
-	if !previousNote.HasPendingWork() {
+	active, indices := activeItems(previousNote)
+	if len(active) == 0 {
 		fmt.Println(ui.RenderSuccess("No pending items to review — all caught up! 🎉"))
 		fmt.Println()
 		prompter.DisplayWorkItems(previousNote.PendingWork, previousNote.CompletedWork)
@@ -65,10 +75,14 @@ func runReview(cmd *cobra.Command, args []string) error {
 	fmt.Println(ui.MutedStyle.Render("Mark items you've completed"))
 	fmt.Println()
 
-	completedIndices, err := prompter.SelectPendingItems(previousNote.PendingWork)
+	selected, err := prompter.SelectPendingItems(active)
+	if cancelled(err) {
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("error reviewing items: %w", err)
 	}
+	completedIndices := noteIndices(selected, indices)
 
 	if len(completedIndices) == 0 {
 		fmt.Println()
@@ -82,19 +96,16 @@ func runReview(cmd *cobra.Command, args []string) error {
 
 	// Mark items as completed
 	for _, idx := range completedIndices {
-		item := previousNote.PendingWork[idx]
-		previousNote.CompletedWork = append(previousNote.CompletedWork, notes.WorkItem{
-			Text:      item.Text,
-			Completed: true,
-		})
-		// Remove from pending
-		previousNote.PendingWork = append(previousNote.PendingWork[:idx], previousNote.PendingWork[idx+1:]...)
+		previousNote.MarkItemCompleted(idx)
 	}
 
 	// Save the note
 	if err := workplaceWriter.WriteNote(previousNote); err != nil {
 		return fmt.Errorf("error saving note: %w", err)
 	}
+	if err := emitNote(hooks.ItemCompleted, selectedWorkplace, previousNote, lastItems(previousNote.CompletedWork, len(completedIndices))); err != nil {
+		return err
+	}
 
 	fmt.Println()
 	fmt.Println(ui.RenderDivider(50))
@@ -106,3 +117,104 @@ func runReview(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// reviewedNote is the previous note of a workplace under review with --all
+type reviewedNote struct {
+	workplace string
+	note      *notes.Note
+	completed []int // Pending indices marked as completed
+}
+
+// runReviewAll reviews the pending items of the previous note of every
+// workplace in one list, labelled with their workplace
+func runReviewAll() error {
+	var reviewed []*reviewedNote
+	var items []notes.WorkItem
+	var owners []*reviewedNote
+	var indices []int
+
+	for _, wp := range cfg.Workplaces {
+		previousNote, err := newParser(wp).FindMostRecentNote(todayFor(wp))
+		if err != nil {
+			return fmt.Errorf("error finding previous note for %s: %w", wp, err)
+		}
+		if previousNote == nil {
+			continue
+		}
+
+		r := &reviewedNote{workplace: wp, note: previousNote}
+		reviewed = append(reviewed, r)
+		active, noteIndices := activeItems(previousNote)
+		for i, item := range active {
+			item.Text = fmt.Sprintf("[%s] %s", wp, item.Text)
+			items = append(items, item)
+			owners = append(owners, r)
+			indices = append(indices, noteIndices[i])
+		}
+	}
+
+	if len(reviewed) == 0 {
+		prompter.DisplayMessage("No previous notes found in any workplace.")
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println(ui.TitleStyle.Render("📝 Review Previous Notes (all workplaces)"))
+	for _, r := range reviewed {
+		fmt.Println(ui.InfoStyle.Render(fmt.Sprintf("📄 %s: %s (%s)", r.workplace, filepath.Base(r.note.FilePath), r.note.Date.Format("January 2, 2006"))))
+	}
+	fmt.Println(ui.RenderDivider(50))
+	fmt.Println()
+
+	if len(items) == 0 {
+		fmt.Println(ui.RenderSuccess("No pending items to review — all caught up! 🎉"))
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Println(ui.HeaderStyle.Render("Review Pending Items"))
+	fmt.Println(ui.MutedStyle.Render("Mark items you've completed"))
+	fmt.Println()
+
+	selected, err := prompter.SelectPendingItems(items)
+	if cancelled(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reviewing items: %w", err)
+	}
+	if len(selected) == 0 {
+		fmt.Println()
+		fmt.Println(ui.MutedStyle.Render("No items marked as completed."))
+		fmt.Println()
+		return nil
+	}
+
+	for _, pos := range selected {
+		owners[pos].completed = append(owners[pos].completed, indices[pos])
+	}
+
+	fmt.Println()
+	fmt.Println(ui.RenderDivider(50))
+	for _, r := range reviewed {
+		if len(r.completed) == 0 {
+			continue
+		}
+
+		// Sort indices in descending order
+		sort.Sort(sort.Reverse(sort.IntSlice(r.completed)))
+		for _, idx := range r.completed {
+			r.note.MarkItemCompleted(idx)
+		}
+		if err := newWriter(r.workplace).WriteNote(r.note); err != nil {
+			return fmt.Errorf("error saving note for %s: %w", r.workplace, err)
+		}
+		if err := emitNote(hooks.ItemCompleted, r.workplace, r.note, lastItems(r.note.CompletedWork, len(r.completed))); err != nil {
+			return err
+		}
+		fmt.Println(ui.RenderSuccess(fmt.Sprintf("%s: marked %d item(s) as completed!", r.workplace, len(r.completed))))
+	}
+	fmt.Println()
+
+	return nil
+}
@@ -3,20 +3,26 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/sandepten/work-obsidian-noter/internal/config"
+	"github.com/sandepten/work-obsidian-noter/internal/hooks"
 	"github.com/sandepten/work-obsidian-noter/internal/notes"
-	"github.com/sandepten/work-obsidian-noter/internal/summarizer"
+	"github.com/sandepten/work-obsidian-noter/internal/recurring"
 	"github.com/sandepten/work-obsidian-noter/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cfg      *config.Config
-	parser   *notes.Parser
-	writer   *notes.Writer
-	prompter *ui.Prompter
-	aiClient *summarizer.Client
+	cfg        *config.Config
+	cfgErr     error // Why the config couldn't be loaded, for commands that run anyway
+	parser     *notes.Parser
+	writer     *notes.Writer
+	prompter   *ui.Prompter
+	hookRunner *hooks.Runner
+
+	profileName string // Profile chosen with --profile
+	configPath  string // Config file chosen with --config
 )
 
 // rootCmd represents the base command
@@ -27,29 +33,85 @@ var rootCmd = &cobra.Command{
 	
 Track your pending and completed work items, review yesterday's tasks,
 and get AI-powered summaries of your accomplishments.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		initConfig(cmd)
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Use a named profile (default $"+config.ProfileEnv+")")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Read this config file instead of the profile's (default $"+config.ConfigEnv+")")
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
+// A command line naming a plugin on PATH runs the plugin instead.
 func Execute() {
+	if ran, code := runPluginCommand(os.Args[1:]); ran {
+		os.Exit(code)
+	}
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
-func init() {
-	cobra.OnInitialize(initConfig)
-}
+// initConfig reads configuration and initializes dependencies. The config
+// commands still run with a broken config so that it can be fixed.
+func initConfig(cmd *cobra.Command) {
+	prompter = ui.NewPrompter()
+	lenient := isConfigCommand(cmd)
 
-// initConfig reads configuration and initializes dependencies
-func initConfig() {
-	var err error
-	cfg, err = config.Load()
-	if err != nil {
+	if err := config.SetProfile(profileName); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	config.SetConfigFile(configPath)
+	cfg, cfgErr = config.Load()
+	if cfgErr != nil {
+		if lenient {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", cfgErr)
+		os.Exit(1)
+	}
+
+	if len(cfg.MovedState) > 0 {
+		fmt.Fprintln(os.Stderr, ui.RenderInfo(fmt.Sprintf("Moved %s to %s", strings.Join(cfg.MovedState, ", "), config.StateDir())))
+	}
+	if cfg.MigratedFrom != "" {
+		fmt.Fprintln(os.Stderr, ui.RenderInfo(fmt.Sprintf("Moved your settings from %s to %s (the old file is kept as %s.legacy)", cfg.MigratedFrom, config.GetConfigPath(), cfg.MigratedFrom)))
+	}
+	if lenient {
+		return
+	}
+
+	if err := ui.SetLocale(cfg.Locale); err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Validate note settings up front so commands can rely on them
+	for _, wp := range cfg.Workplaces {
+		if err := validateWorkplaceSettings(wp); err != nil {
+			fmt.Fprintf(os.Stderr, "Error in config for %s: %v\n", wp, err)
+			os.Exit(1)
+		}
+		// A broken recurring file only skips recurring items, so it can still be fixed
+		// with 'worklog recurring edit'
+		if _, err := recurring.Load(cfg.RecurringFileFor(wp)); err != nil {
+			fmt.Fprintln(os.Stderr, ui.RenderWarning(fmt.Sprintf("Skipping recurring items for %s: %v", wp, err)))
+		}
+	}
+
+	hookList, err := hooks.Parse(cfg.Hooks())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error in config: %v\n", err)
+		os.Exit(1)
+	}
+	hookRunner = hooks.NewRunner(hookList, os.Stderr, func(message string) {
+		fmt.Fprintln(os.Stderr, ui.RenderWarning(message))
+	})
+
 	// Ensure notes directory exists
 	if err := cfg.EnsureNotesDirectory(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating notes directory: %v\n", err)
@@ -57,8 +119,6 @@ func initConfig() {
 	}
 
 	// Initialize dependencies
-	parser = notes.NewParser(cfg.WorkNotesLocation, cfg.WorkplaceName)
-	writer = notes.NewWriter(cfg.WorkNotesLocation, cfg.WorkplaceName)
-	prompter = ui.NewPrompter()
-	aiClient = summarizer.NewClient(cfg.OpenCodeServer, cfg.AIProvider, cfg.AIModel)
+	parser = newParser(cfg.WorkplaceName)
+	writer = newWriter(cfg.WorkplaceName)
 }
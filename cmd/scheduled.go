@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var scheduledWorkplace string
+
+var scheduledCmd = &cobra.Command{
+	Use:   "scheduled",
+	Short: "List deferred items and when they come back",
+	Long: `List the items parked with 'worklog defer', grouped by the date they come back.
+
+Without --workplace, the items of every workplace are shown.`,
+	RunE: runScheduled,
+}
+
+func init() {
+	scheduledCmd.Flags().StringVarP(&scheduledWorkplace, "workplace", "w", "", "Only show items of this workplace")
+	rootCmd.AddCommand(scheduledCmd)
+}
+
+func runScheduled(cmd *cobra.Command, args []string) error {
+	workplaces := cfg.Workplaces
+	if scheduledWorkplace != "" {
+		if !isWorkplace(scheduledWorkplace) {
+			return fmt.Errorf("workplace '%s' not found", scheduledWorkplace)
+		}
+		workplaces = []string{scheduledWorkplace}
+	}
+
+	fmt.Println()
+	fmt.Println(ui.HeaderStyle.Render("Scheduled items"))
+	fmt.Println()
+
+	total := 0
+	for _, wp := range workplaces {
+		ledger, err := notes.LoadLedger(cfg.ScheduledFileFor(wp))
+		if err != nil {
+			return fmt.Errorf("error reading scheduled items: %w", err)
+		}
+		if len(ledger.Items) == 0 {
+			continue
+		}
+
+		if len(workplaces) > 1 {
+			fmt.Println(ui.SubtitleStyle.Render(wp))
+		}
+		today := todayFor(wp)
+		lastDate := ""
+		for _, s := range ledger.Items {
+			if s.Until != lastDate {
+				label := s.UntilDate().Format("Mon, Jan 2 2006")
+				if !s.UntilDate().After(today) {
+					label += " (due, added to the next note)"
+				}
+				fmt.Println(ui.InfoStyle.Render("  " + label))
+				lastDate = s.Until
+			}
+			fmt.Println(ui.PendingItemStyle.Render("    " + ui.IconPending + " " + s.Text))
+			if s.From != "" {
+				fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("      deferred on %s from %s", s.DeferredOn, s.From)))
+			}
+			total++
+		}
+		fmt.Println()
+	}
+
+	if total == 0 {
+		fmt.Println(ui.MutedStyle.Render("Nothing scheduled. Park items with 'worklog defer <item> --until <date>'."))
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/search"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchRegex         bool
+	searchFuzzy         bool
+	searchCaseSensitive bool
+	searchWorkplaces    []string
+	searchSince         string
+	searchUntil         string
+	searchStatus        string
+	searchTags          []string
+	searchLimit         int
+	searchJSON          bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search work items across all notes",
+	Long: `Search the pending items, completed items and summaries of every note,
+including those of archived workplaces.
+
+By default the query is matched as a case-insensitive substring. Use --regex for
+regular expressions or --fuzzy to match the query's characters in order with
+gaps allowed. Results are ranked by how well they match, most recent first.
+
+Dates for --since and --until accept YYYY-MM-DD, today, yesterday, relative
+offsets such as -2w, or weekday names.
+
+Examples:
+  worklog search deploy
+  worklog search --regex "PR-\d+" --status completed
+  worklog search --fuzzy dplyprod -w Acme --since -1m
+  worklog search --tag urgent
+  worklog search --status blocked`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().BoolVar(&searchRegex, "regex", false, "Treat the query as a regular expression")
+	searchCmd.Flags().BoolVar(&searchFuzzy, "fuzzy", false, "Fuzzy match the query")
+	searchCmd.Flags().BoolVarP(&searchCaseSensitive, "case-sensitive", "c", false, "Match case exactly")
+	searchCmd.Flags().StringSliceVarP(&searchWorkplaces, "workplace", "w", nil, "Only search these workplaces (repeatable)")
+	searchCmd.Flags().StringVar(&searchSince, "since", "", "Only search notes on or after this date")
+	searchCmd.Flags().StringVar(&searchUntil, "until", "", "Only search notes on or before this date")
+	searchCmd.Flags().StringVar(&searchStatus, "status", "", "Only match pending, completed, summary, or pending items that are open, blocked, waiting, cancelled or deferred")
+	searchCmd.Flags().StringSliceVarP(&searchTags, "tag", "t", nil, "Only match items with this tag (repeatable)")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 50, "Maximum number of results (0 for all)")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "Print results as JSON")
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	query := search.Query{
+		Mode:          search.ModeSubstring,
+		CaseSensitive: searchCaseSensitive,
+		Tags:          searchTags,
+	}
+	if len(args) > 0 {
+		query.Text = args[0]
+	}
+	if query.Text == "" && len(searchTags) == 0 && searchStatus == "" {
+		return fmt.Errorf("give a query, --tag or --status to search for")
+	}
+
+	switch {
+	case searchRegex && searchFuzzy:
+		return fmt.Errorf("--regex and --fuzzy cannot be used together")
+	case searchRegex:
+		query.Mode = search.ModeRegex
+	case searchFuzzy:
+		query.Mode = search.ModeFuzzy
+	}
+
+	switch searchStatus {
+	case "", search.StatusPending, search.StatusCompleted, search.StatusSummary:
+		query.Status = searchStatus
+	default:
+		state, err := notes.ParseState(searchStatus)
+		if err != nil {
+			return fmt.Errorf("invalid status %q (use pending, completed, summary, open, blocked, waiting, cancelled or deferred)", searchStatus)
+		}
+		query.Status = state.String()
+	}
+
+	today := todayAcross(searchWorkplaces)
+	if searchSince != "" {
+		since, err := parseDateArg(searchSince, today)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		query.From = since
+	}
+	if searchUntil != "" {
+		until, err := parseDateArg(searchUntil, today)
+		if err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+		query.To = until
+	}
+
+	// Archived workplaces stay searchable
+	workplaces := cfg.AllWorkplaces()
+	if len(searchWorkplaces) > 0 {
+		for _, wp := range searchWorkplaces {
+			if !isWorkplace(wp) && !isArchived(wp) {
+				return fmt.Errorf("workplace '%s' not found", wp)
+			}
+		}
+		workplaces = searchWorkplaces
+	}
+
+	var sources []search.Source
+	for _, wp := range workplaces {
+		sources = append(sources, search.Source{
+			Workplace: wp,
+			Parser:    newParser(wp),
+			Markers:   markersFor(wp),
+		})
+	}
+
+	results, err := search.Search(sources, query)
+	if err != nil {
+		return err
+	}
+
+	total := len(results)
+	if searchLimit > 0 && len(results) > searchLimit {
+		results = results[:searchLimit]
+	}
+
+	if searchJSON {
+		if results == nil {
+			results = []search.Result{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+
+	printSearchResults(results, total, len(workplaces) > 1)
+	return nil
+}
+
+// printSearchResults shows ranked search results with the matches highlighted
+func printSearchResults(results []search.Result, total int, showWorkplace bool) {
+	fmt.Println()
+	if len(results) == 0 {
+		fmt.Println(ui.RenderEmptyState("No matching items found"))
+		fmt.Println()
+		return
+	}
+
+	for _, r := range results {
+		header := ui.InfoStyle.Render(r.Date.Format("Mon, Jan 2 2006"))
+		if showWorkplace {
+			header += "  " + ui.MutedStyle.Render("•") + "  " + ui.InfoStyle.Render(r.Workplace)
+		}
+		header += "  " + ui.MutedStyle.Render(r.Section)
+		fmt.Println(header)
+
+		var icon string
+		switch {
+		case r.State == notes.StateCancelled.String():
+			icon = ui.CancelledItemStyle.Render(ui.IconCancelled)
+		case r.State == notes.StateDeferred.String():
+			icon = ui.DeferredItemStyle.Render(ui.IconDeferred)
+		case r.State == notes.StateWaiting.String():
+			icon = ui.BlockedItemStyle.Render(ui.IconWaiting)
+		case r.State == notes.StateBlocked.String():
+			icon = ui.BlockedItemStyle.Render(ui.IconBlocked)
+		case r.Status == search.StatusPending:
+			icon = ui.PendingItemStyle.Render(ui.IconPending)
+		case r.Status == search.StatusCompleted:
+			icon = ui.CompletedItemStyle.Render(ui.IconCompleted)
+		default:
+			icon = ui.MutedStyle.Render("»")
+		}
+		fmt.Printf("  %s %s\n", icon, highlightMatches(r.Text, r.Matches))
+
+		for _, line := range r.Context {
+			fmt.Println(ui.MutedStyle.Render("      " + line))
+		}
+		fmt.Println()
+	}
+
+	fmt.Println(ui.RenderDivider(50))
+	if total > len(results) {
+		fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("Showing %d of %d matches (use --limit to see more)", len(results), total)))
+	} else {
+		fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("%d match(es)", total)))
+	}
+	fmt.Println()
+}
+
+// highlightMatches renders the matched ranges of text in the warning style
+func highlightMatches(text string, matches [][2]int) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if m[0] < last || m[1] > len(text) {
+			continue
+		}
+		b.WriteString(text[last:m[0]])
+		b.WriteString(ui.WarningStyle.Bold(true).Render(text[m[0]:m[1]]))
+		last = m[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
@@ -3,9 +3,9 @@ package cmd
 import (
 	"fmt"
 	"path/filepath"
-	"sort"
 	"time"
 
+	"github.com/sandepten/work-obsidian-noter/internal/hooks"
 	"github.com/sandepten/work-obsidian-noter/internal/notes"
 	"github.com/sandepten/work-obsidian-noter/internal/ui"
 	"github.com/spf13/cobra"
@@ -28,17 +28,16 @@ func init() {
 }
 
 func runStart(cmd *cobra.Command, args []string) error {
-	today := time.Now().Truncate(24 * time.Hour)
-
-	// Ask which workplace
-	selectedWorkplace, err := prompter.SelectWorkplace(cfg.Workplaces)
+	// Choose the workplace
+	selectedWorkplace, err := chooseWorkplace("")
 	if err != nil {
-		return fmt.Errorf("error selecting workplace: %w", err)
+		return err
 	}
+	today := todayFor(selectedWorkplace)
 
 	// Create parser and writer for the selected workplace
-	workplaceParser := notes.NewParser(cfg.WorkNotesLocation, selectedWorkplace)
-	workplaceWriter := notes.NewWriter(cfg.WorkNotesLocation, selectedWorkplace)
+	workplaceParser := newParser(selectedWorkplace)
+	workplaceWriter := newWriter(selectedWorkplace)
 
 	fmt.Println()
 	fmt.Println(ui.TitleStyle.Render(fmt.Sprintf("🚀 Daily Workflow (%s)", selectedWorkplace)))
@@ -58,12 +57,24 @@ func runStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error finding previous note: %w", err)
 	}
 
+	// What happened is passed to hooks once both notes are saved
+	created := todayNote == nil
+	var completed []notes.WorkItem
+	var summary string
+
 	// Create today's note if it doesn't exist
-	if todayNote == nil {
-		todayNote = workplaceWriter.CreateTodayNote(today)
+	if created {
+		todayNote, err = workplaceWriter.CreateTodayNote(today)
+		if err != nil {
+			return fmt.Errorf("error creating today's note: %w", err)
+		}
 		fmt.Println(ui.RenderSuccess(fmt.Sprintf("Created new note: %s", filepath.Base(todayNote.FilePath))))
 	} else {
 		fmt.Println(ui.InfoStyle.Render(fmt.Sprintf("ℹ Today's note already exists: %s", filepath.Base(todayNote.FilePath))))
+		// Deferred items due today come back even when the note was created earlier
+		if err := workplaceWriter.AddScheduledItems(todayNote); err != nil {
+			return err
+		}
 	}
 	fmt.Println()
 
@@ -72,66 +83,102 @@ func runStart(cmd *cobra.Command, args []string) error {
 		fmt.Println(ui.InfoStyle.Render(fmt.Sprintf("📄 Found previous note: %s", filepath.Base(previousNote.FilePath))))
 		fmt.Println()
 
-		// Review pending items from previous note
-		if previousNote.HasPendingWork() {
+		// Review pending items from previous note. Cancelled and deferred items
+		// stay where they are; blocked items are reviewed and carried over as they are.
+		if active, indices := activeItems(previousNote); len(active) > 0 {
 			fmt.Println(ui.HeaderStyle.Render("Review Pending Items"))
 			fmt.Println(ui.MutedStyle.Render("Mark items you completed since last session"))
 			fmt.Println()
 
-			completedIndices, err := prompter.SelectPendingItems(previousNote.PendingWork)
-			if err != nil {
+			// Cancelling the review means nothing was completed
+			selected, selectedDeferred, err := prompter.ReviewPendingItems(active)
+			if cancelled(err) {
+				selected, selectedDeferred = nil, nil
+			} else if err != nil {
 				return fmt.Errorf("error reviewing pending items: %w", err)
 			}
+			completedIndices := noteIndices(selected, indices)
+			deferredIndices := noteIndices(selectedDeferred, indices)
 
-			// Sort indices in descending order to avoid index shifting during removal
-			sort.Sort(sort.Reverse(sort.IntSlice(completedIndices)))
-
-			// Process completed items - move to previous note's completed section
-			for _, idx := range completedIndices {
-				item := previousNote.PendingWork[idx]
-				previousNote.CompletedWork = append(previousNote.CompletedWork, notes.WorkItem{
-					Text:      item.Text,
-					Completed: true,
-				})
+			// Park deferred items until the date they should come back
+			if len(deferredIndices) > 0 {
+				fmt.Println()
+				var deferred []notes.WorkItem
+				var until []time.Time
+				for _, idx := range deferredIndices {
+					item := previousNote.PendingWork[idx]
+					answer, err := prompter.PromptForDeferDate(item, func(input string) error {
+						_, err := parseDeferDate(input, today)
+						return err
+					})
+					if err != nil {
+						return fmt.Errorf("error reading defer date: %w", err)
+					}
+					date, _ := parseDeferDate(answer, today)
+					deferred = append(deferred, item)
+					until = append(until, date)
+				}
+				if err := deferItems(selectedWorkplace, deferred, until, today, previousNote.FilePath); err != nil {
+					return err
+				}
+				for i, idx := range deferredIndices {
+					markDeferred(&previousNote.PendingWork[idx], until[i])
+				}
 			}
 
-			// Remaining pending items go to today's note
+			// Process completed items - move to previous note's completed section
 			completedSet := make(map[int]bool)
 			for _, idx := range completedIndices {
+				item := previousNote.PendingWork[idx]
+				item.Completed = true
+				item.State = notes.StateNone
+				previousNote.CompletedWork = append(previousNote.CompletedWork, item)
 				completedSet[idx] = true
 			}
+			completed = lastItems(previousNote.CompletedWork, len(completedIndices))
 
+			// Remaining active items go to today's note; the rest stay behind
+			var staying []notes.WorkItem
 			for i, item := range previousNote.PendingWork {
-				if !completedSet[i] {
-					// Add to today's pending
-					todayNote.AddPendingItem(item.Text)
+				switch {
+				case completedSet[i]:
+					continue
+				case !item.Active():
+					staying = append(staying, item)
+					continue
 				}
+				todayNote.CarryOver(item)
 			}
 
-			// Update previous note - clear pending (items either completed or moved)
-			previousNote.PendingWork = []notes.WorkItem{}
+			// Update previous note - only cancelled and deferred items are left pending
+			previousNote.PendingWork = staying
 
 			if len(completedIndices) > 0 {
 				fmt.Println()
 				fmt.Println(ui.RenderSuccess(fmt.Sprintf("Marked %d item(s) as completed", len(completedIndices))))
 			}
+			if len(deferredIndices) > 0 {
+				fmt.Println(ui.RenderSuccess(fmt.Sprintf("Deferred %d item(s); see 'worklog scheduled'", len(deferredIndices))))
+			}
 		}
 
 		// Generate summary if there's completed work
-		if previousNote.HasCompletedWork() {
+		if len(previousNote.DoneWork()) > 0 {
 			fmt.Println()
 			fmt.Println(ui.HeaderStyle.Render("AI Summary"))
 			fmt.Println(ui.MutedStyle.Render("Generating summary of completed work..."))
 
 			// Test connection first
+			aiClient := aiClientFor(selectedWorkplace)
 			if err := aiClient.TestConnection(); err != nil {
-				fmt.Println(ui.RenderWarning(fmt.Sprintf("Could not connect to OpenCode server: %v", err)))
+				fmt.Println(ui.RenderWarning(fmt.Sprintf("Could not reach the summarizer: %v", err)))
 				fmt.Println(ui.MutedStyle.Render("Skipping AI summary generation."))
 			} else {
-				summary, err := aiClient.SummarizeWorkItems(previousNote.CompletedWork)
+				generated, err := aiClient.SummarizeWorkItems(previousNote.DoneWork())
 				if err != nil {
 					fmt.Println(ui.RenderWarning(fmt.Sprintf("Could not generate summary: %v", err)))
 				} else {
+					summary = generated
 					fmt.Println()
 					prompter.DisplaySummaryBox("Summary", summary)
 
@@ -155,6 +202,9 @@ func runStart(cmd *cobra.Command, args []string) error {
 	if err := workplaceWriter.WriteNote(todayNote); err != nil {
 		return fmt.Errorf("error saving today's note: %w", err)
 	}
+	if err := emitStart(selectedWorkplace, todayNote, previousNote, created, completed, summary); err != nil {
+		return err
+	}
 
 	fmt.Println()
 	fmt.Println(ui.RenderDivider(50))
@@ -170,3 +220,29 @@ func runStart(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// emitStart runs the hooks of what start did: creating today's note, then
+// completing items of the previous note and summarizing it
+func emitStart(workplace string, todayNote, previousNote *notes.Note, created bool, completed []notes.WorkItem, summary string) error {
+	if created {
+		if err := emitNote(hooks.NoteCreated, workplace, todayNote, nil); err != nil {
+			return err
+		}
+	}
+	if len(completed) > 0 {
+		if err := emitNote(hooks.ItemCompleted, workplace, previousNote, completed); err != nil {
+			return err
+		}
+	}
+	if summary == "" {
+		return nil
+	}
+	return emit(hooks.Event{
+		Event:     hooks.SummaryGenerated,
+		Workplace: workplace,
+		Note:      previousNote.FilePath,
+		Date:      previousNote.Date.Format("2006-01-02"),
+		Items:     hooks.ItemsOf(previousNote.DoneWork()),
+		Summary:   summary,
+	})
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	stateWorkplace string
+	stateDate      string
+	stateWaiting   bool
+)
+
+var blockCmd = &cobra.Command{
+	Use:   "block <item> [reason]",
+	Short: "Mark a pending item as blocked",
+	Long: `Mark a pending item as blocked, written "- [!]" in the note, or as waiting on
+someone with --waiting, written "- [?]". The reason is kept as a
+[reason:: ...] field.
+
+Blocked items stay in Pending: 'worklog start' still asks about them and
+carries them over with their state and reason.
+
+Examples:
+  worklog block 2 "waiting for API keys"
+  worklog block deploy --waiting "answer from the security team"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		state := notes.StateBlocked
+		if stateWaiting {
+			state = notes.StateWaiting
+		}
+		return setItemState(args, state)
+	},
+}
+
+var cancelCmd = &cobra.Command{
+	Use:   "cancel <item> [reason]",
+	Short: "Mark a pending item as cancelled",
+	Long: `Mark a pending item as cancelled, written "- [-]" in the note.
+
+Cancelled items stay in the note as a record but aren't carried over, counted
+as done or included in summaries.
+
+Examples:
+  worklog cancel 3
+  worklog cancel "old migration" "superseded by the new schema"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setItemState(args, notes.StateCancelled)
+	},
+}
+
+var reopenCmd = &cobra.Command{
+	Use:   "reopen <item>",
+	Short: "Clear the blocked, cancelled or deferred state of an item",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setItemState(args, notes.StateNone)
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{blockCmd, cancelCmd, reopenCmd} {
+		c.Flags().StringVarP(&stateWorkplace, "workplace", "w", "", "Workplace of the note")
+		c.Flags().StringVarP(&stateDate, "date", "d", "today", "Date of the note")
+		rootCmd.AddCommand(c)
+	}
+	blockCmd.Flags().BoolVar(&stateWaiting, "waiting", false, "Waiting on someone rather than blocked")
+}
+
+// setItemState sets the state of the pending item given by args[0], with the
+// rest of args as the reason
+func setItemState(args []string, state notes.ItemState) error {
+	selectedWorkplace, err := chooseWorkplace(stateWorkplace)
+	if err != nil {
+		return err
+	}
+
+	date, err := parseDateArg(stateDate, todayFor(selectedWorkplace))
+	if err != nil {
+		return err
+	}
+
+	note, err := newParser(selectedWorkplace).FindTodayNote(date)
+	if err != nil {
+		return fmt.Errorf("error finding note: %w", err)
+	}
+	if note == nil {
+		prompter.DisplayWarning(fmt.Sprintf("No note found for %s in %s.", date.Format("Mon, Jan 2"), selectedWorkplace))
+		return nil
+	}
+
+	index, err := resolveItem(note.PendingWork, args[0])
+	if err != nil {
+		return err
+	}
+	item := &note.PendingWork[index]
+	if item.Deferred() {
+		// The item is dealt with here now, so it shouldn't come back later
+		if err := unscheduleItem(selectedWorkplace, *item); err != nil {
+			return err
+		}
+		item.RemoveField("until")
+	}
+	item.SetState(state, strings.Join(args[1:], " "))
+
+	if err := newWriter(selectedWorkplace).WriteNote(note); err != nil {
+		return fmt.Errorf("error saving note: %w", err)
+	}
+
+	message := fmt.Sprintf("Marked \"%s\" as %s", item.Title(), state)
+	if state == notes.StateNone {
+		message = fmt.Sprintf("Reopened \"%s\"", item.Title())
+	}
+	fmt.Println()
+	fmt.Println(ui.RenderSuccess(message))
+	prompter.DisplayWorkItems(note.PendingWork, note.CompletedWork)
+	return nil
+}
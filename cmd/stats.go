@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/sandepten/work-obsidian-noter/internal/stats"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsWorkplaces []string
+	statsPeriod     string
+	statsSince      string
+	statsUntil      string
+	statsFormat     string
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show productivity statistics and streaks",
+	Long: `Show statistics for each workplace over a period: items added and completed
+per day, completion rate, average time from an item's first appearance to
+completion, how often pending items are carried over, logging streaks, the
+busiest weekdays and a breakdown of inline #tags.
+
+Streaks count consecutive logged workdays; unlogged weekends don't break them.
+
+Examples:
+  worklog stats
+  worklog stats --period year -w Acme
+  worklog stats --since 2025-01-01 --until 2025-03-31 --format csv`,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().StringSliceVarP(&statsWorkplaces, "workplace", "w", nil, "Only show these workplaces (repeatable)")
+	statsCmd.Flags().StringVarP(&statsPeriod, "period", "p", "month", "Period to cover: week, month, quarter, year or all")
+	statsCmd.Flags().StringVar(&statsSince, "since", "", "Start of the period (overrides --period)")
+	statsCmd.Flags().StringVar(&statsUntil, "until", "", "End of the period (default today)")
+	statsCmd.Flags().StringVarP(&statsFormat, "format", "f", "table", "Output format: table, json or csv")
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	today := todayAcross(statsWorkplaces)
+
+	to := today
+	if statsUntil != "" {
+		until, err := parseDateArg(statsUntil, today)
+		if err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+		to = until
+	}
+
+	var from time.Time
+	if statsSince != "" {
+		since, err := parseDateArg(statsSince, today)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		from = since
+	} else {
+		switch statsPeriod {
+		case "week":
+			from = to.AddDate(0, 0, -6)
+		case "month":
+			from = to.AddDate(0, -1, 1)
+		case "quarter":
+			from = to.AddDate(0, -3, 1)
+		case "year":
+			from = to.AddDate(-1, 0, 1)
+		case "all":
+			// Zero: from the first note
+		default:
+			return fmt.Errorf("invalid period %q (use week, month, quarter, year or all)", statsPeriod)
+		}
+	}
+	if !from.IsZero() && from.After(to) {
+		return fmt.Errorf("--since is after --until")
+	}
+
+	workplaces := cfg.Workplaces
+	if len(statsWorkplaces) > 0 {
+		for _, wp := range statsWorkplaces {
+			if !isWorkplace(wp) {
+				return fmt.Errorf("workplace '%s' not found", wp)
+			}
+		}
+		workplaces = statsWorkplaces
+	}
+
+	var reports []*stats.Report
+	for _, wp := range workplaces {
+		report, err := stats.Compute(wp, newParser(wp), from, to, today)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, report)
+	}
+
+	switch statsFormat {
+	case "table":
+		for _, report := range reports {
+			printStatsReport(report)
+		}
+		return nil
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+	case "csv":
+		return writeStatsCSV(reports)
+	}
+
+	return fmt.Errorf("invalid format %q (use table, json or csv)", statsFormat)
+}
+
+// printStatsReport renders one workplace's report as tables and charts
+func printStatsReport(r *stats.Report) {
+	fmt.Println()
+	fmt.Printf("%s  %s  %s\n", ui.TitleStyle.Render("📊 Worklog Stats"), ui.MutedStyle.Render("•"), ui.InfoStyle.Render(r.Workplace))
+	fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("%s – %s", r.From.Format("Jan 2, 2006"), r.To.Format("Jan 2, 2006"))))
+	fmt.Println()
+
+	summary := statsTable().
+		Row("Notes", strconv.Itoa(r.Notes)).
+		Row("Items added", strconv.Itoa(r.Added)).
+		Row("Items completed", strconv.Itoa(r.Completed)).
+		Row("Completion rate", fmt.Sprintf("%.0f%%", r.CompletionRate*100)).
+		Row("Avg. time to done", fmt.Sprintf("%.1f days", r.AvgDaysToDone)).
+		Row("Carry-over ratio", fmt.Sprintf("%.0f%%", r.CarryOverRatio*100)).
+		Row("Current streak", fmt.Sprintf("%d day(s)", r.CurrentStreak)).
+		Row("Longest streak", fmt.Sprintf("%d day(s)", r.LongestStreak))
+	fmt.Println(summary.Render())
+	fmt.Println()
+
+	// Bucket long periods by week so the sparkline fits on one line
+	var completed, added []int
+	bucket := 1
+	if len(r.Days) > 62 {
+		bucket = 7
+	}
+	for i, day := range r.Days {
+		if i%bucket == 0 {
+			completed = append(completed, 0)
+			added = append(added, 0)
+		}
+		completed[len(completed)-1] += day.Completed
+		added[len(added)-1] += day.Added
+	}
+	unit := "day"
+	if bucket > 1 {
+		unit = "week"
+	}
+	fmt.Println(ui.HeaderStyle.Render(fmt.Sprintf("Activity per %s", unit)))
+	fmt.Printf("  %s %s\n", ui.MutedStyle.Render("added    "), ui.RenderSparkline(added))
+	fmt.Printf("  %s %s\n", ui.MutedStyle.Render("completed"), ui.RenderSparkline(completed))
+	fmt.Println()
+
+	busiest := 0
+	for _, wd := range r.Weekdays {
+		if wd.Completed > busiest {
+			busiest = wd.Completed
+		}
+	}
+	fmt.Println(ui.HeaderStyle.Render("Busiest weekdays"))
+	for _, wd := range r.Weekdays {
+		fmt.Printf("  %s %s %s\n",
+			ui.MutedStyle.Render(fmt.Sprintf("%-9s", wd.Weekday)),
+			ui.RenderBar(wd.Completed, busiest, 30, ui.CompletedItemStyle),
+			ui.MutedStyle.Render(strconv.Itoa(wd.Completed)))
+	}
+	fmt.Println()
+
+	if len(r.Tags) > 0 {
+		fmt.Println(ui.HeaderStyle.Render("Tags"))
+		tags := statsTable().Headers("Tag", "Added", "Completed")
+		for i, tc := range r.Tags {
+			if i == 10 {
+				break
+			}
+			tags.Row("#"+tc.Tag, strconv.Itoa(tc.Added), strconv.Itoa(tc.Completed))
+		}
+		fmt.Println(tags.Render())
+		fmt.Println()
+	}
+}
+
+// statsTable creates a table in worklog's colors
+func statsTable() *table.Table {
+	return table.New().
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(ui.Subtle)).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := lipgloss.NewStyle().Padding(0, 1)
+			switch {
+			case row == table.HeaderRow:
+				return style.Foreground(ui.Cyan).Bold(true)
+			case col == 0:
+				return style.Foreground(ui.Gray)
+			}
+			return style
+		})
+}
+
+// writeStatsCSV writes one row per workplace and day
+func writeStatsCSV(reports []*stats.Report) error {
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write([]string{"workplace", "date", "has_note", "added", "completed", "pending", "carried"}); err != nil {
+		return err
+	}
+	for _, r := range reports {
+		for _, day := range r.Days {
+			record := []string{
+				r.Workplace,
+				day.Date.Format("2006-01-02"),
+				strconv.FormatBool(day.HasNote),
+				strconv.Itoa(day.Added),
+				strconv.Itoa(day.Completed),
+				strconv.Itoa(day.Pending),
+				strconv.Itoa(day.Carried),
+			}
+			if err := w.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -2,35 +2,45 @@ package cmd
 
 import (
 	"fmt"
-	"time"
 
+	"github.com/sandepten/work-obsidian-noter/internal/hooks"
 	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/summarizer"
 	"github.com/sandepten/work-obsidian-noter/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+var summarizeAll bool
+
 var summarizeCmd = &cobra.Command{
 	Use:   "summarize",
 	Short: "Get AI summary of today's completed work",
-	Long:  `Generate and display an AI-powered summary of today's completed work items. You will be prompted to select a workplace if multiple are configured.`,
-	RunE:  runSummarize,
+	Long: `Generate and display an AI-powered summary of today's completed work items. You will be prompted to select a workplace if multiple are configured.
+
+With --all, the completed work of every workplace is listed by workplace and
+summarized together, using the global AI settings.`,
+	RunE: runSummarize,
 }
 
 func init() {
+	summarizeCmd.Flags().BoolVarP(&summarizeAll, "all", "a", false, "Summarize the work of every workplace together")
 	rootCmd.AddCommand(summarizeCmd)
 }
 
 func runSummarize(cmd *cobra.Command, args []string) error {
-	today := time.Now().Truncate(24 * time.Hour)
+	if summarizeAll {
+		return runSummarizeAll()
+	}
 
-	// Ask which workplace
-	selectedWorkplace, err := prompter.SelectWorkplace(cfg.Workplaces)
+	// Choose the workplace
+	selectedWorkplace, err := chooseWorkplace("")
 	if err != nil {
-		return fmt.Errorf("error selecting workplace: %w", err)
+		return err
 	}
+	today := todayFor(selectedWorkplace)
 
 	// Create parser for the selected workplace
-	workplaceParser := notes.NewParser(cfg.WorkNotesLocation, selectedWorkplace)
+	workplaceParser := newParser(selectedWorkplace)
 
 	// Get today's note
 	todayNote, err := workplaceParser.FindTodayNote(today)
@@ -43,7 +53,9 @@ func runSummarize(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if !todayNote.HasCompletedWork() {
+	// Cancelled work isn't part of the summary
+	done := todayNote.DoneWork()
+	if len(done) == 0 {
 		fmt.Println()
 		fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("No completed work items to summarize in %s.", selectedWorkplace)))
 		fmt.Println(ui.MutedStyle.Render("Use 'worklog done' to mark items as completed first."))
@@ -59,7 +71,7 @@ func runSummarize(cmd *cobra.Command, args []string) error {
 
 	// Display completed work
 	fmt.Println(ui.HeaderStyle.Render("Completed Work"))
-	for i, item := range todayNote.CompletedWork {
+	for i, item := range done {
 		fmt.Println(ui.RenderCompletedItem(i+1, item.Text))
 	}
 	fmt.Println()
@@ -69,16 +81,91 @@ func runSummarize(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Test connection first
+	aiClient := aiClientFor(selectedWorkplace)
 	if err := aiClient.TestConnection(); err != nil {
-		return fmt.Errorf("could not connect to OpenCode server: %w", err)
+		return fmt.Errorf("could not reach the summarizer: %w", err)
 	}
 
-	summary, err := aiClient.SummarizeWorkItems(todayNote.CompletedWork)
+	summary, err := aiClient.SummarizeWorkItems(done)
 	if err != nil {
 		return fmt.Errorf("could not generate summary: %w", err)
 	}
 
 	prompter.DisplaySummaryBox("AI-Generated Summary", summary)
 
-	return nil
+	return emit(hooks.Event{
+		Event:     hooks.SummaryGenerated,
+		Workplace: selectedWorkplace,
+		Note:      todayNote.FilePath,
+		Date:      todayNote.Date.Format("2006-01-02"),
+		Items:     hooks.ItemsOf(done),
+		Summary:   summary,
+	})
+}
+
+// runSummarizeAll lists today's completed work of every workplace and
+// summarizes it in one summary
+func runSummarizeAll() error {
+	var groups []summarizer.ItemGroup
+	for _, wp := range cfg.Workplaces {
+		todayNote, err := newParser(wp).FindTodayNote(todayFor(wp))
+		if err != nil {
+			return fmt.Errorf("error finding today's note for %s: %w", wp, err)
+		}
+		if todayNote == nil {
+			continue
+		}
+		if done := todayNote.DoneWork(); len(done) > 0 {
+			groups = append(groups, summarizer.ItemGroup{Name: wp, Items: done})
+		}
+	}
+
+	if len(groups) == 0 {
+		fmt.Println()
+		fmt.Println(ui.MutedStyle.Render("No completed work items to summarize in any workplace."))
+		fmt.Println(ui.MutedStyle.Render("Use 'worklog done' to mark items as completed first."))
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println(ui.TitleStyle.Render("📊 Work Summary (all workplaces)"))
+	fmt.Println(ui.MutedStyle.Render(todayAcross(cfg.Workplaces).Format("Monday, January 2, 2006")))
+	fmt.Println(ui.RenderDivider(50))
+	fmt.Println()
+
+	for _, group := range groups {
+		fmt.Println(ui.HeaderStyle.Render(group.Name))
+		for i, item := range group.Items {
+			fmt.Println(ui.RenderCompletedItem(i+1, item.Text))
+		}
+		fmt.Println()
+	}
+
+	fmt.Println(ui.InfoStyle.Render("🤖 Generating AI summary..."))
+	fmt.Println()
+
+	aiClient := aiClientFor("")
+	if err := aiClient.TestConnection(); err != nil {
+		return fmt.Errorf("could not reach the summarizer: %w", err)
+	}
+
+	summary, err := aiClient.SummarizeGroups(groups)
+	if err != nil {
+		return fmt.Errorf("could not generate summary: %w", err)
+	}
+
+	prompter.DisplaySummaryBox("AI-Generated Summary", summary)
+
+	// A summary of every workplace has no workplace of its own
+	var items []notes.WorkItem
+	for _, group := range groups {
+		items = append(items, group.Items...)
+	}
+	return emit(hooks.Event{
+		Event:   hooks.SummaryGenerated,
+		Date:    todayAcross(cfg.Workplaces).Format("2006-01-02"),
+		Items:   hooks.ItemsOf(items),
+		Summary: summary,
+	})
 }
@@ -0,0 +1,460 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/timelog"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// timeField is the inline field holding the total time tracked on an item
+const timeField = "time"
+
+var (
+	trackWorkplace   string
+	reportWorkplaces []string
+	reportPeriod     string
+	reportSince      string
+	reportUntil      string
+	reportBy         string
+	reportFormat     string
+)
+
+var trackCmd = &cobra.Command{
+	Use:   "track",
+	Short: "Track time spent on work items",
+	Long: `Track time spent on the items of today's note.
+
+One timer runs at a time: starting a timer stops the one that is running.
+Every start and stop is appended to a time log right away, so a running timer
+survives crashes and reboots. When a timer stops, its time is added to the
+item's [time:: 1h30m] field in the note, which travels with the item when it
+is carried over.
+
+Examples:
+  worklog track start "code review"
+  worklog track status
+  worklog track stop
+  worklog track report --period week --by tag`,
+}
+
+var trackStartCmd = &cobra.Command{
+	Use:   "start <item>",
+	Short: "Start a timer on a pending item",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTrackStart,
+}
+
+var trackStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running timer",
+	Args:  cobra.NoArgs,
+	RunE:  runTrackStop,
+}
+
+var trackStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the running timer and today's tracked time",
+	Args:  cobra.NoArgs,
+	RunE:  runTrackStatus,
+}
+
+var trackReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report tracked hours per day by workplace, tag or item",
+	Long: `Report the hours tracked per day, grouped by workplace, inline #tag or item.
+
+Examples:
+  worklog track report                       # this week, by workplace
+  worklog track report --period month --by tag
+  worklog track report --since 2025-01-01 --until 2025-01-31 -f csv`,
+	Args: cobra.NoArgs,
+	RunE: runTrackReport,
+}
+
+func init() {
+	trackStartCmd.Flags().StringVarP(&trackWorkplace, "workplace", "w", "", "Workplace of the item")
+	trackReportCmd.Flags().StringSliceVarP(&reportWorkplaces, "workplace", "w", nil, "Only count these workplaces (repeatable)")
+	trackReportCmd.Flags().StringVarP(&reportPeriod, "period", "p", "week", "Period to cover: day, week or month")
+	trackReportCmd.Flags().StringVar(&reportSince, "since", "", "Start of the period (overrides --period)")
+	trackReportCmd.Flags().StringVar(&reportUntil, "until", "", "End of the period")
+	trackReportCmd.Flags().StringVar(&reportBy, "by", "workplace", "Group by workplace, tag or item")
+	trackReportCmd.Flags().StringVarP(&reportFormat, "format", "f", "table", "Output format: table, json or csv")
+
+	trackCmd.AddCommand(trackStartCmd)
+	trackCmd.AddCommand(trackStopCmd)
+	trackCmd.AddCommand(trackStatusCmd)
+	trackCmd.AddCommand(trackReportCmd)
+	rootCmd.AddCommand(trackCmd)
+}
+
+func runTrackStart(cmd *cobra.Command, args []string) error {
+	selectedWorkplace, err := chooseWorkplace(trackWorkplace)
+	if err != nil {
+		return err
+	}
+
+	today := todayFor(selectedWorkplace)
+	note, err := newParser(selectedWorkplace).FindTodayNote(today)
+	if err != nil {
+		return fmt.Errorf("error finding today's note: %w", err)
+	}
+	if note == nil {
+		prompter.DisplayWarning(fmt.Sprintf("No note found for today in %s. Use 'worklog start' to create one.", selectedWorkplace))
+		return nil
+	}
+
+	index, err := resolveItem(note.PendingWork, args[0])
+	if err != nil {
+		return err
+	}
+	item := note.PendingWork[index]
+	if !item.Active() {
+		return fmt.Errorf("\"%s\" is %s; only open or blocked items can be tracked", item.Title(), item.State)
+	}
+
+	stopped, err := timelog.Open(cfg.TimeLogPath()).Start(selectedWorkplace, item.Title(), item.Tags(), time.Now())
+	if err != nil {
+		return fmt.Errorf("error writing time log: %w", err)
+	}
+
+	fmt.Println()
+	if stopped != nil {
+		if err := recordTrackedTime(stopped); err != nil {
+			return err
+		}
+	}
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Tracking \"%s\" (%s)", item.Title(), selectedWorkplace)))
+	fmt.Println(ui.MutedStyle.Render("Stop with 'worklog track stop'"))
+	fmt.Println()
+	return nil
+}
+
+func runTrackStop(cmd *cobra.Command, args []string) error {
+	stopped, err := timelog.Open(cfg.TimeLogPath()).Stop(time.Now())
+	if err != nil {
+		return fmt.Errorf("error writing time log: %w", err)
+	}
+
+	fmt.Println()
+	if stopped == nil {
+		fmt.Println(ui.MutedStyle.Render("No timer is running."))
+		fmt.Println()
+		return nil
+	}
+	if err := recordTrackedTime(stopped); err != nil {
+		return err
+	}
+	fmt.Println()
+	return nil
+}
+
+// recordTrackedTime adds the time of a stopped timer to the item's time field
+func recordTrackedTime(entry *timelog.Entry) error {
+	spent := entry.Duration(time.Now())
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Stopped \"%s\" after %s", entry.Item, timelog.FormatDuration(spent))))
+
+	var total time.Duration
+	note, err := updateTrackedItem(entry, func(note *notes.Note, item *notes.WorkItem) {
+		total = addTrackedTime(item, spent)
+	})
+	if err != nil {
+		return err
+	}
+	if note == nil {
+		fmt.Println(ui.MutedStyle.Render("  The item is no longer in its note; the time is kept in the time log."))
+		return nil
+	}
+	fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("  %s in total, noted in %s", timelog.FormatDuration(total), relToNotes(note.FilePath))))
+	return nil
+}
+
+// addTrackedTime adds time to an item's time field and returns the new total
+func addTrackedTime(item *notes.WorkItem, spent time.Duration) time.Duration {
+	total := spent
+	if value, ok := item.Field(timeField); ok {
+		if previous, err := time.ParseDuration(value); err == nil {
+			total += previous
+		}
+	}
+	item.SetField(timeField, timelog.FormatDuration(total))
+	return total
+}
+
+// updateTrackedItem finds the item a timer ran on, in today's note or the note
+// of the day the timer started, and saves the note after update changed it.
+// It returns nil when the item is in neither note.
+func updateTrackedItem(entry *timelog.Entry, update func(note *notes.Note, item *notes.WorkItem)) (*notes.Note, error) {
+	if !isWorkplace(entry.Workplace) {
+		return nil, nil // The workplace was removed; the log still has the time
+	}
+
+	parser := newParser(entry.Workplace)
+	for _, date := range []time.Time{todayFor(entry.Workplace), dateFor(entry.Workplace, entry.Start)} {
+		note, err := parser.FindTodayNote(date)
+		if err != nil {
+			return nil, fmt.Errorf("error finding note: %w", err)
+		}
+		if note == nil {
+			continue
+		}
+		item := findItemByTitle(note, entry.Item)
+		if item == nil {
+			continue
+		}
+
+		update(note, item)
+		if err := newWriter(entry.Workplace).WriteNote(note); err != nil {
+			return nil, fmt.Errorf("error saving note: %w", err)
+		}
+		return note, nil
+	}
+	return nil, nil
+}
+
+func runTrackStatus(cmd *cobra.Command, args []string) error {
+	entries, err := timelog.Open(cfg.TimeLogPath()).Entries()
+	if err != nil {
+		return fmt.Errorf("error reading time log: %w", err)
+	}
+
+	now := time.Now()
+	fmt.Println()
+	if line := runningTimerLine(entries, "", now); line != "" {
+		fmt.Println(line)
+	} else {
+		fmt.Println(ui.MutedStyle.Render("No timer is running."))
+	}
+	fmt.Println()
+
+	today := todayAcross(nil)
+	report := timelog.NewReport(entries, today, today, now, timelog.ByItem, nil)
+	if len(report.Rows) == 0 {
+		return nil
+	}
+
+	fmt.Println(ui.HeaderStyle.Render("Today") + " " + ui.MutedStyle.Render(timelog.FormatDuration(report.Total)))
+	for _, row := range report.Rows {
+		fmt.Printf("  %s  %s\n", ui.InfoStyle.Render(fmt.Sprintf("%7s", timelog.FormatDuration(row.Total))), row.Key)
+	}
+	fmt.Println()
+	return nil
+}
+
+// runningTimerLine describes the running timer, if any, optionally only when
+// it belongs to a workplace
+func runningTimerLine(entries []timelog.Entry, workplace string, now time.Time) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	running := entries[len(entries)-1]
+	if !running.Running() || (workplace != "" && running.Workplace != workplace) {
+		return ""
+	}
+	elapsed := timelog.FormatDuration(running.Duration(now))
+	return ui.BlockedItemStyle.Render(fmt.Sprintf("⏱  Tracking \"%s\" (%s) · %s", running.Item, running.Workplace, elapsed))
+}
+
+func runTrackReport(cmd *cobra.Command, args []string) error {
+	by, err := timelog.ParseGrouping(reportBy)
+	if err != nil {
+		return err
+	}
+
+	today := todayAcross(reportWorkplaces)
+	var from, to time.Time
+	switch reportPeriod {
+	case "day":
+		from, to = today, today
+	case "week":
+		from = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		to = from.AddDate(0, 0, 6)
+	case "month":
+		from = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		to = from.AddDate(0, 1, -1)
+	default:
+		return fmt.Errorf("invalid period %q (use day, week or month)", reportPeriod)
+	}
+	if reportSince != "" {
+		if from, err = parseDateArg(reportSince, today); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if reportUntil != "" {
+		if to, err = parseDateArg(reportUntil, today); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	if from.After(to) {
+		return fmt.Errorf("the start of the period is after its end")
+	}
+
+	for _, wp := range reportWorkplaces {
+		if !isWorkplace(wp) {
+			return fmt.Errorf("workplace '%s' not found", wp)
+		}
+	}
+	match := func(e timelog.Entry) bool {
+		if len(reportWorkplaces) == 0 {
+			return true
+		}
+		for _, wp := range reportWorkplaces {
+			if e.Workplace == wp {
+				return true
+			}
+		}
+		return false
+	}
+
+	entries, err := timelog.Open(cfg.TimeLogPath()).Entries()
+	if err != nil {
+		return fmt.Errorf("error reading time log: %w", err)
+	}
+	report := timelog.NewReport(entries, from, to, time.Now(), by, match)
+
+	switch reportFormat {
+	case "table":
+		printTimeReport(report)
+		return nil
+	case "json":
+		return writeTimeReportJSON(report)
+	case "csv":
+		return writeTimeReportCSV(report)
+	}
+	return fmt.Errorf("invalid format %q (use table, json or csv)", reportFormat)
+}
+
+// printTimeReport renders a report as a table, with a column per day for up to a week
+func printTimeReport(r timelog.Report) {
+	fmt.Println()
+	fmt.Println(ui.HeaderStyle.Render(fmt.Sprintf("Tracked time · %s – %s", r.From.Format("Jan 2"), r.To.Format("Jan 2, 2006"))))
+	fmt.Println()
+
+	if len(r.Rows) == 0 {
+		fmt.Println(ui.MutedStyle.Render("No time tracked in this period. Start a timer with 'worklog track start <item>'."))
+		fmt.Println()
+		return
+	}
+
+	perDay := len(r.Days) > 1 && len(r.Days) <= 7
+	by := string(r.By)
+	headers := []string{strings.ToUpper(by[:1]) + by[1:]}
+	if perDay {
+		for _, day := range r.Days {
+			headers = append(headers, day.Format("Mon 2"))
+		}
+	}
+	headers = append(headers, "Total")
+
+	cell := func(d time.Duration) string {
+		if d == 0 {
+			return "·"
+		}
+		return timelog.FormatDuration(d)
+	}
+
+	t := statsTable().Headers(headers...)
+	for _, row := range r.Rows {
+		record := []string{row.Key}
+		if perDay {
+			for _, d := range row.Days {
+				record = append(record, cell(d))
+			}
+		}
+		t.Row(append(record, cell(row.Total))...)
+	}
+	if len(r.Rows) > 1 {
+		record := []string{"Total"}
+		if perDay {
+			for _, d := range r.Daily {
+				record = append(record, cell(d))
+			}
+		}
+		t.Row(append(record, cell(r.Total))...)
+	}
+
+	fmt.Println(t.Render())
+	fmt.Println()
+}
+
+// hours converts a duration to hours rounded to two decimals
+func hours(d time.Duration) float64 {
+	return float64(d.Round(36*time.Second)) / float64(time.Hour)
+}
+
+// writeTimeReportJSON writes a report with hours as decimals
+func writeTimeReportJSON(r timelog.Report) error {
+	type row struct {
+		Key   string             `json:"key"`
+		Hours float64            `json:"hours"`
+		Days  map[string]float64 `json:"days"`
+	}
+	out := struct {
+		From  string  `json:"from"`
+		To    string  `json:"to"`
+		By    string  `json:"by"`
+		Hours float64 `json:"hours"`
+		Rows  []row   `json:"rows"`
+	}{
+		From:  r.From.Format("2006-01-02"),
+		To:    r.To.Format("2006-01-02"),
+		By:    string(r.By),
+		Hours: hours(r.Total),
+		Rows:  []row{},
+	}
+	for _, rr := range r.Rows {
+		days := make(map[string]float64)
+		for i, d := range rr.Days {
+			if d > 0 {
+				days[r.Days[i].Format("2006-01-02")] = hours(d)
+			}
+		}
+		out.Rows = append(out.Rows, row{Key: rr.Key, Hours: hours(rr.Total), Days: days})
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// writeTimeReportCSV writes one record per group and day with time tracked
+func writeTimeReportCSV(r timelog.Report) error {
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write([]string{string(r.By), "date", "hours"}); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		for i, d := range row.Days {
+			if d == 0 {
+				continue
+			}
+			record := []string{row.Key, r.Days[i].Format("2006-01-02"), strconv.FormatFloat(hours(d), 'f', 2, 64)}
+			if err := w.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// findItemByTitle returns the pending or completed item with the given title, or nil
+func findItemByTitle(note *notes.Note, title string) *notes.WorkItem {
+	if i := note.FindPendingItem(title); i >= 0 {
+		return &note.PendingWork[i]
+	}
+	for i, item := range note.CompletedWork {
+		if item.Title() == title {
+			return &note.CompletedWork[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var tuiWorkplace string
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Manage today's work in a full-screen interface",
+	Long: `Open a full-screen interface with pending and completed work side by side.
+
+Add, edit, complete, delete and reorder items with the keyboard, switch between
+workplaces and days, and see the note's summaries as you work. The note reloads
+automatically when it is changed on disk, for example by Obsidian.
+
+Press ? inside the interface for the list of keys.`,
+	RunE: runTUI,
+}
+
+func init() {
+	tuiCmd.Flags().StringVarP(&tuiWorkplace, "workplace", "w", "", "Workplace to open first")
+	rootCmd.AddCommand(tuiCmd)
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	if tuiWorkplace != "" && !isWorkplace(tuiWorkplace) {
+		return fmt.Errorf("workplace '%s' not found", tuiWorkplace)
+	}
+	first := tuiWorkplace
+	if first == "" {
+		var err error
+		if first, _, err = currentWorkplace(); err != nil {
+			return err
+		}
+	}
+
+	current := 0
+	var workspaces []tui.Workspace
+	for i, wp := range cfg.Workplaces {
+		if wp == first {
+			current = i
+		}
+		workspaces = append(workspaces, tui.Workspace{
+			Name:   wp,
+			Parser: newParser(wp),
+			Writer: newWriter(wp),
+		})
+	}
+
+	return tui.Run(workspaces, current, todayFor(first), tuiNotifier())
+}
+
+// tuiNotifier runs hooks on the changes made in the TUI. Their output would
+// garble the screen, so it is dropped and failures go to the status line.
+func tuiNotifier() tui.Notifier {
+	if hookRunner == nil {
+		return nil
+	}
+	var warning string
+	hookRunner = hookRunner.WithOutput(io.Discard, func(msg string) { warning = msg })
+	return func(event, workplace string, note *notes.Note, items []notes.WorkItem) error {
+		warning = ""
+		if err := emitNote(event, workplace, note, items); err != nil {
+			return err
+		}
+		if warning != "" {
+			return errors.New(warning)
+		}
+		return nil
+	}
+}
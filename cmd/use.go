@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sandepten/work-obsidian-noter/internal/config"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var useClear bool
+
+var useCmd = &cobra.Command{
+	Use:   "use [workplace]",
+	Short: "Set the workplace of this terminal session",
+	Long: `Set the workplace commands use in this terminal session, so they stop asking.
+Without a workplace, show which one commands use and what picked it.
+
+When a command needs a workplace, the first of these that is set is used:
+
+  1. The --workplace flag, for commands that have one
+  2. The WORKLOG_WORKPLACE environment variable
+  3. 'worklog use' in this terminal session
+  4. A .worklog file in the current folder or a folder above it, holding the
+     workplace's name (like .git, so one in a repository covers all of it)
+  5. The default_workplace setting ('worklog config set default_workplace Acme')
+
+If none is set, you are asked.
+
+A terminal session is told apart by the process ID of its shell, so commands
+run from a subshell, a script or a tool like make don't see the workplace. To
+cover those as well, export the session ID 'worklog use' prints:
+
+  export WORKLOG_SESSION=12345
+
+Anything started from that shell then shares its workplace. Any other value,
+such as WORKLOG_SESSION=review, names a session that several terminals can
+share; it lasts until 'worklog use --clear'.
+
+Examples:
+  worklog use Acme
+  worklog use --clear
+  echo Acme > ~/code/acme/.worklog`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUse,
+}
+
+func init() {
+	useCmd.Flags().BoolVar(&useClear, "clear", false, "Stop using a workplace in this terminal session")
+	rootCmd.AddCommand(useCmd)
+}
+
+func runUse(cmd *cobra.Command, args []string) error {
+	if useClear {
+		if err := cfg.SetSessionWorkplace(""); err != nil {
+			return fmt.Errorf("error saving the session workplace: %w", err)
+		}
+		fmt.Println(ui.RenderSuccess("This terminal session no longer uses a workplace"))
+		return showCurrentWorkplace()
+	}
+
+	if len(args) == 0 {
+		return showCurrentWorkplace()
+	}
+
+	workplace, err := chooseWorkplace(args[0])
+	if err != nil {
+		return err
+	}
+	if err := cfg.SetSessionWorkplace(workplace); err != nil {
+		return fmt.Errorf("error saving the session workplace: %w", err)
+	}
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Using '%s' in this terminal session", workplace)))
+	if os.Getenv(config.SessionEnv) == "" {
+		fmt.Println(ui.MutedStyle.Render("  To use it in subshells and scripts too, run: export " + config.SessionEnv + "=" + config.SessionID()))
+	}
+
+	if env := os.Getenv(workplaceEnv); env != "" && env != workplace {
+		fmt.Println(ui.RenderWarning(fmt.Sprintf("$%s is set to '%s' and takes precedence", workplaceEnv, env)))
+	}
+	return nil
+}
+
+// showCurrentWorkplace prints the workplace commands use and what picked it
+func showCurrentWorkplace() error {
+	workplace, source, err := currentWorkplace()
+	if err != nil {
+		return err
+	}
+	if workplace == "" {
+		fmt.Println(ui.MutedStyle.Render("No workplace is set, so commands ask for one"))
+		return nil
+	}
+	fmt.Printf("%s %s\n", ui.InfoStyle.Render(workplace), ui.MutedStyle.Render("(from "+source+")"))
+	return nil
+}
@@ -6,7 +6,10 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/sandepten/work-obsidian-noter/internal/config"
+	"github.com/sandepten/work-obsidian-noter/internal/hooks"
 	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/timelog"
 	"github.com/sandepten/work-obsidian-noter/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -14,7 +17,7 @@ import (
 var workplaceCmd = &cobra.Command{
 	Use:   "workplace",
 	Short: "Manage workplaces",
-	Long:  `Manage your workplaces - add new workplaces or rename existing ones.`,
+	Long:  `Manage your workplaces - add, rename, archive, merge or remove them.`,
 }
 
 var workplaceAddCmd = &cobra.Command{
@@ -26,10 +29,16 @@ var workplaceAddCmd = &cobra.Command{
 }
 
 var workplaceRenameCmd = &cobra.Command{
-	Use:   "rename",
+	Use:   "rename [old] [new]",
 	Short: "Rename an existing workplace",
-	Long:  `Rename an existing workplace. This will also rename all associated note files.`,
-	RunE:  runWorkplaceRename,
+	Long: `Rename an existing workplace. Its notes are moved to their new names with
+the id and tags in their frontmatter updated, wiki-links to them anywhere in
+the vault are rewritten, and its recurring items, scheduled items and tracked
+time move along. The whole plan is shown first (use --dry-run to only see it,
+with a diff of every edited file) and applied as one step: if anything fails,
+every change is rolled back.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runWorkplaceRename,
 }
 
 var workplaceListCmd = &cobra.Command{
@@ -39,11 +48,83 @@ var workplaceListCmd = &cobra.Command{
 	RunE:  runWorkplaceList,
 }
 
+var workplaceRemoveCmd = &cobra.Command{
+	Use:   "remove [name]",
+	Short: "Remove a workplace",
+	Long: `Remove a workplace from your configuration.
+
+Its notes are kept where they are, moved to an Archive folder in the notes
+folder, or deleted, as chosen with --notes or when asked. Deleting the notes
+also deletes the workplace's recurring and scheduled items. Nothing is changed
+unless every step succeeds.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorkplaceRemove,
+}
+
+var workplaceArchiveCmd = &cobra.Command{
+	Use:   "archive [name]",
+	Short: "Hide a workplace while keeping its notes",
+	Long: `Archive a workplace. It is no longer offered when choosing a workplace, but
+its notes stay where they are and 'worklog search' still finds them. Use
+'worklog workplace unarchive' to bring it back.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorkplaceArchive,
+}
+
+var workplaceUnarchiveCmd = &cobra.Command{
+	Use:   "unarchive [name]",
+	Short: "Make an archived workplace active again",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runWorkplaceUnarchive,
+}
+
+var workplaceMergeCmd = &cobra.Command{
+	Use:   "merge <from> <into>",
+	Short: "Fold one workplace's notes into another",
+	Long: `Merge the notes of one workplace into another and remove the first.
+
+Notes move to the other workplace's folder and layout with their id and tags
+updated. When both workplaces have a note for the same day, the notes are
+combined item by item: items in both are kept once, and an item done in
+either note counts as done. Wiki-links to the moved notes are rewritten
+anywhere in the vault, recurring and scheduled items are merged and tracked
+time moves to the other workplace. Nothing is changed unless every step
+succeeds.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runWorkplaceMerge,
+}
+
+var (
+	workplaceNotes  string
+	workplaceDryRun bool
+	workplaceYes    bool
+)
+
+// Ways to deal with the notes of a removed workplace
+const (
+	notesKeep    = "keep"
+	notesArchive = "archive"
+	notesDelete  = "delete"
+)
+
+// archiveFolder is where the notes of removed workplaces can be moved, inside the notes folder
+const archiveFolder = "Archive"
+
 func init() {
 	rootCmd.AddCommand(workplaceCmd)
 	workplaceCmd.AddCommand(workplaceAddCmd)
 	workplaceCmd.AddCommand(workplaceRenameCmd)
 	workplaceCmd.AddCommand(workplaceListCmd)
+	workplaceCmd.AddCommand(workplaceRemoveCmd)
+	workplaceCmd.AddCommand(workplaceArchiveCmd)
+	workplaceCmd.AddCommand(workplaceUnarchiveCmd)
+	workplaceCmd.AddCommand(workplaceMergeCmd)
+
+	workplaceRemoveCmd.Flags().StringVar(&workplaceNotes, "notes", "", "What to do with its notes: keep, archive or delete")
+	for _, c := range []*cobra.Command{workplaceRenameCmd, workplaceRemoveCmd, workplaceMergeCmd} {
+		c.Flags().BoolVar(&workplaceDryRun, "dry-run", false, "Show the planned changes without making them")
+		c.Flags().BoolVarP(&workplaceYes, "yes", "y", false, "Don't ask for confirmation")
+	}
 }
 
 func runWorkplaceAdd(cmd *cobra.Command, args []string) error {
@@ -52,12 +133,8 @@ func runWorkplaceAdd(cmd *cobra.Command, args []string) error {
 
 	if len(args) > 0 {
 		workplaceName = strings.TrimSpace(args[0])
-		// Validate the name
-		if workplaceName == "" {
-			return fmt.Errorf("workplace name cannot be empty")
-		}
-		if strings.Contains(workplaceName, ",") {
-			return fmt.Errorf("workplace name cannot contain commas")
+		if err := validateWorkplaceName(workplaceName); err != nil {
+			return err
 		}
 	} else {
 		// Prompt for workplace name
@@ -71,6 +148,10 @@ func runWorkplaceAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if _, other := sharedNotePaths([]string{workplaceName}, layoutFor(workplaceName)); other != "" {
+		return fmt.Errorf("notes of %s would be written to the same files as %s's; add {workplace} to the note layout first", workplaceName, other)
+	}
+
 	// Add the workplace
 	if err := cfg.AddWorkplace(workplaceName); err != nil {
 		return fmt.Errorf("failed to add workplace: %w", err)
@@ -85,54 +166,170 @@ func runWorkplaceAdd(cmd *cobra.Command, args []string) error {
 }
 
 func runWorkplaceRename(cmd *cobra.Command, args []string) error {
-	// Select workplace to rename
-	oldName, err := prompter.SelectWorkplaceToRename(cfg.Workplaces)
-	if err != nil {
+	var oldName, newName string
+	var err error
+
+	if len(args) > 0 {
+		if !isWorkplace(args[0]) {
+			return fmt.Errorf("workplace '%s' not found", args[0])
+		}
+		oldName = args[0]
+	} else if oldName, err = prompter.SelectWorkplaceToRename(cfg.Workplaces); err != nil {
 		return fmt.Errorf("error selecting workplace: %w", err)
 	}
 
-	fmt.Println()
-	fmt.Println(ui.RenderInfo(fmt.Sprintf("Renaming workplace '%s'", oldName)))
+	if len(args) > 1 {
+		newName = strings.TrimSpace(args[1])
+	} else {
+		fmt.Println()
+		fmt.Println(ui.RenderInfo(fmt.Sprintf("Renaming workplace '%s'", oldName)))
 
-	// Prompt for new name
-	newName, err := prompter.PromptForWorkplaceName("New name")
-	if err != nil {
-		if err.Error() == "cancelled" {
-			fmt.Println(ui.RenderWarning("Cancelled"))
-			return nil
+		newName, err = prompter.PromptForWorkplaceName("New name")
+		if err != nil {
+			if err.Error() == "cancelled" {
+				fmt.Println(ui.RenderWarning("Cancelled"))
+				return nil
+			}
+			return fmt.Errorf("error getting new workplace name: %w", err)
 		}
-		return fmt.Errorf("error getting new workplace name: %w", err)
+	}
+	if err := validateWorkplaceName(newName); err != nil {
+		return err
+	}
+	if newName == oldName {
+		return fmt.Errorf("the workplace is already called '%s'", oldName)
 	}
 
-	// Confirm the rename with file updates
-	confirmed, err := prompter.ConfirmAction(fmt.Sprintf("Rename '%s' to '%s' and update all note files", oldName, newName))
+	tx, summary, err := planWorkplaceRename(oldName, newName)
 	if err != nil {
-		return fmt.Errorf("error confirming action: %w", err)
+		return err
 	}
-	if !confirmed {
-		fmt.Println(ui.RenderWarning("Cancelled"))
-		return nil
+
+	fmt.Println()
+	fmt.Println(ui.RenderInfo(fmt.Sprintf("Renaming '%s' to '%s' (%s)", oldName, newName, summary)))
+	printTransaction(tx)
+
+	applied, err := applyTransaction(tx, fmt.Sprintf("Rename '%s' to '%s' and update all note files", oldName, newName))
+	if err != nil || !applied {
+		return err
 	}
+	tx.PruneEmptyDirs(cfg.NotesDirFor(newName))
+	followSessions(oldName, newName)
 
-	// Rename files first
-	renamedCount, err := renameWorkplaceFiles(cfg.WorkNotesLocation, oldName, newName)
+	fmt.Println()
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Workplace renamed from '%s' to '%s'!", oldName, newName)))
+	fmt.Println(ui.MutedStyle.Render("  Updated " + summary))
+	fmt.Println()
+
+	return emit(hooks.Event{Event: hooks.WorkplaceRenamed, Workplace: newName, OldName: oldName, NewName: newName})
+}
+
+// planWorkplaceRename plans renaming a workplace: moving its notes to their new
+// paths with the id and tags in their frontmatter updated, rewriting wiki-links
+// to them anywhere in the vault, moving its recurring and scheduled items,
+// relabelling its tracked time and renaming it in the config. Files are only
+// changed once the transaction is committed.
+func planWorkplaceRename(oldName, newName string) (*notes.Transaction, string, error) {
+	notesDir := cfg.NotesDirFor(oldName)
+	layout := layoutFor(oldName)
+	files, err := notes.FindNoteFiles(notesDir, oldName, layout)
 	if err != nil {
-		return fmt.Errorf("error renaming files: %w", err)
+		return nil, "", fmt.Errorf("error finding notes for %s: %w", oldName, err)
 	}
 
-	// Update config
+	vault := notes.FindVaultRoot(notesDir)
+	renames := notes.NewLinkRenames()
+	newPaths := make(map[string]string)
+	for _, file := range files {
+		newPath := filepath.Join(notesDir, layout.Path(file.Date, newName))
+		newPaths[file.Path] = newPath
+
+		oldRel, err := filepath.Rel(vault, file.Path)
+		if err != nil {
+			return nil, "", err
+		}
+		newRel, err := filepath.Rel(vault, newPath)
+		if err != nil {
+			return nil, "", err
+		}
+		renames.Add(oldRel, newRel)
+	}
+
+	tx := notes.NewTransaction()
+	for _, file := range files {
+		data, err := os.ReadFile(file.Path)
+		if err != nil {
+			return nil, "", err
+		}
+		content := notes.RenameWorkplaceContent(string(data), oldName, newName)
+		content, _ = renames.Rewrite(content)
+
+		newPath := newPaths[file.Path]
+		if filepath.Clean(newPath) != filepath.Clean(file.Path) {
+			tx.Move(file.Path, newPath)
+		}
+		if content != string(data) {
+			tx.Write(newPath, []byte(content))
+		}
+	}
+
+	// Links to the renamed notes from the rest of the vault
+	linkFiles, links := 0, 0
+	if !renames.Empty() {
+		paths, err := notes.MarkdownFiles(vault)
+		if err != nil {
+			return nil, "", fmt.Errorf("error reading the vault: %w", err)
+		}
+		for _, path := range paths {
+			if _, renamed := newPaths[path]; renamed {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, "", err
+			}
+			if content, changed := renames.Rewrite(string(data)); changed > 0 {
+				tx.Write(path, []byte(content))
+				linkFiles++
+				links += changed
+			}
+		}
+	}
+
+	// Files named after the workplace follow the config to their new paths
+	oldRecurring, oldScheduled := cfg.RecurringFileFor(oldName), cfg.ScheduledFileFor(oldName)
 	if err := cfg.RenameWorkplace(oldName, newName); err != nil {
-		return fmt.Errorf("failed to rename workplace in config: %w", err)
+		return nil, "", fmt.Errorf("failed to rename workplace in config: %w", err)
+	}
+	for _, move := range []notes.FileMove{
+		{From: oldRecurring, To: cfg.RecurringFileFor(newName)},
+		{From: oldScheduled, To: cfg.ScheduledFileFor(newName)},
+	} {
+		if _, err := os.Stat(move.From); err == nil && move.From != move.To {
+			tx.Move(move.From, move.To)
+		}
 	}
 
-	fmt.Println()
-	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Workplace renamed from '%s' to '%s'!", oldName, newName)))
-	if renamedCount > 0 {
-		fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("  Renamed %d note file(s)", renamedCount)))
+	data, events, err := timelog.Open(cfg.TimeLogPath()).RenameWorkplace(oldName, newName)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading the time log: %w", err)
+	}
+	if events > 0 {
+		tx.Write(cfg.TimeLogPath(), data)
 	}
-	fmt.Println()
 
-	return nil
+	if err := writeConfigIn(tx); err != nil {
+		return nil, "", err
+	}
+
+	summary := fmt.Sprintf("%d note(s)", len(files))
+	if links > 0 {
+		summary += fmt.Sprintf(", %d link(s) in %d other file(s)", links, linkFiles)
+	}
+	if events > 0 {
+		summary += fmt.Sprintf(", %d tracked time event(s)", events)
+	}
+	return tx, summary, nil
 }
 
 func runWorkplaceList(cmd *cobra.Command, args []string) error {
@@ -148,63 +345,499 @@ func runWorkplaceList(cmd *cobra.Command, args []string) error {
 	for i, wp := range cfg.Workplaces {
 		fmt.Printf("  %d. %s\n", i+1, ui.SuccessStyle.Render(wp))
 	}
+	for _, wp := range cfg.Archived {
+		fmt.Printf("  %s %s\n", ui.MutedStyle.Render("-"), ui.MutedStyle.Render(wp+" (archived)"))
+	}
 	fmt.Println()
 
 	return nil
 }
 
-// renameWorkplaceFiles renames all note files for a workplace
-func renameWorkplaceFiles(notesDir, oldName, newName string) (int, error) {
-	renamedCount := 0
+// workplaceArg returns the workplace named by an argument, or asks for one of choices
+func workplaceArg(args []string, label string, choices []string) (string, error) {
+	if len(args) > 0 {
+		if !contains(choices, args[0]) {
+			return "", fmt.Errorf("workplace '%s' not found", args[0])
+		}
+		return args[0], nil
+	}
+	if len(choices) == 0 {
+		return "", fmt.Errorf("no workplaces to choose from")
+	}
+
+	index, err := prompter.SelectFromList(label, choices)
+	if err != nil {
+		return "", fmt.Errorf("error selecting workplace: %w", err)
+	}
+	return choices[index], nil
+}
+
+func runWorkplaceArchive(cmd *cobra.Command, args []string) error {
+	name, err := workplaceArg(args, "Workplace to archive", cfg.Workplaces)
+	if err != nil {
+		return err
+	}
+	if err := cfg.ArchiveWorkplace(name); err != nil {
+		return fmt.Errorf("failed to archive workplace: %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to archive workplace: %w", err)
+	}
+	followSessions(name, "")
 
-	// Find all files matching the pattern *-OldName.md
-	pattern := fmt.Sprintf("*-%s.md", oldName)
-	matches, err := filepath.Glob(filepath.Join(notesDir, pattern))
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Archived '%s'. Its notes are kept and still show up in 'worklog search'.", name)))
+	return nil
+}
+
+func runWorkplaceUnarchive(cmd *cobra.Command, args []string) error {
+	name, err := workplaceArg(args, "Workplace to unarchive", cfg.Archived)
 	if err != nil {
-		return 0, fmt.Errorf("error finding files: %w", err)
+		return err
+	}
+	if err := cfg.UnarchiveWorkplace(name); err != nil {
+		return fmt.Errorf("failed to unarchive workplace: %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to unarchive workplace: %w", err)
 	}
 
-	for _, oldPath := range matches {
-		// Get the filename
-		filename := filepath.Base(oldPath)
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("'%s' is active again", name)))
+	return nil
+}
 
-		// Replace the workplace name in the filename
-		newFilename := strings.Replace(filename, fmt.Sprintf("-%s.md", oldName), fmt.Sprintf("-%s.md", newName), 1)
-		newPath := filepath.Join(notesDir, newFilename)
+func runWorkplaceRemove(cmd *cobra.Command, args []string) error {
+	name, err := workplaceArg(args, "Workplace to remove", cfg.AllWorkplaces())
+	if err != nil {
+		return err
+	}
 
-		// Rename the file
-		if err := os.Rename(oldPath, newPath); err != nil {
-			return renamedCount, fmt.Errorf("error renaming file %s: %w", filename, err)
+	mode := workplaceNotes
+	if mode == "" {
+		choices := []string{"Keep them where they are", "Move them to the " + archiveFolder + " folder", "Delete them"}
+		index, err := prompter.SelectFromList(fmt.Sprintf("What should happen to the notes of '%s'", name), choices)
+		if err != nil {
+			return fmt.Errorf("error selecting an option: %w", err)
 		}
+		mode = []string{notesKeep, notesArchive, notesDelete}[index]
+	}
+	if mode != notesKeep && mode != notesArchive && mode != notesDelete {
+		return fmt.Errorf("invalid --notes %q: use keep, archive or delete", mode)
+	}
+
+	notesDir := cfg.NotesDirFor(name)
+	files, err := notes.FindNoteFiles(notesDir, name, layoutFor(name))
+	if err != nil {
+		return fmt.Errorf("error finding notes for %s: %w", name, err)
+	}
 
-		// Update file content (ID and tags)
-		if err := updateNoteContent(newPath, oldName, newName); err != nil {
-			// Log warning but don't fail
-			fmt.Println(ui.RenderWarning(fmt.Sprintf("Warning: could not update content in %s: %v", newFilename, err)))
+	// Plan every change, the config included, before touching anything
+	tx := notes.NewTransaction()
+	for _, file := range files {
+		switch mode {
+		case notesArchive:
+			rel, err := filepath.Rel(notesDir, file.Path)
+			if err != nil {
+				return err
+			}
+			tx.Move(file.Path, filepath.Join(notesDir, archiveFolder, name, rel))
+		case notesDelete:
+			tx.Remove(file.Path)
+		}
+	}
+	if mode == notesDelete {
+		for _, path := range []string{cfg.RecurringFileFor(name), cfg.ScheduledFileFor(name)} {
+			if _, err := os.Stat(path); err == nil {
+				tx.Remove(path)
+			}
 		}
+	}
+
+	if err := cfg.RemoveWorkplace(name); err != nil {
+		return fmt.Errorf("failed to remove workplace: %w", err)
+	}
+	if err := writeConfigIn(tx); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(ui.RenderInfo(fmt.Sprintf("Removing workplace '%s' (%d note(s), %s)", name, len(files), mode)))
+	printTransaction(tx)
 
-		renamedCount++
+	applied, err := applyTransaction(tx, fmt.Sprintf("Remove '%s'", name))
+	if err != nil || !applied {
+		return err
 	}
+	tx.PruneEmptyDirs(notesDir)
+	followSessions(name, "")
 
-	return renamedCount, nil
+	fmt.Println()
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Removed workplace '%s'", name)))
+	switch mode {
+	case notesKeep:
+		if len(files) > 0 {
+			fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("  Its %d note(s) were kept in %s", len(files), notesDir)))
+		}
+	case notesArchive:
+		fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("  Moved %d note(s) to %s", len(files), filepath.Join(notesDir, archiveFolder, name))))
+	case notesDelete:
+		fmt.Println(ui.MutedStyle.Render(fmt.Sprintf("  Deleted %d note(s)", len(files))))
+	}
+	fmt.Println()
+	return nil
 }
 
-// updateNoteContent updates the workplace references inside a note file
-func updateNoteContent(filePath, oldName, newName string) error {
-	content, err := os.ReadFile(filePath)
+func runWorkplaceMerge(cmd *cobra.Command, args []string) error {
+	from, into := args[0], args[1]
+	all := cfg.AllWorkplaces()
+	for _, wp := range []string{from, into} {
+		if !contains(all, wp) {
+			return fmt.Errorf("workplace '%s' not found", wp)
+		}
+	}
+	if from == into {
+		return fmt.Errorf("can't merge a workplace into itself")
+	}
+
+	fromDir := cfg.NotesDirFor(from)
+	files, err := notes.FindNoteFiles(fromDir, from, layoutFor(from))
+	if err != nil {
+		return fmt.Errorf("error finding notes for %s: %w", from, err)
+	}
+
+	fromParser := newParser(from)
+	intoParser := newParser(into)
+	intoWriter := newWriter(into)
+
+	// Plan every change, the config included, before touching anything
+	vault := notes.FindVaultRoot(fromDir)
+	renames := notes.NewLinkRenames()
+	moved := make(map[string]string) // Old note path -> note it went into
+	var targets []*notes.Note
+	combined := 0
+	for _, file := range files {
+		note, err := fromParser.ParseFile(file.Path)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", relToNotes(file.Path), err)
+		}
+
+		target, err := intoParser.FindTodayNote(file.Date)
+		if err != nil {
+			return fmt.Errorf("error finding note for %s: %w", file.Date.Format("2006-01-02"), err)
+		}
+		note.RenameWorkplace(from, into)
+		if target != nil {
+			target.Merge(note)
+			combined++
+		} else {
+			note.FilePath = intoWriter.NotePath(file.Date)
+			target = note
+		}
+		targets = append(targets, target)
+		moved[file.Path] = target.FilePath
+
+		oldRel, err := filepath.Rel(vault, file.Path)
+		if err != nil {
+			return err
+		}
+		newRel, err := filepath.Rel(vault, target.FilePath)
+		if err != nil {
+			return err
+		}
+		renames.Add(oldRel, newRel)
+	}
+
+	tx := notes.NewTransaction()
+	written := make(map[string]bool)
+	for i, target := range targets {
+		content, _ := renames.Rewrite(string(intoWriter.Markdown(target)))
+		tx.Write(target.FilePath, []byte(content))
+		tx.Remove(files[i].Path)
+		written[target.FilePath] = true
+	}
+
+	// Links to the merged notes from the rest of the vault
+	linkFiles, links := 0, 0
+	if !renames.Empty() {
+		paths, err := notes.MarkdownFiles(vault)
+		if err != nil {
+			return fmt.Errorf("error reading the vault: %w", err)
+		}
+		for _, path := range paths {
+			if _, merged := moved[path]; merged || written[path] {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if content, changed := renames.Rewrite(string(data)); changed > 0 {
+				tx.Write(path, []byte(content))
+				linkFiles++
+				links += changed
+			}
+		}
+	}
+
+	if err := mergeRecurring(tx, from, into); err != nil {
+		return err
+	}
+	if err := mergeScheduled(tx, from, into, moved); err != nil {
+		return err
+	}
+
+	data, events, err := timelog.Open(cfg.TimeLogPath()).RenameWorkplace(from, into)
+	if err != nil {
+		return fmt.Errorf("error reading the time log: %w", err)
+	}
+	if events > 0 {
+		tx.Write(cfg.TimeLogPath(), data)
+	}
+
+	if err := cfg.RemoveWorkplace(from); err != nil {
+		return fmt.Errorf("failed to remove workplace: %w", err)
+	}
+	if err := writeConfigIn(tx); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	summary := fmt.Sprintf("%d note(s), %d combined with existing notes", len(files), combined)
+	if links > 0 {
+		summary += fmt.Sprintf(", %d link(s) in %d other file(s)", links, linkFiles)
+	}
+	if events > 0 {
+		summary += fmt.Sprintf(", %d tracked time event(s)", events)
+	}
+
+	fmt.Println()
+	fmt.Println(ui.RenderInfo(fmt.Sprintf("Merging '%s' into '%s' (%s)", from, into, summary)))
+	printTransaction(tx)
+
+	applied, err := applyTransaction(tx, fmt.Sprintf("Merge '%s' into '%s'", from, into))
+	if err != nil || !applied {
+		return err
+	}
+	tx.PruneEmptyDirs(fromDir)
+	followSessions(from, into)
+
+	fmt.Println()
+	fmt.Println(ui.RenderSuccess(fmt.Sprintf("Merged '%s' into '%s'", from, into)))
+	fmt.Println(ui.MutedStyle.Render("  Updated " + summary))
+	fmt.Println()
+	return nil
+}
+
+// mergeRecurring appends the recurring items of one workplace to another's
+func mergeRecurring(tx *notes.Transaction, from, into string) error {
+	fromPath, intoPath := cfg.RecurringFileFor(from), cfg.RecurringFileFor(into)
+	if fromPath == intoPath {
+		return nil
+	}
+	data, err := os.ReadFile(fromPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	existing, err := os.ReadFile(intoPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		existing = append(existing, '\n')
+	}
+	tx.Write(intoPath, append(existing, data...))
+	tx.Remove(fromPath)
+	return nil
+}
+
+// mergeScheduled moves the deferred items of one workplace to another's
+// ledger, pointing the notes they were deferred from at the notes those went into
+func mergeScheduled(tx *notes.Transaction, from, into string, moved map[string]string) error {
+	fromPath := cfg.ScheduledFileFor(from)
+	if _, err := os.Stat(fromPath); os.IsNotExist(err) {
+		return nil
+	}
+	fromLedger, err := notes.LoadLedger(fromPath)
 	if err != nil {
 		return err
 	}
+	intoLedger, err := notes.LoadLedger(cfg.ScheduledFileFor(into))
+	if err != nil {
+		return err
+	}
+
+	for i, item := range fromLedger.Items {
+		for oldPath, newPath := range moved {
+			if item.From == relToNotes(oldPath) {
+				fromLedger.Items[i].From = relToNotes(newPath)
+			}
+		}
+	}
+	intoLedger.Merge(fromLedger)
+	data, err := intoLedger.Bytes()
+	if err != nil {
+		return err
+	}
+	tx.Write(cfg.ScheduledFileFor(into), data)
+	tx.Remove(fromPath)
+	return nil
+}
+
+// writeConfigIn adds saving the changed config to a transaction
+func writeConfigIn(tx *notes.Transaction) error {
+	data, err := cfg.Contents()
+	if err != nil {
+		return err
+	}
+	tx.Write(config.GetConfigPath(), data)
+	return nil
+}
+
+// followSessions moves the terminal sessions using a workplace, chosen with
+// 'worklog use', to its new name, or clears them for an empty name
+func followSessions(oldName, newName string) {
+	if err := cfg.RenameSessionWorkplace(oldName, newName); err != nil {
+		fmt.Println(ui.RenderWarning(fmt.Sprintf("Couldn't update 'worklog use' sessions: %v", err)))
+	}
+}
+
+// printTransaction lists the changes a transaction will make
+func printTransaction(tx *notes.Transaction) {
+	fmt.Println()
+	for _, m := range tx.Moves() {
+		fmt.Printf("  %s %s %s\n", relToNotes(m.From), ui.MutedStyle.Render(ui.IconArrow), ui.InfoStyle.Render(relToNotes(m.To)))
+	}
+	for _, w := range tx.Writes() {
+		fmt.Printf("  %s %s\n", ui.WarningStyle.Render("✎"), relToNotes(w.Path))
+		if workplaceDryRun {
+			printWriteDiff(tx, w)
+		}
+	}
+	for _, path := range tx.Removals() {
+		fmt.Printf("  %s %s\n", ui.ErrorStyle.Render(ui.IconError), relToNotes(path))
+	}
+	fmt.Println()
+}
+
+// printWriteDiff shows the lines a planned write changes. A file that is also
+// moved is compared with its content before the move.
+func printWriteDiff(tx *notes.Transaction, w notes.FileWrite) {
+	source := w.Path
+	for _, m := range tx.Moves() {
+		if m.To == w.Path {
+			source = m.From
+		}
+	}
+	old, _ := os.ReadFile(source)
+	for _, line := range diffLines(string(old), string(w.Data)) {
+		if strings.HasPrefix(line, "-") {
+			fmt.Println("      " + ui.ErrorStyle.Render(line))
+		} else {
+			fmt.Println("      " + ui.SuccessStyle.Render(line))
+		}
+	}
+}
 
-	contentStr := string(content)
+// diffLines returns the lines removed from old, prefixed with "-", and added
+// in new, prefixed with "+", in file order
+func diffLines(old, new string) []string {
+	a, b := strings.Split(old, "\n"), strings.Split(new, "\n")
 
-	// Update the ID (WorkplaceName-D-Mon-YYYY)
-	contentStr = strings.ReplaceAll(contentStr, oldName+"-", newName+"-")
+	// Only the middle between a shared start and end needs comparing
+	start := 0
+	for start < len(a) && start < len(b) && a[start] == b[start] {
+		start++
+	}
+	end := 0
+	for end < len(a)-start && end < len(b)-start && a[len(a)-1-end] == b[len(b)-1-end] {
+		end++
+	}
+	a, b = a[start:len(a)-end], b[start:len(b)-end]
 
-	// Update the tags (lowercase workplace name)
-	oldTag := notes.ToLowerCase(oldName)
-	newTag := notes.ToLowerCase(newName)
-	contentStr = strings.ReplaceAll(contentStr, fmt.Sprintf("- %s", oldTag), fmt.Sprintf("- %s", newTag))
+	// Longest common subsequence of the remaining lines
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "- "+a[i])
+			i++
+		default:
+			diff = append(diff, "+ "+b[j])
+			j++
+		}
+	}
+	return diff
+}
 
-	return os.WriteFile(filePath, []byte(contentStr), 0644)
+// applyTransaction asks for confirmation and commits a transaction, or only
+// reports it with --dry-run. It reports whether the changes were made.
+func applyTransaction(tx *notes.Transaction, action string) (bool, error) {
+	if err := tx.Validate(); err != nil {
+		return false, fmt.Errorf("cannot continue: %w", err)
+	}
+	if workplaceDryRun {
+		fmt.Println(ui.MutedStyle.Render("Dry run: nothing was changed."))
+		fmt.Println()
+		return false, nil
+	}
+
+	if !workplaceYes {
+		confirmed, err := prompter.ConfirmAction(action)
+		if err != nil {
+			return false, fmt.Errorf("error confirming action: %w", err)
+		}
+		if !confirmed {
+			fmt.Println(ui.RenderWarning("Cancelled"))
+			return false, nil
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("%w; nothing was changed", err)
+	}
+	return true, nil
+}
+
+// validateWorkplaceName checks that a workplace name can be used in note file names
+func validateWorkplaceName(name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return fmt.Errorf("workplace name cannot be empty")
+	case name != strings.TrimSpace(name):
+		return fmt.Errorf("workplace name cannot start or end with spaces")
+	case strings.Contains(name, ","):
+		return fmt.Errorf("workplace name cannot contain commas")
+	case name == "." || name == "..":
+		return fmt.Errorf("workplace name cannot be %q", name)
+	}
+	if i := strings.IndexAny(name, `/\:*?"<>|`); i >= 0 {
+		return fmt.Errorf("workplace name cannot contain %q", name[i])
+	}
+	for _, r := range name {
+		if r < ' ' {
+			return fmt.Errorf("workplace name cannot contain control characters")
+		}
+	}
+	return nil
 }
@@ -1,11 +1,14 @@
 package config
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Config holds the application configuration
@@ -13,91 +16,262 @@ type Config struct {
 	WorkNotesLocation string
 	WorkplaceName     string   // Default workplace (for backward compatibility)
 	Workplaces        []string // List of available workplaces
+	Archived          []string // Workplaces hidden from the choices, whose notes are kept
 	OpenCodeServer    string
 	AIProvider        string
 	AIModel           string
+	NoteLayout        string // Default note path layout (see notes.Layout)
+	NoteTemplate      string // Template file new notes are rendered from, empty for the built-in note
+	Locale            string // Language for UI strings and default note markers (en, de, ja)
+	DefaultWorkplace  string // Workplace used without asking, empty to ask
+
+	Profile      string   // Active profile, empty for the default one
+	MigratedFrom string   // Legacy config file migrated to config.yaml by Load, if any
+	MovedState   []string // Files Load moved from the config folder to StateDir
+
+	doc  *Document
+	file *File
+}
+
+// MarkerConfig holds the configured note markers of a workplace. Each list has
+// the canonical form first followed by aliases; empty lists use the locale's defaults.
+type MarkerConfig struct {
+	Locale           string
+	Pending          []string
+	Completed        []string
+	Summary          []string
+	YesterdaySummary []string
 }
 
-// Load reads the configuration from ~/.config/worklog/config
+// AIConfig holds the summary settings of a workplace
+type AIConfig struct {
+	Server   string
+	Provider string
+	Model    string
+	Prompt   string // Empty for the built-in prompt
+	// Name or path of a summarizer plugin used instead of the server; empty for the server
+	Summarizer string
+}
+
+// Load reads the configuration of the active profile, from
+// $XDG_CONFIG_HOME/worklog/config.yaml for the default one, or the file given
+// with SetConfigFile or WORKLOG_CONFIG. A legacy KEY=value config file is
+// migrated first if there is one, and data kept next to the config by older
+// versions is moved to the state folder. Environment variables override the file.
 func Load() (*Config, error) {
-	// Load config from ~/.config/worklog/config
+	profileName := ActiveProfile()
+	if profileName != "" {
+		if err := ValidateProfileName(profileName); err != nil {
+			return nil, err
+		}
+		if !explicitConfig() && !ProfileExists(profileName) {
+			return nil, fmt.Errorf("profile '%s' not found (create it with 'worklog profile create %s')", profileName, profileName)
+		}
+	}
 	configPath := getConfigPath()
-	loadConfigFile(configPath)
-
-	workplaceName := getEnv("WORKPLACE_NAME", "Work")
-	workplacesStr := getEnv("WORKPLACES", "")
-
-	// Parse workplaces list
-	var workplaces []string
-	if workplacesStr != "" {
-		for _, wp := range strings.Split(workplacesStr, ",") {
-			wp = strings.TrimSpace(wp)
-			if wp != "" {
-				workplaces = append(workplaces, wp)
+
+	migrated := ""
+	if _, err := os.Stat(configPath); os.IsNotExist(err) && profileName == "" && !explicitConfig() {
+		legacyPath := legacyConfigPath()
+		if _, err := os.Stat(legacyPath); err == nil {
+			if err := MigrateLegacy(legacyPath, configPath); err != nil {
+				return nil, fmt.Errorf("failed to migrate %s: %w", legacyPath, err)
 			}
+			migrated = legacyPath
 		}
 	}
+	movedState, err := migrateState()
+	if err != nil {
+		return nil, err
+	}
 
-	// If no workplaces defined, use the default workplace name
-	if len(workplaces) == 0 {
-		workplaces = []string{workplaceName}
+	doc, err := LoadDocument(configPath)
+	if err != nil {
+		return nil, err
+	}
+	file, err := doc.Decode()
+	if err != nil {
+		return nil, err
 	}
 
-	cfg := &Config{
-		WorkNotesLocation: getEnv("WORK_NOTES_LOCATION", "~/Documents/obsidian-notes/Inbox/work"),
-		WorkplaceName:     workplaceName,
-		Workplaces:        workplaces,
-		OpenCodeServer:    getEnv("OPENCODE_SERVER", "http://127.0.0.1:4096"),
-		AIProvider:        getEnv("AI_PROVIDER", "github-copilot"),
-		AIModel:           getEnv("AI_MODEL", "claude-sonnet-4"),
+	cfg := &Config{Profile: profileName, MigratedFrom: migrated, MovedState: movedState, doc: doc, file: file}
+
+	// Workplaces are the sections of the file, in file order
+	workplaces := splitList(getEnv("WORKPLACES", ""))
+	if len(workplaces) == 0 {
+		for _, wp := range doc.Keys([]string{"workplaces"}) {
+			if section := file.Workplaces[wp]; section != nil && section.Archived {
+				cfg.Archived = append(cfg.Archived, wp)
+				continue
+			}
+			workplaces = append(workplaces, wp)
+		}
+	}
+	if len(workplaces) == 0 {
+		workplaces = []string{getEnv("WORKPLACE_NAME", "Work")}
 	}
 
-	// Expand ~ in the path
-	cfg.WorkNotesLocation = expandPath(cfg.WorkNotesLocation)
+	cfg.Workplaces = workplaces
+	cfg.WorkplaceName = getEnv("WORKPLACE_NAME", workplaces[0])
+	cfg.resolve()
 
 	return cfg, nil
 }
 
-// getConfigPath returns the path to the config file
-func getConfigPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return ""
+// resolve reads the global settings kept on Config from the file and environment
+func (c *Config) resolve() {
+	c.WorkNotesLocation = expandPath(c.value("WORK_NOTES_LOCATION", ""))
+	c.OpenCodeServer = c.value("OPENCODE_SERVER", "")
+	c.AIProvider = c.value("AI_PROVIDER", "")
+	c.AIModel = c.value("AI_MODEL", "")
+	c.NoteLayout = c.value("NOTE_LAYOUT", "")
+	c.NoteTemplate = c.value("NOTE_TEMPLATE", "")
+	c.Locale = c.value("LOCALE", "")
+	c.DefaultWorkplace = c.value("DEFAULT_WORKPLACE", "")
+}
+
+// value returns a setting by its environment variable name, or the option's
+// default when it isn't set
+func (c *Config) value(env, workplace string) string {
+	opt := FindOption(env)
+	if value, source := c.Lookup(opt, workplace); source != SourceDefault {
+		return value
 	}
-	return filepath.Join(home, ".config", "worklog", "config")
+	return opt.Default
 }
 
-// loadConfigFile reads a key=value config file and sets environment variables
-func loadConfigFile(path string) {
-	file, err := os.Open(path)
+// Source tells where the value of a setting comes from
+type Source string
+
+const (
+	SourceDefault              Source = "default"
+	SourceFile                 Source = "config file"
+	SourceWorkplace            Source = "workplace section"
+	SourceEnvironment          Source = "environment"
+	SourceWorkplaceEnvironment Source = "workplace environment"
+)
+
+// Lookup returns the value of a setting for a workplace and where it comes
+// from. A workplace's environment variable and section come first, then the
+// global variable and setting. An empty workplace looks up the global value.
+func (c *Config) Lookup(opt *Option, workplace string) (string, Source) {
+	if workplace != "" && !opt.Global() {
+		if value, exists := os.LookupEnv(opt.WorkplaceEnv(workplace)); exists {
+			return value, SourceWorkplaceEnvironment
+		}
+		if section := c.file.Workplaces[workplace]; section != nil {
+			if value := opt.get(&section.Settings); value != "" {
+				return value, SourceWorkplace
+			}
+		}
+	}
+	if value, exists := os.LookupEnv(opt.Env); exists {
+		return value, SourceEnvironment
+	}
+	if value := opt.fileValue(c.file); value != "" {
+		return value, SourceFile
+	}
+	return "", SourceDefault
+}
+
+// Set changes a setting in the config file, in a workplace's section or
+// globally for an empty workplace. Call Save to write the file.
+func (c *Config) Set(opt *Option, workplace, value string) error {
+	if opt.Int {
+		if n, err := strconv.Atoi(strings.TrimSpace(value)); err != nil || n <= 0 {
+			return fmt.Errorf("%s must be a positive whole number, got %q", opt.Key, value)
+		}
+	}
+
+	path, err := c.settingPath(opt, workplace)
 	if err != nil {
-		return // Config file doesn't exist, use defaults
+		return err
 	}
-	defer file.Close()
+	if err := c.doc.Set(path, opt.node(value)); err != nil {
+		return err
+	}
+	return c.reload()
+}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+// Unset removes a setting from the config file and reports whether it was set.
+// Call Save to write the file.
+func (c *Config) Unset(opt *Option, workplace string) (bool, error) {
+	path, err := c.settingPath(opt, workplace)
+	if err != nil {
+		return false, err
+	}
+	if !c.doc.Unset(path) {
+		return false, nil
+	}
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+	// Drop sections left empty, such as ai: after unsetting ai.model
+	for len(path) > 1 {
+		path = path[:len(path)-1]
+		if node := c.doc.Lookup(path); node == nil || len(node.Content) > 0 || (len(path) == 2 && path[0] == "workplaces") {
+			break
 		}
+		c.doc.Unset(path)
+	}
+	return true, c.reload()
+}
 
-		// Parse key=value
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue
-		}
+// Save writes the config file
+func (c *Config) Save() error {
+	return c.doc.Save()
+}
+
+// settingPath returns the keys of a setting in the file
+func (c *Config) settingPath(opt *Option, workplace string) ([]string, error) {
+	if workplace == "" {
+		return opt.Path(), nil
+	}
+	if opt.Global() {
+		return nil, fmt.Errorf("%s can only be set globally", opt.Key)
+	}
+	if !contains(c.Workplaces, workplace) {
+		return nil, fmt.Errorf("workplace '%s' not found", workplace)
+	}
+	c.ensureSections()
+	return append([]string{"workplaces", workplace}, opt.Path()...), nil
+}
 
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
+// reload decodes the edited document again so lookups see the change
+func (c *Config) reload() error {
+	file, err := c.doc.Decode()
+	if err != nil {
+		return err
+	}
+	c.file = file
+	c.resolve()
+	return nil
+}
+
+// splitList splits a comma-separated config value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
 
-		// Only set if not already set in environment
-		if _, exists := os.LookupEnv(key); !exists {
-			os.Setenv(key, value)
+// workplaceKey returns the per-workplace environment variable of a setting,
+// e.g. NOTE_LAYOUT for "My Team" becomes NOTE_LAYOUT_MY_TEAM
+func workplaceKey(key, workplace string) string {
+	var sb strings.Builder
+	sb.WriteString(key)
+	sb.WriteString("_")
+	for _, r := range strings.ToUpper(workplace) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
 		}
 	}
+	return sb.String()
 }
 
 // getEnv retrieves an environment variable or returns a default value
@@ -120,104 +294,315 @@ func expandPath(path string) string {
 	return path
 }
 
-// EnsureNotesDirectory creates the notes directory if it doesn't exist
+// EnsureNotesDirectory creates the notes directories if they don't exist
 func (c *Config) EnsureNotesDirectory() error {
-	return os.MkdirAll(c.WorkNotesLocation, 0755)
+	if err := os.MkdirAll(c.WorkNotesLocation, 0755); err != nil {
+		return err
+	}
+	for _, wp := range c.Workplaces {
+		if err := os.MkdirAll(c.NotesDirFor(wp), 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AllWorkplaces returns the active workplaces followed by the archived ones
+func (c *Config) AllWorkplaces() []string {
+	return append(append([]string{}, c.Workplaces...), c.Archived...)
 }
 
 // AddWorkplace adds a new workplace to the config and saves it
 func (c *Config) AddWorkplace(name string) error {
 	// Check if workplace already exists
-	for _, wp := range c.Workplaces {
+	for _, wp := range c.AllWorkplaces() {
 		if strings.EqualFold(wp, name) {
 			return fmt.Errorf("workplace '%s' already exists", name)
 		}
 	}
 
-	// Add to the list
+	c.ensureSections()
+	if err := c.doc.Set([]string{"workplaces", name}, emptySection()); err != nil {
+		return err
+	}
 	c.Workplaces = append(c.Workplaces, name)
 
-	// Save to config file
-	return c.saveWorkplaces()
+	return c.doc.Save()
 }
 
-// RenameWorkplace renames an existing workplace in the config and saves it
+// RenameWorkplace renames an existing workplace in the config. The
+// workplace's section keeps its settings and comments. Call Save or write
+// Contents to keep the change.
 func (c *Config) RenameWorkplace(oldName, newName string) error {
 	// Check if new name already exists
-	for _, wp := range c.Workplaces {
-		if strings.EqualFold(wp, newName) {
+	for _, wp := range c.AllWorkplaces() {
+		if strings.EqualFold(wp, newName) && wp != oldName {
 			return fmt.Errorf("workplace '%s' already exists", newName)
 		}
 	}
 
-	// Find and rename
-	found := false
+	index := -1
 	for i, wp := range c.Workplaces {
 		if wp == oldName {
-			c.Workplaces[i] = newName
-			found = true
+			index = i
 			break
 		}
 	}
-
-	if !found {
+	if index < 0 {
 		return fmt.Errorf("workplace '%s' not found", oldName)
 	}
 
-	// Save to config file
-	return c.saveWorkplaces()
+	c.ensureSections()
+	c.Workplaces[index] = newName
+	c.doc.RenameKey([]string{"workplaces"}, oldName, newName)
+	if c.WorkplaceName == oldName {
+		c.WorkplaceName = newName
+	}
+	if c.file.DefaultWorkplace == oldName {
+		c.doc.Set([]string{"default_workplace"}, stringNode(newName))
+	}
+	return c.reload()
 }
 
-// saveWorkplaces writes the updated workplaces list to the config file
-func (c *Config) saveWorkplaces() error {
-	configPath := getConfigPath()
+// ArchiveWorkplace hides a workplace from the workplace choices while keeping
+// its section and notes. Call Save or write Contents to keep the change.
+func (c *Config) ArchiveWorkplace(name string) error {
+	if !contains(c.Workplaces, name) {
+		return fmt.Errorf("workplace '%s' not found", name)
+	}
+	if len(c.Workplaces) == 1 {
+		return fmt.Errorf("'%s' is the only active workplace", name)
+	}
 
-	// Ensure config directory exists
-	configDir := filepath.Dir(configPath)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
+	c.ensureSections()
+	if err := c.doc.Set([]string{"workplaces", name, "archived"}, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"}); err != nil {
+		return err
 	}
+	c.Workplaces = remove(c.Workplaces, name)
+	c.Archived = append(c.Archived, name)
+	c.dropDefault(name)
+	return c.reload()
+}
 
-	// Read existing config file content
-	existingContent := make(map[string]string)
-	if file, err := os.Open(configPath); err == nil {
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			line := strings.TrimSpace(scanner.Text())
-			if line == "" || strings.HasPrefix(line, "#") {
-				continue
-			}
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) == 2 {
-				key := strings.TrimSpace(parts[0])
-				value := strings.TrimSpace(parts[1])
-				existingContent[key] = value
-			}
+// UnarchiveWorkplace makes an archived workplace active again. Call Save or
+// write Contents to keep the change.
+func (c *Config) UnarchiveWorkplace(name string) error {
+	if !contains(c.Archived, name) {
+		return fmt.Errorf("workplace '%s' is not archived", name)
+	}
+
+	c.doc.Unset([]string{"workplaces", name, "archived"})
+	c.Archived = remove(c.Archived, name)
+	c.Workplaces = append(c.Workplaces, name)
+	return c.reload()
+}
+
+// RemoveWorkplace removes a workplace and its settings from the config. Call
+// Save or write Contents to keep the change.
+func (c *Config) RemoveWorkplace(name string) error {
+	switch {
+	case contains(c.Archived, name):
+		c.Archived = remove(c.Archived, name)
+	case !contains(c.Workplaces, name):
+		return fmt.Errorf("workplace '%s' not found", name)
+	case len(c.Workplaces) == 1:
+		return fmt.Errorf("'%s' is the only active workplace", name)
+	default:
+		c.ensureSections()
+		c.Workplaces = remove(c.Workplaces, name)
+	}
+
+	c.doc.Unset([]string{"workplaces", name})
+	if c.WorkplaceName == name {
+		c.WorkplaceName = c.Workplaces[0]
+	}
+	c.dropDefault(name)
+	return c.reload()
+}
+
+// dropDefault unsets default_workplace if it names a workplace that is no longer active
+func (c *Config) dropDefault(name string) {
+	if c.file.DefaultWorkplace == name {
+		c.doc.Unset([]string{"default_workplace"})
+	}
+}
+
+// Contents returns the config file as Save would write it
+func (c *Config) Contents() ([]byte, error) {
+	return c.doc.bytes()
+}
+
+// ensureSections adds an empty section for each workplace without one, so
+// the file lists every workplace in order
+func (c *Config) ensureSections() {
+	existing := c.doc.Keys([]string{"workplaces"})
+	for _, wp := range c.Workplaces {
+		if !contains(existing, wp) {
+			c.doc.Set([]string{"workplaces", wp}, emptySection())
+		}
+	}
+}
+
+// emptySection returns an empty workplace section, written as {}
+func emptySection() *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+}
+
+// remove returns a list without a value
+func remove(list []string, value string) []string {
+	var kept []string
+	for _, item := range list {
+		if item != value {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// contains reports whether a list holds a value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
 		}
-		file.Close()
 	}
+	return false
+}
 
-	// Update WORKPLACES
-	existingContent["WORKPLACES"] = strings.Join(c.Workplaces, ",")
+// NotesDirFor returns the folder the notes of a workplace are kept in
+func (c *Config) NotesDirFor(workplace string) string {
+	return expandPath(c.value("WORK_NOTES_LOCATION", workplace))
+}
 
-	// Write back to file
-	file, err := os.Create(configPath)
+// NoteLayoutFor returns the note path layout for a workplace, falling back to the global layout
+func (c *Config) NoteLayoutFor(workplace string) string {
+	return c.value("NOTE_LAYOUT", workplace)
+}
+
+// NoteTemplateFor returns the note template file for a workplace, or "" for the built-in note
+func (c *Config) NoteTemplateFor(workplace string) string {
+	return expandPath(c.value("NOTE_TEMPLATE", workplace))
+}
+
+// NoteTagsFor returns the tags of new notes of a workplace, or nil for the defaults
+func (c *Config) NoteTagsFor(workplace string) []string {
+	return splitList(c.value("NOTE_TAGS", workplace))
+}
+
+// LocationFor returns the time zone that decides which day it is for a
+// workplace, or nil when none is set
+func (c *Config) LocationFor(workplace string) (*time.Location, error) {
+	name := c.value("TIMEZONE", workplace)
+	if name == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(name)
 	if err != nil {
-		return fmt.Errorf("failed to open config file for writing: %w", err)
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// AIFor returns the summary settings of a workplace
+func (c *Config) AIFor(workplace string) AIConfig {
+	return AIConfig{
+		Server:     c.value("OPENCODE_SERVER", workplace),
+		Provider:   c.value("AI_PROVIDER", workplace),
+		Model:      c.value("AI_MODEL", workplace),
+		Prompt:     c.value("AI_PROMPT", workplace),
+		Summarizer: expandPath(c.value("AI_SUMMARIZER", workplace)),
+	}
+}
+
+// RecurringFileFor returns the recurring tasks file of a workplace, by default
+// recurring/<workplace>.txt next to the config file
+func (c *Config) RecurringFileFor(workplace string) string {
+	if path := c.value("RECURRING_FILE", workplace); path != "" {
+		return expandPath(path)
+	}
+	return filepath.Join(filepath.Dir(getConfigPath()), "recurring", workplace+".txt")
+}
+
+// ScheduledFileFor returns the file deferred items of a workplace are kept in
+func (c *Config) ScheduledFileFor(workplace string) string {
+	return filepath.Join(StateDir(), "scheduled", workplace+".json")
+}
+
+// TimeLogPath returns the file tracked time is logged to
+func (c *Config) TimeLogPath() string {
+	return filepath.Join(StateDir(), "timelog.jsonl")
+}
+
+// Hooks returns the hooks of the config file in order, with ~ in their paths expanded
+func (c *Config) Hooks() []Hook {
+	hooks := make([]Hook, len(c.file.Hooks))
+	for i, hook := range c.file.Hooks {
+		hook.Run = expandPath(hook.Run)
+		hooks[i] = hook
+	}
+	return hooks
+}
+
+// FocusConfig holds the lengths of Pomodoro focus sessions
+type FocusConfig struct {
+	Work           time.Duration
+	ShortBreak     time.Duration
+	LongBreak      time.Duration
+	LongBreakEvery int // Pomodoros before a long break
+}
+
+// Focus returns the configured Pomodoro lengths, 25/5/15 minutes with a long
+// break every 4 pomodoros by default
+func (c *Config) Focus() (FocusConfig, error) {
+	minutes := func(key string) (time.Duration, error) {
+		n, err := c.positiveInt(key)
+		return time.Duration(n) * time.Minute, err
 	}
-	defer file.Close()
 
-	writer := bufio.NewWriter(file)
+	var focus FocusConfig
+	var err error
+	if focus.Work, err = minutes("FOCUS_MINUTES"); err != nil {
+		return focus, err
+	}
+	if focus.ShortBreak, err = minutes("BREAK_MINUTES"); err != nil {
+		return focus, err
+	}
+	if focus.LongBreak, err = minutes("LONG_BREAK_MINUTES"); err != nil {
+		return focus, err
+	}
+	focus.LongBreakEvery, err = c.positiveInt("LONG_BREAK_EVERY")
+	return focus, err
+}
 
-	// Write all config values
-	for key, value := range existingContent {
-		fmt.Fprintf(writer, "%s=%s\n", key, value)
+// positiveInt reads a setting that must be a positive whole number
+func (c *Config) positiveInt(key string) (int, error) {
+	value := c.value(key, "")
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s must be a positive whole number, got %q", FindOption(key).Key, value)
 	}
+	return n, nil
+}
 
-	return writer.Flush()
+// MarkersFor returns the note markers configured for a workplace
+func (c *Config) MarkersFor(workplace string) MarkerConfig {
+	list := func(key string) []string {
+		return splitList(c.value(key, workplace))
+	}
+
+	return MarkerConfig{
+		Locale:           c.value("LOCALE", workplace),
+		Pending:          list("PENDING_HEADING"),
+		Completed:        list("COMPLETED_HEADING"),
+		Summary:          list("SUMMARY_FIELD"),
+		YesterdaySummary: list("YESTERDAY_SUMMARY_FIELD"),
+	}
 }
 
-// GetConfigPath returns the path to the config file (exported for use by commands)
-func GetConfigPath() string {
-	return getConfigPath()
+// SetNoteLayout saves a note path layout. An empty workplace sets the global layout.
+func (c *Config) SetNoteLayout(workplace, layout string) error {
+	if err := c.Set(FindOption("NOTE_LAYOUT"), workplace, layout); err != nil {
+		return err
+	}
+	return c.doc.Save()
 }
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// MarkerFile is the name of the file mapping a folder, such as a git
+// repository, to a workplace
+const MarkerFile = ".worklog"
+
+// FindMarker looks for a .worklog file in dir and the folders above it, like
+// git looks for .git, and returns the workplace it names and its path. The
+// workplace is the first line that isn't empty or a # comment. It returns ""
+// when there is no marker.
+func FindMarker(dir string) (string, string, error) {
+	for current := filepath.Clean(dir); ; current = filepath.Dir(current) {
+		path := filepath.Join(current, MarkerFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			workplace, err := readMarker(path)
+			return workplace, path, err
+		}
+		if filepath.Dir(current) == current {
+			return "", "", nil
+		}
+	}
+}
+
+// readMarker returns the workplace named in a marker file
+func readMarker(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s doesn't name a workplace", path)
+}
+
+// SessionsPath returns the file the workplaces chosen with 'worklog use' are kept in
+func (c *Config) SessionsPath() string {
+	return filepath.Join(StateDir(), "sessions.json")
+}
+
+// SessionEnv names the terminal session 'worklog use' applies to. Exporting it
+// keeps the session's workplace in subshells and scripts started from the
+// shell, which have another parent process.
+const SessionEnv = "WORKLOG_SESSION"
+
+// SessionID returns the terminal session worklog runs in: $WORKLOG_SESSION
+// when the shell exported it, otherwise the process ID of the shell
+func SessionID() string {
+	if id := strings.TrimSpace(os.Getenv(SessionEnv)); id != "" {
+		return id
+	}
+	return strconv.Itoa(os.Getppid())
+}
+
+// SessionWorkplace returns the workplace chosen with 'worklog use' in the
+// current terminal session, or "" if none was chosen
+func (c *Config) SessionWorkplace() string {
+	path := c.SessionsPath()
+	sessions, err := loadSessions(path)
+	if err != nil {
+		return ""
+	}
+	// A shell that exited may have left its process ID to a new one
+	if pruneSessions(sessions) {
+		_ = saveSessions(path, sessions)
+	}
+	return sessions[SessionID()]
+}
+
+// SetSessionWorkplace sets the workplace of the current terminal session, or
+// clears it for an empty name
+func (c *Config) SetSessionWorkplace(workplace string) error {
+	path := c.SessionsPath()
+	sessions, err := loadSessions(path)
+	if err != nil {
+		return err
+	}
+
+	pruneSessions(sessions)
+	if workplace == "" {
+		delete(sessions, SessionID())
+	} else {
+		sessions[SessionID()] = workplace
+	}
+
+	return saveSessions(path, sessions)
+}
+
+// pruneSessions drops the sessions of shells that have exited and reports
+// whether there were any. Sessions named in $WORKLOG_SESSION by something
+// other than a process ID are kept until they are cleared.
+func pruneSessions(sessions map[string]string) bool {
+	pruned := false
+	for id := range sessions {
+		if _, err := strconv.Atoi(id); err == nil && !processRunning(id) {
+			delete(sessions, id)
+			pruned = true
+		}
+	}
+	return pruned
+}
+
+// RenameSessionWorkplace points the sessions using a workplace at its new
+// name, or clears them for an empty name
+func (c *Config) RenameSessionWorkplace(oldName, newName string) error {
+	path := c.SessionsPath()
+	sessions, err := loadSessions(path)
+	if err != nil || len(sessions) == 0 {
+		return err
+	}
+
+	changed := false
+	for id, wp := range sessions {
+		if wp != oldName {
+			continue
+		}
+		if newName == "" {
+			delete(sessions, id)
+		} else {
+			sessions[id] = newName
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return saveSessions(path, sessions)
+}
+
+// loadSessions reads the session workplaces by session ID. A missing file has
+// none.
+func loadSessions(path string) (map[string]string, error) {
+	sessions := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return sessions, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return sessions, nil
+}
+
+// saveSessions writes the session workplaces
+func saveSessions(path string, sessions map[string]string) error {
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// processRunning reports whether the process with the given ID still runs
+func processRunning(pid string) bool {
+	n, err := strconv.Atoi(pid)
+	if err != nil {
+		return false
+	}
+	process, err := os.FindProcess(n)
+	if err != nil {
+		return false
+	}
+	return !errors.Is(process.Signal(syscall.Signal(0)), os.ErrProcessDone)
+}
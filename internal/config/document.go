@@ -0,0 +1,252 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is the config file as a tree of YAML nodes. Edits change the tree
+// in place, so comments and the order of keys survive saving.
+type Document struct {
+	path string
+	root *yaml.Node // Document node holding the top-level mapping
+}
+
+// newDocument returns an empty document to be saved at path
+func newDocument(path string) *Document {
+	return &Document{
+		path: path,
+		root: &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}},
+	}
+}
+
+// LoadDocument reads the config file at path. A missing file gives an empty document.
+func LoadDocument(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return newDocument(path), nil
+		}
+		return nil, err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(root.Content) == 0 {
+		// Empty or comments only; keep the comments
+		doc := newDocument(path)
+		doc.root.HeadComment = root.HeadComment
+		return doc, nil
+	}
+	if root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: the top level must be a mapping of settings", path)
+	}
+	return &Document{path: path, root: &root}, nil
+}
+
+// Path returns the file the document is saved to
+func (d *Document) Path() string {
+	return d.path
+}
+
+// Lookup returns the node at a path of keys, such as
+// ["workplaces", "Acme", "layout"], or nil if it isn't set
+func (d *Document) Lookup(path []string) *yaml.Node {
+	node := d.root.Content[0]
+	for _, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		_, value := mappingEntry(node, key)
+		if value == nil {
+			return nil
+		}
+		node = value
+	}
+	return node
+}
+
+// Keys returns the keys of the mapping at path in file order
+func (d *Document) Keys(path []string) []string {
+	node := d.Lookup(path)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var keys []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	return keys
+}
+
+// Set sets the value at path, creating mappings along the way. An existing
+// value keeps its comments.
+func (d *Document) Set(path []string, value *yaml.Node) error {
+	node := d.root.Content[0]
+	for i, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is not a section", strings.Join(path[:i], "."))
+		}
+		_, existing := mappingEntry(node, key)
+		if i == len(path)-1 {
+			if existing == nil {
+				d.insert(node, key, value)
+				return nil
+			}
+			value.HeadComment, value.LineComment, value.FootComment = existing.HeadComment, existing.LineComment, existing.FootComment
+			*existing = *value
+			return nil
+		}
+		if existing == nil {
+			existing = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			d.insert(node, key, existing)
+		}
+		node = existing
+	}
+	return nil
+}
+
+// insert adds a key to a mapping. Global settings go before the workplaces
+// section, and a section written as {} turns into a block once it has keys.
+func (d *Document) insert(mapping *yaml.Node, key string, value *yaml.Node) {
+	mapping.Style &^= yaml.FlowStyle
+	at := len(mapping.Content)
+	if mapping == d.root.Content[0] {
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			if mapping.Content[i].Value == "workplaces" {
+				at = i
+				break
+			}
+		}
+	}
+	entry := []*yaml.Node{stringNode(key), value}
+	mapping.Content = append(mapping.Content[:at], append(entry, mapping.Content[at:]...)...)
+}
+
+// Unset removes the value at path and reports whether it was set
+func (d *Document) Unset(path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	parent := d.Lookup(path[:len(path)-1])
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == path[len(path)-1] {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// RenameKey renames a key of the mapping at path, keeping its place and value
+func (d *Document) RenameKey(path []string, oldKey, newKey string) bool {
+	node := d.Lookup(path)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return false
+	}
+	keyNode, _ := mappingEntry(node, oldKey)
+	if keyNode == nil {
+		return false
+	}
+	keyNode.Value = newKey
+	return true
+}
+
+// Decode reads the document into the config schema, rejecting unknown settings
+func (d *Document) Decode() (*File, error) {
+	data, err := d.bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(file); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			// Name settings rather than Go types
+			for i, msg := range typeErr.Errors {
+				typeErr.Errors[i] = unknownFieldRegex.ReplaceAllString(msg, `unknown setting "$1"`)
+			}
+			return nil, fmt.Errorf("%s: %s", d.path, strings.Join(typeErr.Errors, "; "))
+		}
+		return nil, fmt.Errorf("%s: %w", d.path, err)
+	}
+	return file, nil
+}
+
+// unknownFieldRegex matches the decoder's error for a key the schema doesn't have
+var unknownFieldRegex = regexp.MustCompile(`field (\S+) not found in type \S+`)
+
+// Save writes the document, replacing the file in one step
+func (d *Document) Save() error {
+	data, err := d.bytes()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(d.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	tmp := d.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tmp, d.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// bytes encodes the document as YAML with two-space indentation
+func (d *Document) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(d.root); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mappingEntry returns the key and value nodes of a key in a mapping node
+func mappingEntry(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// stringNode returns a scalar node that is always read back as a string
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// listNode returns a flow-style sequence of strings, such as [a, b]
+func listNode(values []string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+	for _, value := range values {
+		node.Content = append(node.Content, stringNode(value))
+	}
+	return node
+}
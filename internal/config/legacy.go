@@ -0,0 +1,119 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// legacyConfigPath returns the KEY=value config file used before config.yaml
+func legacyConfigPath() string {
+	return filepath.Join(filepath.Dir(getConfigPath()), "config")
+}
+
+// readLegacyFile reads the KEY=value pairs of a legacy config file in order
+func readLegacyFile(path string) ([][2]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var pairs [][2]string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// Skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		pairs = append(pairs, [2]string{strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])})
+	}
+	return pairs, scanner.Err()
+}
+
+// MigrateLegacy converts a legacy KEY=value config file to config.yaml at path,
+// and renames the old file to config.legacy so it isn't migrated again
+func MigrateLegacy(legacyPath, path string) error {
+	pairs, err := readLegacyFile(legacyPath)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string)
+	for _, pair := range pairs {
+		values[pair[0]] = pair[1]
+	}
+	workplaces := splitList(values["WORKPLACES"])
+	if len(workplaces) == 0 && values["WORKPLACE_NAME"] != "" {
+		workplaces = []string{values["WORKPLACE_NAME"]}
+	}
+
+	doc := newDocument(path)
+	doc.root.HeadComment = fmt.Sprintf("Worklog configuration, migrated from %s", filepath.Base(legacyPath))
+
+	// Global settings first, then a section per workplace with its overrides
+	var perWorkplace [][2]string
+	var unknown []string
+	for _, pair := range pairs {
+		key, value := pair[0], pair[1]
+		if key == "WORKPLACES" || key == "WORKPLACE_NAME" {
+			continue
+		}
+		if opt := FindOption(key); opt != nil && opt.Env == key {
+			if err := doc.Set(opt.Path(), opt.node(value)); err != nil {
+				return err
+			}
+			continue
+		}
+		if opt, _ := legacyWorkplaceOption(key, workplaces); opt != nil {
+			perWorkplace = append(perWorkplace, pair)
+			continue
+		}
+		unknown = append(unknown, key+"="+value)
+	}
+
+	for _, wp := range workplaces {
+		if err := doc.Set([]string{"workplaces", wp}, &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}); err != nil {
+			return err
+		}
+	}
+	for _, pair := range perWorkplace {
+		opt, wp := legacyWorkplaceOption(pair[0], workplaces)
+		if err := doc.Set(append([]string{"workplaces", wp}, opt.Path()...), opt.node(pair[1])); err != nil {
+			return err
+		}
+	}
+
+	if len(unknown) > 0 {
+		doc.root.Content[0].FootComment = "Settings not recognised when migrating:\n" + strings.Join(unknown, "\n")
+	}
+
+	if err := doc.Save(); err != nil {
+		return err
+	}
+	return os.Rename(legacyPath, legacyPath+".legacy")
+}
+
+// legacyWorkplaceOption returns the option a per-workplace legacy key such as
+// NOTE_LAYOUT_ACME overrides and its workplace, or nil
+func legacyWorkplaceOption(key string, workplaces []string) (*Option, string) {
+	for _, wp := range workplaces {
+		for i := range Options {
+			if key == workplaceKey(Options[i].Env, wp) {
+				return &Options[i], wp
+			}
+		}
+	}
+	return nil, ""
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConfigEnv names a config file to read instead of the profile's
+const ConfigEnv = "WORKLOG_CONFIG"
+
+// configFile is the config file chosen with SetConfigFile
+var configFile string
+
+// SetConfigFile chooses the config file Load reads, overriding WORKLOG_CONFIG
+// and the profile's config file. An empty path leaves the choice to them.
+func SetConfigFile(path string) {
+	configFile = path
+}
+
+// getConfigPath returns the path to the config file: the one chosen with
+// SetConfigFile or WORKLOG_CONFIG, or else the active profile's
+func getConfigPath() string {
+	if configFile != "" {
+		return AbsPath(configFile)
+	}
+	if path := os.Getenv(ConfigEnv); path != "" {
+		return AbsPath(path)
+	}
+	return filepath.Join(configDir(), "config.yaml")
+}
+
+// GetConfigPath returns the path to the config file (exported for use by commands)
+func GetConfigPath() string {
+	return getConfigPath()
+}
+
+// explicitConfig reports whether the config file was chosen instead of the profile's
+func explicitConfig() bool {
+	return configFile != "" || os.Getenv(ConfigEnv) != ""
+}
+
+// configDir returns the folder of the active profile's config file. The
+// default profile lives in $XDG_CONFIG_HOME/worklog, others in
+// profiles/<name> below it.
+func configDir() string {
+	if name := ActiveProfile(); name != "" {
+		return filepath.Join(profilesDir(), name)
+	}
+	return baseConfigDir()
+}
+
+// profilesDir returns the folder holding the profiles other than the default one
+func profilesDir() string {
+	return filepath.Join(baseConfigDir(), "profiles")
+}
+
+// baseConfigDir returns the folder of the default profile's config file,
+// $XDG_CONFIG_HOME/worklog or ~/.config/worklog. A config made in
+// ~/.config/worklog before XDG_CONFIG_HOME was set keeps being used.
+func baseConfigDir() string {
+	fallback := homeDir(".config", "worklog")
+	dir := xdgDir("XDG_CONFIG_HOME", fallback)
+	if dir != fallback {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			if _, err := os.Stat(fallback); err == nil {
+				return fallback
+			}
+		}
+	}
+	return dir
+}
+
+// StateDir returns the folder of the data worklog keeps for itself, such as
+// the time log and scheduled items: $XDG_STATE_HOME/worklog, by default
+// ~/.local/state/worklog, with profiles/<name> below it for a profile
+func StateDir() string {
+	return profileDir(xdgDir("XDG_STATE_HOME", homeDir(".local", "state", "worklog")))
+}
+
+// CacheDir returns the folder of data that can be rebuilt at any time, such as
+// indexes: $XDG_CACHE_HOME/worklog, by default ~/.cache/worklog, with
+// profiles/<name> below it for a profile
+func CacheDir() string {
+	return profileDir(xdgDir("XDG_CACHE_HOME", homeDir(".cache", "worklog")))
+}
+
+// profileDir returns the folder of the active profile below a base folder
+func profileDir(base string) string {
+	if name := ActiveProfile(); name != "" {
+		return filepath.Join(base, "profiles", name)
+	}
+	return base
+}
+
+// xdgDir returns the worklog folder below an XDG base directory, or fallback
+// when the variable isn't set. Relative paths are ignored, as the spec requires.
+func xdgDir(env, fallback string) string {
+	if base := os.Getenv(env); filepath.IsAbs(base) {
+		return filepath.Join(base, "worklog")
+	}
+	return fallback
+}
+
+// homeDir returns a path below the home folder
+func homeDir(elem ...string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(append([]string{home}, elem...)...)
+}
+
+// AbsPath expands ~ and makes a path absolute
+func AbsPath(path string) string {
+	path = expandPath(path)
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// migrateState moves the time log, scheduled items and session workplaces
+// from the config folder, where they were kept before, to the state folder.
+// It returns the files moved.
+func migrateState() ([]string, error) {
+	oldDir, newDir := configDir(), StateDir()
+	if oldDir == newDir {
+		return nil, nil
+	}
+
+	var moved []string
+	for _, name := range []string{"timelog.jsonl", "scheduled", "sessions.json"} {
+		from, to := filepath.Join(oldDir, name), filepath.Join(newDir, name)
+		if _, err := os.Stat(from); err != nil {
+			continue
+		}
+		if _, err := os.Stat(to); err == nil {
+			continue // Already moved; leave the old copy alone
+		}
+		if err := os.MkdirAll(newDir, 0755); err != nil {
+			return moved, err
+		}
+		if err := os.Rename(from, to); err != nil {
+			return moved, fmt.Errorf("failed to move %s to %s: %w", from, newDir, err)
+		}
+		moved = append(moved, name)
+	}
+	return moved, nil
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// ProfileEnv names the profile to use when none is given with SetProfile
+const ProfileEnv = "WORKLOG_PROFILE"
+
+// profile is the profile chosen with SetProfile
+var profile string
+
+// profileNameRegex matches the names profiles can have, which are folder names
+var profileNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// SetProfile chooses the profile Load reads, overriding WORKLOG_PROFILE. An
+// empty name leaves the choice to the environment.
+func SetProfile(name string) error {
+	if name != "" {
+		if err := ValidateProfileName(name); err != nil {
+			return err
+		}
+	}
+	profile = name
+	return nil
+}
+
+// ValidateProfileName checks that a profile name can be used as a folder name
+func ValidateProfileName(name string) error {
+	if !profileNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// ActiveProfile returns the profile in use, chosen with SetProfile or
+// WORKLOG_PROFILE, or "" for the default profile
+func ActiveProfile() string {
+	if profile != "" {
+		return profile
+	}
+	return os.Getenv(ProfileEnv)
+}
+
+// Profiles returns the names of the profiles besides the default one, sorted
+func Profiles() ([]string, error) {
+	entries, err := os.ReadDir(profilesDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && profileNameRegex.MatchString(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ProfileExists reports whether a profile has been created. The default
+// profile always exists.
+func ProfileExists(name string) bool {
+	if name == "" {
+		return true
+	}
+	info, err := os.Stat(filepath.Join(profilesDir(), name))
+	return err == nil && info.IsDir()
+}
+
+// CreateProfile creates a profile with its own config file, keeping notes in
+// notesDir and starting with one workplace
+func CreateProfile(name, notesDir, workplace string) (string, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+	if ProfileExists(name) {
+		return "", fmt.Errorf("profile '%s' already exists", name)
+	}
+
+	doc := newDocument(filepath.Join(profilesDir(), name, "config.yaml"))
+	doc.root.HeadComment = fmt.Sprintf("Worklog configuration of the %s profile", name)
+	if err := doc.Set([]string{"notes_dir"}, stringNode(notesDir)); err != nil {
+		return "", err
+	}
+	if err := doc.Set([]string{"workplaces", workplace}, emptySection()); err != nil {
+		return "", err
+	}
+	if err := doc.Save(); err != nil {
+		return "", err
+	}
+	return doc.Path(), nil
+}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is the schema of config.yaml. Settings at the top level apply to every
+// workplace; a workplace's section overrides them for that workplace.
+//
+//	notes_dir: ~/Documents/obsidian-notes/Inbox/work
+//	default_workplace: Acme
+//	ai:
+//	  model: claude-sonnet-4
+//	workplaces:
+//	  Acme:
+//	    layout: Acme/{YYYY}/{MM}/{YYYY}-{MM}-{DD}.md
+//	    timezone: Europe/Berlin
+//	  Personal: {}
+//	  OldJob:
+//	    archived: true
+type File struct {
+	Settings         `yaml:",inline"`
+	DefaultWorkplace string                `yaml:"default_workplace"`
+	Hooks            []Hook                `yaml:"hooks"`
+	Workplaces       map[string]*Workplace `yaml:"workplaces"`
+}
+
+// Hook is an executable run on an event, such as item.completed, with the
+// event as JSON on stdin
+//
+//	hooks:
+//	  - event: item.completed
+//	    run: ~/bin/ping-status
+//	    timeout: 5s
+//	    on_failure: warn
+type Hook struct {
+	Event      string   `yaml:"event"`
+	Run        string   `yaml:"run"`
+	Args       []string `yaml:"args"`
+	Timeout    string   `yaml:"timeout"`
+	OnFailure  string   `yaml:"on_failure"`
+	Workplaces []string `yaml:"workplaces"`
+}
+
+// Workplace is a workplace's section: its own settings, and whether it is archived
+type Workplace struct {
+	Settings `yaml:",inline"`
+	Archived bool `yaml:"archived"`
+}
+
+// Settings are the options that can be set globally and per workplace
+type Settings struct {
+	NotesDir  string   `yaml:"notes_dir"`
+	Layout    string   `yaml:"layout"`
+	Template  string   `yaml:"template"`
+	Locale    string   `yaml:"locale"`
+	Timezone  string   `yaml:"timezone"`
+	Tags      []string `yaml:"tags"`
+	Recurring string   `yaml:"recurring_file"`
+	Headings  Headings `yaml:"headings"`
+	AI        AI       `yaml:"ai"`
+	Focus     Focus    `yaml:"focus"`
+}
+
+// Headings are the note markers worklog reads and writes. Each list has the
+// canonical form first followed by aliases.
+type Headings struct {
+	Pending          []string `yaml:"pending"`
+	Completed        []string `yaml:"completed"`
+	Summary          []string `yaml:"summary"`
+	YesterdaySummary []string `yaml:"yesterday_summary"`
+}
+
+// AI configures the OpenCode server or summarizer plugin used for summaries
+type AI struct {
+	Server     string `yaml:"server"`
+	Provider   string `yaml:"provider"`
+	Model      string `yaml:"model"`
+	Prompt     string `yaml:"prompt"`
+	Summarizer string `yaml:"summarizer"`
+}
+
+// Focus holds the Pomodoro lengths of 'worklog focus'
+type Focus struct {
+	WorkMinutes      int `yaml:"work_minutes"`
+	BreakMinutes     int `yaml:"break_minutes"`
+	LongBreakMinutes int `yaml:"long_break_minutes"`
+	LongBreakEvery   int `yaml:"long_break_every"`
+}
+
+// Option is a setting of the config file
+type Option struct {
+	Key         string // Path below the top level or a workplace section, such as "ai.model"
+	Env         string // Environment variable overriding it, also its key in the legacy config file
+	Description string
+	Default     string // Value when unset; empty when there is none or it depends on the workplace
+	List        bool   // A list, comma-separated in environment variables
+	Int         bool
+
+	get    func(s *Settings) string
+	global func(f *File) string // Set for options that only exist at the top level
+}
+
+// Options lists every setting of the config file
+var Options = []Option{
+	{Key: "default_workplace", Env: "DEFAULT_WORKPLACE", Description: "Workplace used without asking when nothing else picks one",
+		global: func(f *File) string { return f.DefaultWorkplace }},
+	{Key: "notes_dir", Env: "WORK_NOTES_LOCATION", Description: "Folder the notes are kept in", Default: "~/Documents/obsidian-notes/Inbox/work",
+		get: func(s *Settings) string { return s.NotesDir }},
+	{Key: "layout", Env: "NOTE_LAYOUT", Description: "Path layout of notes within the notes folder", Default: "{YYYY}-{MM}-{DD}-{workplace}.md",
+		get: func(s *Settings) string { return s.Layout }},
+	{Key: "template", Env: "NOTE_TEMPLATE", Description: "Template file new notes are created from",
+		get: func(s *Settings) string { return s.Template }},
+	{Key: "locale", Env: "LOCALE", Description: "Language of prompts and default headings (en, de, ja)", Default: "en",
+		get: func(s *Settings) string { return s.Locale }},
+	{Key: "timezone", Env: "TIMEZONE", Description: "Time zone deciding which day it is, such as Europe/Berlin",
+		get: func(s *Settings) string { return s.Timezone }},
+	{Key: "tags", Env: "NOTE_TAGS", Description: "Tags of new notes", List: true,
+		get: func(s *Settings) string { return strings.Join(s.Tags, ",") }},
+	{Key: "recurring_file", Env: "RECURRING_FILE", Description: "File of recurring items",
+		get: func(s *Settings) string { return s.Recurring }},
+	{Key: "headings.pending", Env: "PENDING_HEADING", Description: "Heading(s) of the pending section", List: true,
+		get: func(s *Settings) string { return strings.Join(s.Headings.Pending, ",") }},
+	{Key: "headings.completed", Env: "COMPLETED_HEADING", Description: "Heading(s) of the completed section", List: true,
+		get: func(s *Settings) string { return strings.Join(s.Headings.Completed, ",") }},
+	{Key: "headings.summary", Env: "SUMMARY_FIELD", Description: "Inline field(s) of the day's summary", List: true,
+		get: func(s *Settings) string { return strings.Join(s.Headings.Summary, ",") }},
+	{Key: "headings.yesterday_summary", Env: "YESTERDAY_SUMMARY_FIELD", Description: "Inline field(s) of yesterday's summary", List: true,
+		get: func(s *Settings) string { return strings.Join(s.Headings.YesterdaySummary, ",") }},
+	{Key: "ai.server", Env: "OPENCODE_SERVER", Description: "URL of the OpenCode server for summaries", Default: "http://127.0.0.1:4096",
+		get: func(s *Settings) string { return s.AI.Server }},
+	{Key: "ai.provider", Env: "AI_PROVIDER", Description: "AI provider ID for summaries", Default: "github-copilot",
+		get: func(s *Settings) string { return s.AI.Provider }},
+	{Key: "ai.model", Env: "AI_MODEL", Description: "AI model ID for summaries", Default: "claude-sonnet-4",
+		get: func(s *Settings) string { return s.AI.Model }},
+	{Key: "ai.prompt", Env: "AI_PROMPT", Description: "Instructions the completed items are summarized with",
+		get: func(s *Settings) string { return s.AI.Prompt }},
+	{Key: "ai.summarizer", Env: "AI_SUMMARIZER", Description: "Summarizer plugin used instead of the OpenCode server",
+		get: func(s *Settings) string { return s.AI.Summarizer }},
+	{Key: "focus.work_minutes", Env: "FOCUS_MINUTES", Description: "Length of a pomodoro in minutes", Default: "25", Int: true,
+		get: func(s *Settings) string { return intString(s.Focus.WorkMinutes) }},
+	{Key: "focus.break_minutes", Env: "BREAK_MINUTES", Description: "Length of a short break in minutes", Default: "5", Int: true,
+		get: func(s *Settings) string { return intString(s.Focus.BreakMinutes) }},
+	{Key: "focus.long_break_minutes", Env: "LONG_BREAK_MINUTES", Description: "Length of a long break in minutes", Default: "15", Int: true,
+		get: func(s *Settings) string { return intString(s.Focus.LongBreakMinutes) }},
+	{Key: "focus.long_break_every", Env: "LONG_BREAK_EVERY", Description: "Pomodoros before a long break", Default: "4", Int: true,
+		get: func(s *Settings) string { return intString(s.Focus.LongBreakEvery) }},
+}
+
+// FindOption returns the option with the given key or environment variable, or nil
+func FindOption(name string) *Option {
+	for i := range Options {
+		if Options[i].Key == name || Options[i].Env == name {
+			return &Options[i]
+		}
+	}
+	return nil
+}
+
+// Global reports whether the option can only be set at the top level, not per workplace
+func (o *Option) Global() bool {
+	return o.global != nil
+}
+
+// fileValue returns the option's value at the top level of the file
+func (o *Option) fileValue(f *File) string {
+	if o.global != nil {
+		return o.global(f)
+	}
+	return o.get(&f.Settings)
+}
+
+// Path returns the keys of the option below a section
+func (o *Option) Path() []string {
+	return strings.Split(o.Key, ".")
+}
+
+// WorkplaceEnv returns the environment variable overriding the option for one workplace
+func (o *Option) WorkplaceEnv(workplace string) string {
+	return workplaceKey(o.Env, workplace)
+}
+
+// node returns the YAML node of a value given as text, with lists comma-separated
+func (o *Option) node(value string) *yaml.Node {
+	switch {
+	case o.List:
+		return listNode(splitList(value))
+	case o.Int:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strings.TrimSpace(value)}
+	}
+	return stringNode(value)
+}
+
+// intString formats an unset number as ""
+func intString(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
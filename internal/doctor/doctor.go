@@ -0,0 +1,228 @@
+package doctor
+
+import (
+	"bufio"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+)
+
+// Severity describes how serious a diagnostic is
+type Severity string
+
+const (
+	// SeverityError means worklog may read or rewrite the note incorrectly
+	SeverityError Severity = "error"
+	// SeverityWarning means the note works but doesn't follow worklog's conventions
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a single problem found in a note
+type Diagnostic struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Fixable  bool     `json:"fixable"`
+}
+
+// File is a note being checked, with the facts rules need about it
+type File struct {
+	Path       string
+	Workplace  string
+	Date       time.Time // Date encoded in the note's path
+	Lines      []string
+	Markers    notes.Markers
+	Tags       []string // Tags configured for the workplace's notes, or none for the defaults
+	SharedWith []string // Other workplaces that read this file as one of their notes
+
+	frontmatter *frontmatter
+}
+
+// frontmatter describes the frontmatter block of a note as found on disk
+type frontmatter struct {
+	present    bool
+	terminated bool
+	endLine    int            // Line number of the closing "---"
+	keys       map[string]int // Key -> line number
+	values     map[string]string
+	tags       []tagLine
+}
+
+// tagLine is a tag and the line it was found on
+type tagLine struct {
+	tag  string
+	line int
+}
+
+// LoadFile reads a note for checking. tags are the tags configured for the
+// workplace's notes, or nil for the defaults.
+func LoadFile(path, workplace string, date time.Time, markers notes.Markers, tags []string) (*File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &File{
+		Path:      path,
+		Workplace: workplace,
+		Date:      date,
+		Lines:     lines,
+		Markers:   markers,
+		Tags:      tags,
+	}, nil
+}
+
+// parseFrontmatter returns the frontmatter block, reading it on first use
+func (f *File) parseFrontmatter() *frontmatter {
+	if f.frontmatter != nil {
+		return f.frontmatter
+	}
+
+	fm := &frontmatter{
+		keys:   make(map[string]int),
+		values: make(map[string]string),
+	}
+	f.frontmatter = fm
+
+	if len(f.Lines) == 0 || f.Lines[0] != "---" {
+		return fm
+	}
+	fm.present = true
+
+	currentKey := ""
+	for i := 1; i < len(f.Lines); i++ {
+		line := f.Lines[i]
+		lineNum := i + 1
+
+		if line == "---" {
+			fm.terminated = true
+			fm.endLine = lineNum
+			break
+		}
+
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "- ") {
+			item := strings.TrimSpace(line)
+			if currentKey == "tags" && strings.HasPrefix(item, "- ") {
+				fm.tags = append(fm.tags, tagLine{tag: strings.TrimSpace(strings.TrimPrefix(item, "- ")), line: lineNum})
+			}
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		currentKey = strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		fm.keys[currentKey] = lineNum
+		fm.values[currentKey] = value
+
+		if currentKey == "tags" && value != "" && value != "[]" {
+			for _, tag := range strings.Split(strings.Trim(value, "[]"), ",") {
+				if tag = strings.Trim(strings.TrimSpace(tag), `"'`); tag != "" {
+					fm.tags = append(fm.tags, tagLine{tag: tag, line: lineNum})
+				}
+			}
+		}
+	}
+
+	return fm
+}
+
+// bodyStart returns the index of the first line after the frontmatter
+func (f *File) bodyStart() int {
+	fm := f.parseFrontmatter()
+	if fm.present && fm.terminated {
+		return fm.endLine
+	}
+	return 0
+}
+
+// sectionHeading describes a "## " heading in the note body
+type sectionHeading struct {
+	kind notes.SectionKind
+	text string
+	line int
+}
+
+// sections returns the "## " headings of the note body
+func (f *File) sections() []sectionHeading {
+	var headings []sectionHeading
+	for i := f.bodyStart(); i < len(f.Lines); i++ {
+		if strings.HasPrefix(f.Lines[i], "## ") {
+			text := strings.TrimSpace(strings.TrimPrefix(f.Lines[i], "## "))
+			headings = append(headings, sectionHeading{
+				kind: f.Markers.KindOf(text),
+				text: text,
+				line: i + 1,
+			})
+		}
+	}
+	return headings
+}
+
+// diagnostic creates a diagnostic for this file
+func (f *File) diagnostic(rule Rule, line int, message string) Diagnostic {
+	return Diagnostic{
+		File:     f.Path,
+		Line:     line,
+		Rule:     rule.Name,
+		Severity: rule.Severity,
+		Message:  message,
+		Fixable:  rule.Fix != nil,
+	}
+}
+
+// Check runs every rule against a note, returning diagnostics in line order
+func Check(f *File) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, rule := range Rules {
+		diagnostics = append(diagnostics, rule.Check(f, rule)...)
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Line < diagnostics[j].Line
+	})
+
+	return diagnostics
+}
+
+// Fix repairs the parsed note for every fixable diagnostic and reports whether
+// anything changed. The caller writes the note back to disk.
+func Fix(f *File, note *notes.Note, diagnostics []Diagnostic) bool {
+	changed := false
+	for _, rule := range Rules {
+		if rule.Fix == nil || !hasRule(diagnostics, rule.Name) {
+			continue
+		}
+		if rule.Fix(f, note) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// hasRule reports whether any fixable diagnostic was raised by the named rule
+func hasRule(diagnostics []Diagnostic, name string) bool {
+	for _, d := range diagnostics {
+		if d.Rule == name && d.Fixable {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,386 @@
+package doctor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+)
+
+// Rule is a single check that can be run against a note
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       func(f *File, rule Rule) []Diagnostic
+	// Fix repairs the parsed note and reports whether it changed anything.
+	// Rules without a Fix can only be reported.
+	Fix func(f *File, note *notes.Note) bool
+}
+
+// Rules lists every check worklog doctor runs, in the order fixes are applied
+var Rules = []Rule{
+	{
+		Name:        "frontmatter",
+		Description: "The note starts with a terminated YAML frontmatter block",
+		Severity:    SeverityError,
+		Check:       checkFrontmatter,
+		Fix:         fixFrontmatter,
+	},
+	{
+		Name:        "id",
+		Description: "The frontmatter id matches the workplace and date",
+		Severity:    SeverityError,
+		Check:       checkID,
+		Fix:         fixID,
+	},
+	{
+		Name:        "date",
+		Description: "The frontmatter date matches the date in the file path",
+		Severity:    SeverityError,
+		Check:       checkDate,
+		Fix:         fixDate,
+	},
+	{
+		Name:        "duplicate-section",
+		Description: "Pending and completed sections appear only once",
+		Severity:    SeverityError,
+		Check:       checkDuplicateSections,
+		Fix:         fixRewrite,
+	},
+	{
+		Name:        "missing-section",
+		Description: "The note has pending and completed sections",
+		Severity:    SeverityWarning,
+		Check:       checkMissingSections,
+		Fix:         fixRewrite,
+	},
+	{
+		Name:        "misplaced-item",
+		Description: "Checked items are under completed work and unchecked items under pending work",
+		Severity:    SeverityError,
+		Check:       checkMisplacedItems,
+		Fix:         fixMisplacedItems,
+	},
+	{
+		Name:        "tags",
+		Description: "Tags are lowercase, unique and include the tags configured for new notes",
+		Severity:    SeverityWarning,
+		Check:       checkTags,
+		Fix:         fixTags,
+	},
+	{
+		Name:        "shared-path",
+		Description: "The note belongs to a single workplace",
+		Severity:    SeverityError,
+		Check:       checkSharedPath,
+	},
+}
+
+// checkFrontmatter reports missing or unterminated frontmatter
+func checkFrontmatter(f *File, rule Rule) []Diagnostic {
+	fm := f.parseFrontmatter()
+	if !fm.present {
+		return []Diagnostic{f.diagnostic(rule, 1, "note has no frontmatter")}
+	}
+	if !fm.terminated {
+		// Everything after the opening "---" is read as frontmatter, so rewriting
+		// would lose the note's content; this one has to be fixed by hand
+		d := f.diagnostic(rule, 1, "frontmatter is never closed with ---")
+		d.Fixable = false
+		return []Diagnostic{d}
+	}
+	return nil
+}
+
+// fixFrontmatter fills in the frontmatter fields of a note that had none
+func fixFrontmatter(f *File, note *notes.Note) bool {
+	defaults := notes.NewNote(f.Date, f.Workplace)
+	if note.ID == "" {
+		note.ID = defaults.ID
+	}
+	if note.Date.IsZero() {
+		note.Date = defaults.Date
+	}
+	if len(note.Tags) == 0 {
+		note.Tags = f.expectedTags()
+	}
+	return true
+}
+
+// checkID reports a missing or mismatched note id
+func checkID(f *File, rule Rule) []Diagnostic {
+	fm := f.parseFrontmatter()
+	if !fm.present || !fm.terminated {
+		return nil // Reported by the frontmatter rule
+	}
+
+	expected := notes.GenerateID(f.Date, f.Workplace)
+	line, ok := fm.keys["id"]
+	if !ok || fm.values["id"] == "" {
+		return []Diagnostic{f.diagnostic(rule, 1, fmt.Sprintf("id is missing, expected %q", expected))}
+	}
+	if fm.values["id"] != expected {
+		return []Diagnostic{f.diagnostic(rule, line, fmt.Sprintf("id is %q, expected %q", fm.values["id"], expected))}
+	}
+	return nil
+}
+
+// fixID regenerates the note id
+func fixID(f *File, note *notes.Note) bool {
+	expected := notes.GenerateID(f.Date, f.Workplace)
+	if note.ID == expected {
+		return false
+	}
+	note.ID = expected
+	return true
+}
+
+// checkDate reports a missing frontmatter date or one that disagrees with the path
+func checkDate(f *File, rule Rule) []Diagnostic {
+	fm := f.parseFrontmatter()
+	if !fm.present || !fm.terminated {
+		return nil // Reported by the frontmatter rule
+	}
+
+	expected := f.Date.Format("2006-01-02")
+	line, ok := fm.keys["date"]
+	if !ok || fm.values["date"] == "" {
+		return []Diagnostic{f.diagnostic(rule, 1, fmt.Sprintf("date is missing, expected %s", expected))}
+	}
+
+	value := fm.values["date"]
+	if _, err := time.Parse("2006-01-02", value); err != nil {
+		return []Diagnostic{f.diagnostic(rule, line, fmt.Sprintf("date %q is not a YYYY-MM-DD date", value))}
+	}
+	if value != expected {
+		return []Diagnostic{f.diagnostic(rule, line, fmt.Sprintf("date is %s but the file is named for %s", value, expected))}
+	}
+	return nil
+}
+
+// fixDate sets the frontmatter date from the file path
+func fixDate(f *File, note *notes.Note) bool {
+	if note.Date.Equal(f.Date) {
+		return false
+	}
+	note.Date = f.Date
+	return true
+}
+
+// checkDuplicateSections reports pending or completed sections that appear more than once
+func checkDuplicateSections(f *File, rule Rule) []Diagnostic {
+	var diagnostics []Diagnostic
+	seen := make(map[notes.SectionKind]int)
+
+	for _, h := range f.sections() {
+		if h.kind == notes.SectionOther {
+			continue
+		}
+		if first, ok := seen[h.kind]; ok {
+			diagnostics = append(diagnostics, f.diagnostic(rule, h.line,
+				fmt.Sprintf("duplicate section %q (first seen on line %d); items will be merged", h.text, first)))
+			continue
+		}
+		seen[h.kind] = h.line
+	}
+
+	return diagnostics
+}
+
+// checkMissingSections reports notes without a pending or completed section
+func checkMissingSections(f *File, rule Rule) []Diagnostic {
+	hasPending, hasCompleted := false, false
+	for _, h := range f.sections() {
+		switch h.kind {
+		case notes.SectionPending:
+			hasPending = true
+		case notes.SectionCompleted:
+			hasCompleted = true
+		}
+	}
+
+	var diagnostics []Diagnostic
+	if !hasPending {
+		diagnostics = append(diagnostics, f.diagnostic(rule, 1,
+			fmt.Sprintf("no %q section", f.Markers.PendingHeading())))
+	}
+	if !hasCompleted {
+		diagnostics = append(diagnostics, f.diagnostic(rule, 1,
+			fmt.Sprintf("no %q section", f.Markers.CompletedHeading())))
+	}
+	return diagnostics
+}
+
+// fixRewrite repairs problems that writing the parsed note back already solves
+func fixRewrite(f *File, note *notes.Note) bool {
+	return true
+}
+
+// uncheckedKind describes an item that isn't checked by its checkbox character
+func uncheckedKind(box byte) string {
+	switch notes.ItemState(box) {
+	case notes.StateCancelled:
+		return "cancelled"
+	case notes.StateDeferred:
+		return "deferred"
+	case notes.StateBlocked, notes.StateWaiting:
+		return "blocked"
+	}
+	return "unchecked"
+}
+
+// checkMisplacedItems reports checked items under pending work and unchecked items under completed work
+func checkMisplacedItems(f *File, rule Rule) []Diagnostic {
+	var diagnostics []Diagnostic
+	current := notes.SectionOther
+
+	for i := f.bodyStart(); i < len(f.Lines); i++ {
+		line := f.Lines[i]
+		if strings.HasPrefix(line, "## ") {
+			current = f.Markers.KindOf(strings.TrimSpace(strings.TrimPrefix(line, "## ")))
+			continue
+		}
+
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue // Sub-items belong to the item above
+		}
+
+		trimmed := strings.TrimSpace(line)
+		checked := strings.HasPrefix(trimmed, "- [x] ") || strings.HasPrefix(trimmed, "- [X] ")
+		// Cancelled, deferred and blocked items belong with the pending ones
+		unchecked := !checked && len(trimmed) > 6 && strings.HasPrefix(trimmed, "- [") && trimmed[4:6] == "] "
+
+		switch {
+		case current == notes.SectionPending && checked:
+			diagnostics = append(diagnostics, f.diagnostic(rule, i+1, "checked item under pending work"))
+		case current == notes.SectionCompleted && unchecked:
+			diagnostics = append(diagnostics, f.diagnostic(rule, i+1, fmt.Sprintf("%s item under completed work", uncheckedKind(trimmed[3]))))
+		}
+	}
+
+	return diagnostics
+}
+
+// fixMisplacedItems moves items into the section matching their checkbox
+func fixMisplacedItems(f *File, note *notes.Note) bool {
+	changed := false
+
+	var pending []notes.WorkItem
+	for _, item := range note.PendingWork {
+		if item.Completed {
+			note.CompletedWork = append(note.CompletedWork, item)
+			changed = true
+		} else {
+			pending = append(pending, item)
+		}
+	}
+
+	var completed []notes.WorkItem
+	for _, item := range note.CompletedWork {
+		if !item.Completed {
+			pending = append(pending, item)
+			changed = true
+		} else {
+			completed = append(completed, item)
+		}
+	}
+
+	note.PendingWork = pending
+	note.CompletedWork = completed
+	return changed
+}
+
+// checkTags reports tags that aren't normalised and missing configured tags
+func checkTags(f *File, rule Rule) []Diagnostic {
+	fm := f.parseFrontmatter()
+	if !fm.present || !fm.terminated {
+		return nil // Reported by the frontmatter rule
+	}
+
+	var diagnostics []Diagnostic
+	seen := make(map[string]bool)
+	for _, t := range fm.tags {
+		normalized := normalizeTag(t.tag)
+		switch {
+		case normalized != t.tag:
+			diagnostics = append(diagnostics, f.diagnostic(rule, t.line, fmt.Sprintf("tag %q should be %q", t.tag, normalized)))
+		case seen[normalized]:
+			diagnostics = append(diagnostics, f.diagnostic(rule, t.line, fmt.Sprintf("duplicate tag %q", t.tag)))
+		}
+		seen[normalized] = true
+	}
+
+	for _, tag := range f.expectedTags() {
+		if seen[tag] {
+			continue
+		}
+		line := fm.keys["tags"]
+		if line == 0 {
+			line = 1
+		}
+		diagnostics = append(diagnostics, f.diagnostic(rule, line, fmt.Sprintf("missing tag %q", tag)))
+	}
+
+	return diagnostics
+}
+
+// fixTags normalises and de-duplicates the note's tags, adding the configured tags if missing
+func fixTags(f *File, note *notes.Note) bool {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range note.Tags {
+		normalized := normalizeTag(tag)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		tags = append(tags, normalized)
+	}
+
+	var missing []string
+	for _, tag := range f.expectedTags() {
+		if !seen[tag] {
+			missing = append(missing, tag)
+		}
+	}
+	tags = append(missing, tags...)
+
+	changed := strings.Join(tags, "\n") != strings.Join(note.Tags, "\n")
+	note.Tags = tags
+	return changed
+}
+
+// expectedTags returns the tags every note of the workplace should have: the
+// configured ones, or those of a new note by default, normalised
+func (f *File) expectedTags() []string {
+	configured := f.Tags
+	if len(configured) == 0 {
+		configured = notes.NewNote(f.Date, f.Workplace).Tags
+	}
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range configured {
+		if normalized := normalizeTag(tag); normalized != "" && !seen[normalized] {
+			seen[normalized] = true
+			tags = append(tags, normalized)
+		}
+	}
+	return tags
+}
+
+// normalizeTag lowercases a tag and strips a leading #, matching the tags worklog writes
+func normalizeTag(tag string) string {
+	tag = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	return notes.ToLowerCase(tag)
+}
+
+// checkSharedPath reports a note that other workplaces read as their own too,
+// so that adding or completing items in one would change the others
+func checkSharedPath(f *File, rule Rule) []Diagnostic {
+	if len(f.SharedWith) == 0 {
+		return nil
+	}
+	return []Diagnostic{f.diagnostic(rule, 1, fmt.Sprintf("note is also a note of %s; add {workplace} to the note layout or use separate notes folders", strings.Join(f.SharedWith, ", ")))}
+}
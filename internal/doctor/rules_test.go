@@ -0,0 +1,264 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+)
+
+var testDate = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+// healthyNote is a note as worklog writes it, without any problems
+const healthyNote = `---
+id: Acme-18-Oct-2026
+aliases: []
+tags:
+  - acme
+  - job
+date: 2026-10-18
+---
+
+# 2026-10-18
+
+summary::
+
+yesterday's summary::
+
+## Pending Work
+
+- [ ] Write report
+
+## Work Completed
+
+- [x] Ship it
+`
+
+// withChange returns the healthy note with one piece of text replaced
+func withChange(old, new string) string {
+	return strings.Replace(healthyNote, old, new, 1)
+}
+
+// writeNote writes content to a note file and loads it for checking
+func writeNote(t *testing.T, content string) *File {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "2026-10-18-Acme.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return loadNote(t, path)
+}
+
+// loadNote loads a note file for checking with the default tags
+func loadNote(t *testing.T, path string) *File {
+	t.Helper()
+	return loadNoteWithTags(t, path, nil)
+}
+
+// loadNoteWithTags loads a note file for checking with configured tags
+func loadNoteWithTags(t *testing.T, path string, tags []string) *File {
+	t.Helper()
+	file, err := LoadFile(path, "Acme", testDate, notes.DefaultMarkers, tags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// fixNote parses a note, lets fix repair it and writes it back, returning
+// whether fix reported a change
+func fixNote(t *testing.T, file *File, fix func(note *notes.Note) bool) bool {
+	t.Helper()
+	note, err := notes.NewParser(filepath.Dir(file.Path), "Acme").ParseFile(file.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := fix(note)
+	if err := os.WriteFile(file.Path, notes.NewWriter(filepath.Dir(file.Path), "Acme").Markdown(note), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return changed
+}
+
+// ruleDiagnostics returns the diagnostics raised by one rule
+func ruleDiagnostics(diagnostics []Diagnostic, rule string) []Diagnostic {
+	var matching []Diagnostic
+	for _, d := range diagnostics {
+		if d.Rule == rule {
+			matching = append(matching, d)
+		}
+	}
+	return matching
+}
+
+func TestHealthyNote(t *testing.T) {
+	if diagnostics := Check(writeNote(t, healthyNote)); len(diagnostics) > 0 {
+		t.Fatalf("expected no diagnostics, got %+v", diagnostics)
+	}
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        string
+		content     string
+		diagnostics int
+		line        int // Line of the first diagnostic
+	}{
+		{"no frontmatter", "frontmatter", healthyNote[strings.Index(healthyNote, "# 2026"):], 1, 1},
+		{"missing id", "id", withChange("id: Acme-18-Oct-2026\n", ""), 1, 1},
+		{"wrong id", "id", withChange("id: Acme-18-Oct-2026", "id: Acme-17-Oct-2026"), 1, 2},
+		{"missing date", "date", withChange("date: 2026-10-18\n", ""), 1, 1},
+		{"wrong date", "date", withChange("date: 2026-10-18", "date: 2026-10-17"), 1, 7},
+		{"invalid date", "date", withChange("date: 2026-10-18", "date: 18/10/2026"), 1, 7},
+		{"duplicate section", "duplicate-section", healthyNote + "\n## Pending Work\n\n- [ ] Call Bob\n", 1, 24},
+		{"missing pending section", "missing-section", withChange("## Pending Work\n\n- [ ] Write report\n\n", ""), 1, 1},
+		{"missing completed section", "missing-section", withChange("\n## Work Completed\n\n- [x] Ship it\n", ""), 1, 1},
+		{"checked item under pending", "misplaced-item", withChange("- [ ] Write report", "- [x] Write report"), 1, 18},
+		{"unchecked item under completed", "misplaced-item", withChange("- [x] Ship it", "- [ ] Ship it"), 1, 22},
+		{"cancelled item under completed", "misplaced-item", withChange("- [x] Ship it", "- [-] Ship it"), 1, 22},
+		{"uppercase tag", "tags", withChange("  - job", "  - Job"), 1, 6},
+		{"tag with #", "tags", withChange("  - job", "  - #job"), 1, 6},
+		{"duplicate tag", "tags", withChange("  - job", "  - job\n  - job"), 1, 7},
+		{"missing default tag", "tags", withChange("  - acme\n", ""), 1, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := writeNote(t, tt.content)
+			diagnostics := Check(file)
+			found := ruleDiagnostics(diagnostics, tt.rule)
+			if len(found) != tt.diagnostics {
+				t.Fatalf("expected %d %s diagnostic(s), got %+v", tt.diagnostics, tt.rule, diagnostics)
+			}
+			if found[0].Line != tt.line {
+				t.Errorf("expected the diagnostic on line %d, got %d", tt.line, found[0].Line)
+			}
+			if !found[0].Fixable {
+				t.Fatalf("expected %s to be fixable", tt.rule)
+			}
+
+			if !fixNote(t, file, func(note *notes.Note) bool { return Fix(file, note, diagnostics) }) {
+				t.Fatal("expected Fix to report a change")
+			}
+			fixed := loadNote(t, file.Path)
+			if remaining := Check(fixed); len(remaining) > 0 {
+				t.Fatalf("expected no diagnostics after fixing, got %+v\n%s", remaining, strings.Join(fixed.Lines, "\n"))
+			}
+
+			// Running every fix again changes nothing
+			fixNote(t, fixed, func(note *notes.Note) bool {
+				for _, rule := range Rules {
+					if rule.Fix != nil {
+						rule.Fix(fixed, note)
+					}
+				}
+				return false
+			})
+			if again := loadNote(t, file.Path); strings.Join(again.Lines, "\n") != strings.Join(fixed.Lines, "\n") {
+				t.Fatalf("fixing a second time changed the note:\n%s\nbecame\n%s", strings.Join(fixed.Lines, "\n"), strings.Join(again.Lines, "\n"))
+			}
+		})
+	}
+}
+
+func TestConfiguredTags(t *testing.T) {
+	configured := []string{"client", "#Work"}
+	tests := []struct {
+		name    string
+		content string
+		missing []string // Tags reported missing
+		want    []string // Tags after fixing
+	}{
+		{"default tags only", healthyNote, []string{"client", "work"}, []string{"client", "work", "acme", "job"}},
+		{"configured tags present", withChange("  - acme\n", "  - work\n  - client\n"), nil, []string{"work", "client", "job"}},
+		{"one configured tag missing", withChange("  - acme\n", "  - work\n"), []string{"client"}, []string{"client", "work", "job"}},
+		{"no tags", withChange("tags:\n  - acme\n  - job\n", ""), []string{"client", "work"}, []string{"client", "work"}},
+		{"no frontmatter", healthyNote[strings.Index(healthyNote, "# 2026"):], nil, []string{"client", "work"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeNote(t, tt.content).Path
+			file := loadNoteWithTags(t, path, configured)
+			diagnostics := Check(file)
+			var missing []string
+			for _, d := range ruleDiagnostics(diagnostics, "tags") {
+				missing = append(missing, strings.Trim(strings.TrimPrefix(d.Message, "missing tag "), `"`))
+			}
+			if strings.Join(missing, ",") != strings.Join(tt.missing, ",") {
+				t.Errorf("missing tags: got %q, want %q", missing, tt.missing)
+			}
+
+			var tags []string
+			fixNote(t, file, func(note *notes.Note) bool {
+				changed := Fix(file, note, diagnostics)
+				tags = note.Tags
+				return changed
+			})
+			if strings.Join(tags, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("tags after fixing: got %q, want %q", tags, tt.want)
+			}
+			if remaining := Check(loadNoteWithTags(t, path, configured)); len(remaining) > 0 {
+				t.Errorf("expected no diagnostics after fixing, got %+v", remaining)
+			}
+		})
+	}
+}
+
+func TestFixKeepsItems(t *testing.T) {
+	file := writeNote(t, withChange("- [ ] Write report", "- [x] Write report\n- [ ] Call Bob"))
+	fixNote(t, file, func(note *notes.Note) bool { return Fix(file, note, Check(file)) })
+
+	note, err := notes.NewParser(filepath.Dir(file.Path), "Acme").ParseFile(file.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(note.PendingWork) != 1 || note.PendingWork[0].Text != "Call Bob" {
+		t.Errorf("expected only 'Call Bob' pending, got %+v", note.PendingWork)
+	}
+	if len(note.CompletedWork) != 2 || note.CompletedWork[1].Text != "Write report" {
+		t.Errorf("expected 'Write report' moved to completed work, got %+v", note.CompletedWork)
+	}
+}
+
+func TestUnterminatedFrontmatter(t *testing.T) {
+	file := writeNote(t, withChange("date: 2026-10-18\n---\n", "date: 2026-10-18\n"))
+	diagnostics := Check(file)
+	found := ruleDiagnostics(diagnostics, "frontmatter")
+	if len(found) != 1 {
+		t.Fatalf("expected one frontmatter diagnostic, got %+v", diagnostics)
+	}
+	if found[0].Fixable {
+		t.Error("unterminated frontmatter can't be fixed safely, but was reported as fixable")
+	}
+	for _, rule := range []string{"id", "date", "tags"} {
+		if d := ruleDiagnostics(diagnostics, rule); len(d) > 0 {
+			t.Errorf("expected the %s rule to leave unterminated frontmatter to the frontmatter rule, got %+v", rule, d)
+		}
+	}
+
+	note := notes.NewNote(testDate, "Acme")
+	if Fix(file, note, found) {
+		t.Error("expected Fix to leave unterminated frontmatter alone")
+	}
+}
+
+func TestSharedPath(t *testing.T) {
+	file := writeNote(t, healthyNote)
+	if found := ruleDiagnostics(Check(file), "shared-path"); len(found) > 0 {
+		t.Fatalf("expected a note of one workplace to pass, got %+v", found)
+	}
+
+	file.SharedWith = []string{"Beta", "Gamma"}
+	found := ruleDiagnostics(Check(file), "shared-path")
+	if len(found) != 1 || !strings.Contains(found[0].Message, "Beta, Gamma") {
+		t.Fatalf("expected one shared-path diagnostic naming the other workplaces, got %+v", found)
+	}
+	if found[0].Fixable || found[0].Severity != SeverityError {
+		t.Errorf("expected a shared note to be an error that isn't fixed automatically, got %+v", found[0])
+	}
+}
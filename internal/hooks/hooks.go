@@ -0,0 +1,232 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sandepten/work-obsidian-noter/internal/config"
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+)
+
+// Events hooks can run on
+const (
+	NoteCreated      = "note.created"
+	ItemAdded        = "item.added"
+	ItemCompleted    = "item.completed"
+	ItemDeleted      = "item.deleted"
+	SummaryGenerated = "summary.generated"
+	WorkplaceRenamed = "workplace.renamed"
+)
+
+// Events lists every event hooks can run on
+var Events = []string{NoteCreated, ItemAdded, ItemCompleted, ItemDeleted, SummaryGenerated, WorkplaceRenamed}
+
+// Policy decides what happens when a hook fails
+type Policy string
+
+const (
+	Warn   Policy = "warn"   // Report the failure and carry on (the default)
+	Ignore Policy = "ignore" // Carry on silently
+	Abort  Policy = "abort"  // Skip the remaining hooks and fail the command
+)
+
+// DefaultTimeout is how long a hook may run when it doesn't set a timeout
+const DefaultTimeout = 10 * time.Second
+
+// Hook is an executable run on an event
+type Hook struct {
+	Event      string
+	Command    string
+	Args       []string
+	Timeout    time.Duration
+	OnFailure  Policy
+	Workplaces []string // Only run for these workplaces; empty for all
+}
+
+// Name returns how the hook is referred to in messages
+func (h Hook) Name() string {
+	return filepath.Base(h.Command)
+}
+
+// Item is a work item as hooks receive it
+type Item struct {
+	Text  string   `json:"text"`
+	Done  bool     `json:"done"`
+	State string   `json:"state,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// Event is what happened, passed to hooks as JSON on stdin
+type Event struct {
+	Event     string    `json:"event"`
+	Time      time.Time `json:"time"`
+	Profile   string    `json:"profile,omitempty"`
+	Workplace string    `json:"workplace,omitempty"`
+	Note      string    `json:"note,omitempty"` // Path of the note
+	Date      string    `json:"date,omitempty"` // Date of the note, YYYY-MM-DD
+	Items     []Item    `json:"items,omitempty"`
+	Summary   string    `json:"summary,omitempty"`
+	OldName   string    `json:"old_name,omitempty"`
+	NewName   string    `json:"new_name,omitempty"`
+}
+
+// ItemsOf converts work items for an event
+func ItemsOf(items []notes.WorkItem) []Item {
+	converted := make([]Item, len(items))
+	for i, item := range items {
+		converted[i] = Item{Text: item.Text, Done: item.Completed, Tags: item.Tags()}
+		if item.State != notes.StateNone {
+			converted[i].State = item.State.String()
+		}
+	}
+	return converted
+}
+
+// Parse checks the hooks of the config file and fills in their defaults
+func Parse(configured []config.Hook) ([]Hook, error) {
+	var parsed []Hook
+	for i, c := range configured {
+		label := fmt.Sprintf("hooks[%d]", i)
+		if !isEvent(c.Event) {
+			return nil, fmt.Errorf("%s: unknown event %q (use %s)", label, c.Event, strings.Join(Events, ", "))
+		}
+		if strings.TrimSpace(c.Run) == "" {
+			return nil, fmt.Errorf("%s: run is empty", label)
+		}
+
+		hook := Hook{Event: c.Event, Command: c.Run, Args: c.Args, Timeout: DefaultTimeout, OnFailure: Warn, Workplaces: c.Workplaces}
+		if c.Timeout != "" {
+			timeout, err := time.ParseDuration(c.Timeout)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("%s: invalid timeout %q, use a duration such as 5s", label, c.Timeout)
+			}
+			hook.Timeout = timeout
+		}
+		switch policy := Policy(c.OnFailure); policy {
+		case "":
+		case Warn, Ignore, Abort:
+			hook.OnFailure = policy
+		default:
+			return nil, fmt.Errorf("%s: invalid on_failure %q (use warn, ignore or abort)", label, c.OnFailure)
+		}
+		parsed = append(parsed, hook)
+	}
+	return parsed, nil
+}
+
+// isEvent reports whether hooks can run on an event
+func isEvent(name string) bool {
+	for _, event := range Events {
+		if event == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Runner runs the hooks of events
+type Runner struct {
+	hooks  []Hook
+	output io.Writer    // Where the output of hooks goes
+	warn   func(string) // Reports failures of hooks with on_failure: warn
+}
+
+// NewRunner returns a runner for hooks, passing their output to output and
+// failures to be warned about to warn
+func NewRunner(hooks []Hook, output io.Writer, warn func(string)) *Runner {
+	return &Runner{hooks: hooks, output: output, warn: warn}
+}
+
+// WithOutput returns a runner for the same hooks passing their output and
+// failures elsewhere
+func (r *Runner) WithOutput(output io.Writer, warn func(string)) *Runner {
+	return &Runner{hooks: r.hooks, output: output, warn: warn}
+}
+
+// Hooks returns the hooks that run on an event for a workplace, in order
+func (r *Runner) Hooks(event, workplace string) []Hook {
+	var matching []Hook
+	for _, hook := range r.hooks {
+		if hook.Event == event && (len(hook.Workplaces) == 0 || workplace == "" || containsString(hook.Workplaces, workplace)) {
+			matching = append(matching, hook)
+		}
+	}
+	return matching
+}
+
+// Run runs the hooks of an event one after the other, in the order they are
+// configured. It only returns an error when a hook with on_failure: abort
+// fails, and then skips the hooks after it.
+func (r *Runner) Run(e Event) error {
+	hooks := r.Hooks(e.Event, e.Workplace)
+	if len(hooks) == 0 {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range hooks {
+		err := r.run(hook, e.Event, payload)
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("%s hook %s failed: %w", e.Event, hook.Name(), err)
+		switch hook.OnFailure {
+		case Abort:
+			return err
+		case Warn:
+			if r.warn != nil {
+				r.warn(err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// run runs one hook with the event on stdin
+func (r *Runner) run(hook Hook, event string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hook.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, hook.Command, hook.Args...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = r.output
+	cmd.Stderr = r.output
+	cmd.Env = append(os.Environ(), "WORKLOG_EVENT="+event)
+	// Don't wait for children that keep the output open after a timeout
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", hook.Timeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("exit status %d", exitErr.ExitCode())
+	}
+	return err
+}
+
+// containsString reports whether a list holds a value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
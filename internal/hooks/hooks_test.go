@@ -0,0 +1,240 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sandepten/work-obsidian-noter/internal/config"
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+)
+
+// script writes an executable shell script into dir and returns its path
+func script(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// recorder writes a script that appends its first argument to log, and a
+// hook running it with that argument
+func recorder(t *testing.T, dir, log, name string) Hook {
+	t.Helper()
+	path := script(t, dir, "record", `echo "$1" >> "`+log+`"`)
+	return Hook{Event: ItemCompleted, Command: path, Args: []string{name}, Timeout: 5 * time.Second, OnFailure: Warn}
+}
+
+// readLines returns the lines of a file, or none when it doesn't exist
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Fields(string(data))
+}
+
+func TestRunPassesEventOnStdin(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "payload.json")
+	env := filepath.Join(dir, "event.txt")
+	path := script(t, dir, "capture", `cat > "`+out+`"; echo "$WORKLOG_EVENT" > "`+env+`"`)
+
+	runner := NewRunner([]Hook{{Event: ItemCompleted, Command: path, Timeout: 5 * time.Second, OnFailure: Warn}}, &bytes.Buffer{}, nil)
+	items := ItemsOf([]notes.WorkItem{
+		{Text: "Ship #release", Completed: true},
+		{Text: "Wait for review", State: notes.StateBlocked},
+	})
+	sent := Event{Event: ItemCompleted, Profile: "work", Workplace: "Acme", Note: "/notes/2026-10-18-Acme.md", Date: "2026-10-18", Items: items}
+	if err := runner.Run(sent); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received Event
+	if err := json.Unmarshal(data, &received); err != nil {
+		t.Fatalf("invalid JSON on stdin: %v\n%s", err, data)
+	}
+	if received.Time.IsZero() {
+		t.Error("expected the event time to be set")
+	}
+	received.Time = time.Time{}
+	if !reflect.DeepEqual(received, sent) {
+		t.Errorf("got %+v, want %+v", received, sent)
+	}
+	if want := []Item{{Text: "Ship #release", Done: true, Tags: []string{"release"}}, {Text: "Wait for review", State: "blocked"}}; !reflect.DeepEqual(received.Items, want) {
+		t.Errorf("items: got %+v, want %+v", received.Items, want)
+	}
+	if got := readLines(t, env); !reflect.DeepEqual(got, []string{ItemCompleted}) {
+		t.Errorf("WORKLOG_EVENT: got %q", got)
+	}
+}
+
+func TestRunOrderAndFilters(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+
+	first := recorder(t, dir, log, "first")
+	second := recorder(t, dir, log, "second")
+	otherEvent := recorder(t, dir, log, "other-event")
+	otherEvent.Event = ItemAdded
+	otherWorkplace := recorder(t, dir, log, "other-workplace")
+	otherWorkplace.Workplaces = []string{"Zeta"}
+	onlyAcme := recorder(t, dir, log, "only-acme")
+	onlyAcme.Workplaces = []string{"Acme"}
+	third := recorder(t, dir, log, "third")
+
+	runner := NewRunner([]Hook{first, second, otherEvent, otherWorkplace, onlyAcme, third}, &bytes.Buffer{}, nil)
+	if err := runner.Run(Event{Event: ItemCompleted, Workplace: "Acme"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := readLines(t, log), []string{"first", "second", "only-acme", "third"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// An event without a workplace, such as a summary of every workplace, runs all hooks of the event
+	os.Remove(log)
+	if err := runner.Run(Event{Event: ItemCompleted}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := readLines(t, log), []string{"first", "second", "other-workplace", "only-acme", "third"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("without a workplace: got %q, want %q", got, want)
+	}
+}
+
+func TestRunFailurePolicies(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		timeout   time.Duration
+		policy    Policy
+		wantErr   string
+		wantWarn  string
+		wantAfter bool // Whether the hook after the failing one runs
+	}{
+		{"warn on exit status", "exit 3", 5 * time.Second, Warn, "", "exit status 3", true},
+		{"ignore exit status", "exit 3", 5 * time.Second, Ignore, "", "", true},
+		{"abort on exit status", "exit 3", 5 * time.Second, Abort, "exit status 3", "", false},
+		{"warn on timeout", "sleep 5", 200 * time.Millisecond, Warn, "", "timed out after 200ms", true},
+		{"abort on timeout", "sleep 5", 200 * time.Millisecond, Abort, "timed out after 200ms", "", false},
+		{"succeeding hook", "exit 0", 5 * time.Second, Abort, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			log := filepath.Join(dir, "log")
+			failing := Hook{Event: ItemCompleted, Command: script(t, dir, "failing", tt.body), Timeout: tt.timeout, OnFailure: tt.policy}
+			after := recorder(t, dir, log, "after")
+
+			var warnings []string
+			runner := NewRunner([]Hook{failing, after}, &bytes.Buffer{}, func(message string) { warnings = append(warnings, message) })
+			start := time.Now()
+			err := runner.Run(Event{Event: ItemCompleted, Workplace: "Acme"})
+			if elapsed := time.Since(start); elapsed > 3*time.Second {
+				t.Errorf("hooks ran for %s, the timeout wasn't enforced", elapsed)
+			}
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+			if tt.wantWarn == "" && len(warnings) > 0 {
+				t.Errorf("unexpected warnings: %q", warnings)
+			}
+			if tt.wantWarn != "" && (len(warnings) != 1 || !strings.Contains(warnings[0], tt.wantWarn)) {
+				t.Errorf("expected one warning containing %q, got %q", tt.wantWarn, warnings)
+			}
+			if ran := len(readLines(t, log)) > 0; ran != tt.wantAfter {
+				t.Errorf("hook after the failing one ran: %v, want %v", ran, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestRunPassesOutput(t *testing.T) {
+	dir := t.TempDir()
+	path := script(t, dir, "talk", `echo "to stdout"; echo "to stderr" >&2`)
+	var output bytes.Buffer
+	runner := NewRunner([]Hook{{Event: NoteCreated, Command: path, Timeout: 5 * time.Second, OnFailure: Warn}}, &output, nil)
+	if err := runner.Run(Event{Event: NoteCreated}); err != nil {
+		t.Fatal(err)
+	}
+	if got := output.String(); !strings.Contains(got, "to stdout") || !strings.Contains(got, "to stderr") {
+		t.Errorf("expected both streams in the output, got %q", got)
+	}
+
+	// WithOutput sends the same hooks' output elsewhere
+	var quiet bytes.Buffer
+	if err := runner.WithOutput(&quiet, nil).Run(Event{Event: NoteCreated}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(quiet.String(), "to stdout") {
+		t.Errorf("expected the output in the new writer, got %q", quiet.String())
+	}
+}
+
+func TestRunMissingCommand(t *testing.T) {
+	var warnings []string
+	runner := NewRunner([]Hook{{Event: NoteCreated, Command: filepath.Join(t.TempDir(), "missing"), Timeout: time.Second, OnFailure: Warn}},
+		&bytes.Buffer{}, func(message string) { warnings = append(warnings, message) })
+	if err := runner.Run(Event{Event: NoteCreated}); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "note.created hook missing failed") {
+		t.Errorf("expected a warning about the missing command, got %q", warnings)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    config.Hook
+		want    Hook
+		wantErr string
+	}{
+		{"defaults", config.Hook{Event: ItemAdded, Run: "/bin/true"},
+			Hook{Event: ItemAdded, Command: "/bin/true", Timeout: DefaultTimeout, OnFailure: Warn}, ""},
+		{"every field", config.Hook{Event: WorkplaceRenamed, Run: "/bin/true", Args: []string{"-v"}, Timeout: "2s", OnFailure: "abort", Workplaces: []string{"Acme"}},
+			Hook{Event: WorkplaceRenamed, Command: "/bin/true", Args: []string{"-v"}, Timeout: 2 * time.Second, OnFailure: Abort, Workplaces: []string{"Acme"}}, ""},
+		{"unknown event", config.Hook{Event: "item.exploded", Run: "/bin/true"}, Hook{}, `hooks[0]: unknown event "item.exploded"`},
+		{"empty run", config.Hook{Event: ItemAdded, Run: " "}, Hook{}, "hooks[0]: run is empty"},
+		{"invalid timeout", config.Hook{Event: ItemAdded, Run: "/bin/true", Timeout: "soon"}, Hook{}, `hooks[0]: invalid timeout "soon"`},
+		{"negative timeout", config.Hook{Event: ItemAdded, Run: "/bin/true", Timeout: "-1s"}, Hook{}, `hooks[0]: invalid timeout "-1s"`},
+		{"invalid policy", config.Hook{Event: ItemAdded, Run: "/bin/true", OnFailure: "panic"}, Hook{}, `hooks[0]: invalid on_failure "panic"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := Parse([]config.Hook{tt.hook})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(parsed) != 1 || !reflect.DeepEqual(parsed[0], tt.want) {
+				t.Errorf("got %+v, want %+v", parsed, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package notes
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// inlineFieldRegex matches Dataview-style inline fields such as [due:: 2025-01-31]
+var inlineFieldRegex = regexp.MustCompile(`\[([\p{L}\p{N}_-]+)::\s*([^\]]*)\]`)
+
+// Title returns the item text without its inline fields
+func (w WorkItem) Title() string {
+	return strings.Join(strings.Fields(inlineFieldRegex.ReplaceAllString(w.Text, "")), " ")
+}
+
+// Field returns the value of an inline [key:: value] field
+func (w WorkItem) Field(key string) (string, bool) {
+	for _, match := range inlineFieldRegex.FindAllStringSubmatch(w.Text, -1) {
+		if strings.EqualFold(match[1], key) {
+			return strings.TrimSpace(match[2]), true
+		}
+	}
+	return "", false
+}
+
+// SetField sets an inline [key:: value] field, replacing an existing one
+func (w *WorkItem) SetField(key, value string) {
+	field := fmt.Sprintf("[%s:: %s]", key, value)
+	replaced := false
+	w.Text = inlineFieldRegex.ReplaceAllStringFunc(w.Text, func(match string) string {
+		if replaced || !strings.EqualFold(inlineFieldRegex.FindStringSubmatch(match)[1], key) {
+			return match
+		}
+		replaced = true
+		return field
+	})
+	if !replaced {
+		w.Text = strings.TrimSpace(w.Text) + " " + field
+	}
+}
+
+// RemoveField removes an inline field
+func (w *WorkItem) RemoveField(key string) {
+	w.Text = inlineFieldRegex.ReplaceAllStringFunc(w.Text, func(match string) string {
+		if strings.EqualFold(inlineFieldRegex.FindStringSubmatch(match)[1], key) {
+			return ""
+		}
+		return match
+	})
+	w.Text = strings.Join(strings.Fields(w.Text), " ")
+}
+
+// SetTitle replaces the item text while keeping its #tags and inline fields. A
+// title that brings its own tags or fields replaces those of the item.
+func (w *WorkItem) SetTitle(title string) {
+	parts := []string{strings.TrimSpace(title)}
+	if !itemTagRegex.MatchString(title) {
+		for _, match := range itemTagRegex.FindAllStringSubmatch(w.Text, -1) {
+			parts = append(parts, "#"+match[1])
+		}
+	}
+	if !inlineFieldRegex.MatchString(title) {
+		parts = append(parts, inlineFieldRegex.FindAllString(w.Text, -1)...)
+	}
+	w.Text = strings.Join(parts, " ")
+}
+
+// FormatItem renders an item as markdown checklist lines
+func FormatItem(item WorkItem) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("- %s %s\n", item.Box(), item.Text))
+	for _, child := range item.Children {
+		sb.WriteString(child + "\n")
+	}
+	return sb.String()
+}
+
+// ParseItems reads markdown checklist lines, such as a list edited by hand.
+// Indented lines belong to the item above; other lines are ignored.
+func ParseItems(r io.Reader) ([]WorkItem, error) {
+	var items []WorkItem
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if item := parseWorkItem(line); item != nil && !isIndented(line) {
+			items = append(items, *item)
+			continue
+		}
+		if isIndented(line) && strings.TrimSpace(line) != "" && len(items) > 0 {
+			last := &items[len(items)-1]
+			last.Children = append(last.Children, line)
+		}
+	}
+	return items, scanner.Err()
+}
+
+// isIndented reports whether a line starts with whitespace
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
+
+// itemList returns the list of items for a section kind
+func (n *Note) itemList(kind SectionKind) (*[]WorkItem, error) {
+	switch kind {
+	case SectionPending:
+		return &n.PendingWork, nil
+	case SectionCompleted:
+		return &n.CompletedWork, nil
+	}
+	return nil, fmt.Errorf("section holds no work items")
+}
+
+// checkIndex returns an error when index is outside a list of n items
+func checkIndex(index, n int) error {
+	if index < 0 || index >= n {
+		return fmt.Errorf("item %d does not exist (the list has %d item(s))", index+1, n)
+	}
+	return nil
+}
+
+// EditItem changes the text of an item, keeping its inline fields and sub-items
+func (n *Note) EditItem(kind SectionKind, index int, title string) error {
+	items, err := n.itemList(kind)
+	if err != nil {
+		return err
+	}
+	if err := checkIndex(index, len(*items)); err != nil {
+		return err
+	}
+	if strings.TrimSpace(title) == "" {
+		return fmt.Errorf("item text cannot be empty")
+	}
+	(*items)[index].SetTitle(title)
+	return nil
+}
+
+// MoveItem moves an item to position to within its list
+func (n *Note) MoveItem(kind SectionKind, from, to int) error {
+	items, err := n.itemList(kind)
+	if err != nil {
+		return err
+	}
+	if err := checkIndex(from, len(*items)); err != nil {
+		return err
+	}
+	if err := checkIndex(to, len(*items)); err != nil {
+		return err
+	}
+
+	item := (*items)[from]
+	list := append((*items)[:from:from], (*items)[from+1:]...)
+	list = append(list[:to], append([]WorkItem{item}, list[to:]...)...)
+	*items = list
+	return nil
+}
+
+// TakeItem removes an item and returns it, for moving it to another note
+func (n *Note) TakeItem(kind SectionKind, index int) (WorkItem, error) {
+	items, err := n.itemList(kind)
+	if err != nil {
+		return WorkItem{}, err
+	}
+	if err := checkIndex(index, len(*items)); err != nil {
+		return WorkItem{}, err
+	}
+
+	item := (*items)[index]
+	*items = append((*items)[:index:index], (*items)[index+1:]...)
+	return item, nil
+}
+
+// PutItem appends an item to a list, setting its checkbox to match
+func (n *Note) PutItem(kind SectionKind, item WorkItem) error {
+	items, err := n.itemList(kind)
+	if err != nil {
+		return err
+	}
+	item.Completed = kind == SectionCompleted
+	if item.Completed {
+		item.State = StateNone
+	}
+	*items = append(*items, item)
+	return nil
+}
+
+// SetItems replaces a note's items, sorting them into pending and completed by
+// their checkbox. Cancelled, deferred and blocked items go to pending.
+func (n *Note) SetItems(items []WorkItem) {
+	n.PendingWork = []WorkItem{}
+	n.CompletedWork = []WorkItem{}
+	for _, item := range items {
+		if item.Completed {
+			n.CompletedWork = append(n.CompletedWork, item)
+		} else {
+			n.PendingWork = append(n.PendingWork, item)
+		}
+	}
+}
+
+// FindPendingItem returns the index of the pending item with the given title,
+// ignoring case, inline fields and spacing, or -1 if there is none
+func (n *Note) FindPendingItem(text string) int {
+	title := WorkItem{Text: text}.Title()
+	for i, item := range n.PendingWork {
+		if strings.EqualFold(item.Title(), title) {
+			return i
+		}
+	}
+	return -1
+}
+
+// CarryOver adds a pending item brought over from an earlier note. It replaces
+// the same item already added to the note, such as a recurring item that is
+// due again, keeping the carried item's fields and sub-items.
+func (n *Note) CarryOver(item WorkItem) {
+	if i := n.FindPendingItem(item.Text); i >= 0 {
+		n.PendingWork[i] = item
+		return
+	}
+	n.PendingWork = append(n.PendingWork, item)
+}
@@ -0,0 +1,307 @@
+package notes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sandepten/work-obsidian-noter/internal/recurring"
+)
+
+// richItem is a pending item with every kind of metadata an item can carry
+func richItem() WorkItem {
+	return WorkItem{
+		Text:     "Review PR #backend [due:: 2026-10-20] [reason:: waiting on CI]",
+		State:    StateBlocked,
+		Children: []string{"  - [ ] Check the tests", "  Notes from the call"},
+	}
+}
+
+// testNote returns a note with three pending items, the second one rich, and
+// one completed item
+func testNote() *Note {
+	note := NewNote(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), "Acme")
+	note.PendingWork = []WorkItem{{Text: "First"}, richItem(), {Text: "Third"}}
+	note.CompletedWork = []WorkItem{{Text: "Done #ops", Completed: true}}
+	return note
+}
+
+// texts returns the text of every item in a list
+func texts(items []WorkItem) []string {
+	var list []string
+	for _, item := range items {
+		list = append(list, item.Text)
+	}
+	return list
+}
+
+// checkMetadata fails when an item lost the tags, fields, state or sub-items of richItem
+func checkMetadata(t *testing.T, item WorkItem, state ItemState) {
+	t.Helper()
+	want := richItem()
+	if got := item.Tags(); !reflect.DeepEqual(got, want.Tags()) {
+		t.Errorf("tags: got %v, want %v", got, want.Tags())
+	}
+	for _, key := range []string{"due", "reason"} {
+		wantValue, _ := want.Field(key)
+		if got, ok := item.Field(key); !ok || got != wantValue {
+			t.Errorf("field %s: got %q (%v), want %q", key, got, ok, wantValue)
+		}
+	}
+	if item.State != state {
+		t.Errorf("state: got %q, want %q", item.State, state)
+	}
+	if !reflect.DeepEqual(item.Children, want.Children) {
+		t.Errorf("children: got %q, want %q", item.Children, want.Children)
+	}
+}
+
+func TestItemIndexErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(n *Note) error
+	}{
+		{"edit before the first item", func(n *Note) error { return n.EditItem(SectionPending, -1, "New") }},
+		{"edit after the last item", func(n *Note) error { return n.EditItem(SectionPending, 3, "New") }},
+		{"edit in an empty list", func(n *Note) error { n.CompletedWork = nil; return n.EditItem(SectionCompleted, 0, "New") }},
+		{"edit in another section", func(n *Note) error { return n.EditItem(SectionOther, 0, "New") }},
+		{"edit to empty text", func(n *Note) error { return n.EditItem(SectionPending, 0, "  ") }},
+		{"move from outside the list", func(n *Note) error { return n.MoveItem(SectionPending, 3, 0) }},
+		{"move to outside the list", func(n *Note) error { return n.MoveItem(SectionPending, 0, 3) }},
+		{"move to a negative position", func(n *Note) error { return n.MoveItem(SectionPending, 0, -1) }},
+		{"take outside the list", func(n *Note) error { _, err := n.TakeItem(SectionCompleted, 1); return err }},
+		{"take a negative index", func(n *Note) error { _, err := n.TakeItem(SectionPending, -1); return err }},
+		{"put into another section", func(n *Note) error { return n.PutItem(SectionOther, WorkItem{Text: "New"}) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := testNote()
+			before := texts(note.PendingWork)
+			if err := tt.run(note); err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := texts(note.PendingWork); !reflect.DeepEqual(got, before) {
+				t.Errorf("a failed call changed the items: got %q, want %q", got, before)
+			}
+		})
+	}
+}
+
+func TestMoveItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+		want     []string
+	}{
+		{"to the first position", 2, 0, []string{"Third", "First", richItem().Text}},
+		{"to the last position", 0, 2, []string{richItem().Text, "Third", "First"}},
+		{"down by one", 0, 1, []string{richItem().Text, "First", "Third"}},
+		{"up by one", 2, 1, []string{"First", "Third", richItem().Text}},
+		{"to the same position", 1, 1, []string{"First", richItem().Text, "Third"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := testNote()
+			if err := note.MoveItem(SectionPending, tt.from, tt.to); err != nil {
+				t.Fatal(err)
+			}
+			if got := texts(note.PendingWork); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// The rich item keeps its metadata wherever it goes
+	note := testNote()
+	if err := note.MoveItem(SectionPending, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	checkMetadata(t, note.PendingWork[0], StateBlocked)
+}
+
+func TestTakeAndPutItem(t *testing.T) {
+	source, target := testNote(), testNote()
+	target.PendingWork = nil
+
+	item, err := source.TakeItem(SectionPending, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := texts(source.PendingWork), []string{"First", "Third"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("source after take: got %q, want %q", got, want)
+	}
+
+	// Back into pending work, the item is unchanged
+	if err := target.PutItem(SectionPending, item); err != nil {
+		t.Fatal(err)
+	}
+	if len(target.PendingWork) != 1 || !reflect.DeepEqual(target.PendingWork[0], richItem()) {
+		t.Errorf("round trip changed the item: got %+v, want %+v", target.PendingWork, richItem())
+	}
+
+	// Into completed work, it is checked and loses its state, but keeps the rest
+	if err := target.PutItem(SectionCompleted, item); err != nil {
+		t.Fatal(err)
+	}
+	completed := target.CompletedWork[len(target.CompletedWork)-1]
+	if !completed.Completed {
+		t.Error("an item put into completed work should be checked")
+	}
+	checkMetadata(t, completed, StateNone)
+
+	// And back out of completed work into pending, it is unchecked again
+	taken, err := target.TakeItem(SectionCompleted, len(target.CompletedWork)-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := source.PutItem(SectionPending, taken); err != nil {
+		t.Fatal(err)
+	}
+	if reopened := source.PendingWork[len(source.PendingWork)-1]; reopened.Completed {
+		t.Error("an item put into pending work should be unchecked")
+	}
+}
+
+func TestEditItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		wantText string
+	}{
+		{"keeps tags and fields", "Review the PR", "Review the PR #backend [due:: 2026-10-20] [reason:: waiting on CI]"},
+		{"new tags replace old ones", "Review the PR #frontend", "Review the PR #frontend [due:: 2026-10-20] [reason:: waiting on CI]"},
+		{"new fields replace old ones", "Review the PR [due:: 2026-10-25]", "Review the PR [due:: 2026-10-25] #backend"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := testNote()
+			if err := note.EditItem(SectionPending, 1, tt.title); err != nil {
+				t.Fatal(err)
+			}
+			item := note.PendingWork[1]
+			if item.Text != tt.wantText {
+				t.Errorf("got %q, want %q", item.Text, tt.wantText)
+			}
+			if item.State != StateBlocked || !reflect.DeepEqual(item.Children, richItem().Children) {
+				t.Errorf("lost state or sub-items: %+v", item)
+			}
+		})
+	}
+}
+
+func TestItemFields(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(w *WorkItem)
+		want string
+	}{
+		{"set a new field", func(w *WorkItem) { w.SetField("owner", "sam") }, "Ship #ops [due:: 2026-10-20] [owner:: sam]"},
+		{"replace a field", func(w *WorkItem) { w.SetField("due", "2026-11-01") }, "Ship #ops [due:: 2026-11-01]"},
+		{"replace a field ignoring case", func(w *WorkItem) { w.SetField("DUE", "2026-11-01") }, "Ship #ops [DUE:: 2026-11-01]"},
+		{"remove a field", func(w *WorkItem) { w.RemoveField("due") }, "Ship #ops"},
+		{"remove a missing field", func(w *WorkItem) { w.RemoveField("owner") }, "Ship #ops [due:: 2026-10-20]"},
+		{"set the title", func(w *WorkItem) { w.SetTitle("Deploy") }, "Deploy #ops [due:: 2026-10-20]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := WorkItem{Text: "Ship #ops [due:: 2026-10-20]"}
+			tt.edit(&item)
+			if item.Text != tt.want {
+				t.Errorf("got %q, want %q", item.Text, tt.want)
+			}
+		})
+	}
+
+	if title := (WorkItem{Text: "Ship #ops [due:: 2026-10-20]"}).Title(); title != "Ship #ops" {
+		t.Errorf("Title: got %q, want %q", title, "Ship #ops")
+	}
+}
+
+func TestSetItems(t *testing.T) {
+	note := testNote()
+	items, err := ParseItems(strings.NewReader(strings.Join([]string{
+		"- [x] Shipped #ops",
+		"- [ ] Write docs [due:: 2026-10-21]",
+		"  - outline first",
+		"- [-] Dropped",
+		"- [>] Later",
+		"not an item",
+		"- [!] Blocked #backend",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	note.SetItems(items)
+
+	if got, want := texts(note.PendingWork), []string{"Write docs [due:: 2026-10-21]", "Dropped", "Later", "Blocked #backend"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pending: got %q, want %q", got, want)
+	}
+	if got, want := texts(note.CompletedWork), []string{"Shipped #ops"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("completed: got %q, want %q", got, want)
+	}
+	if got := note.PendingWork[0].Children; !reflect.DeepEqual(got, []string{"  - outline first"}) {
+		t.Errorf("sub-items: got %q", got)
+	}
+	states := []ItemState{StateNone, StateCancelled, StateDeferred, StateBlocked}
+	for i, state := range states {
+		if note.PendingWork[i].State != state {
+			t.Errorf("item %d: got state %q, want %q", i, note.PendingWork[i].State, state)
+		}
+	}
+
+	// Formatting the items and reading them back keeps everything
+	var sb strings.Builder
+	for _, item := range append(note.PendingWork, note.CompletedWork...) {
+		sb.WriteString(FormatItem(item))
+	}
+	reparsed, err := ParseItems(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := append(note.PendingWork, note.CompletedWork...); !reflect.DeepEqual(reparsed, want) {
+		t.Errorf("round trip through FormatItem: got %+v, want %+v", reparsed, want)
+	}
+}
+
+func TestCarryOverRecurringItem(t *testing.T) {
+	tasks, err := recurring.Parse(strings.NewReader("@weekdays | Check the dashboard\nFREQ=MONTHLY;BYMONTHDAY=1 | Send invoices"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := NewWriter(t.TempDir(), "Acme")
+	writer.SetRecurring(tasks)
+
+	// Monday the 19th: only the weekday item is due
+	note, err := writer.CreateTodayNote(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := texts(note.PendingWork), []string{"Check the dashboard"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("recurring items: got %q, want %q", got, want)
+	}
+
+	// Yesterday's copy of the item replaces the new one rather than doubling it
+	carried := WorkItem{Text: "check the dashboard [time:: 1h]", Children: []string{"  - alerts were noisy"}}
+	note.CarryOver(carried)
+	note.CarryOver(WorkItem{Text: "Write report"})
+	if got, want := texts(note.PendingWork), []string{carried.Text, "Write report"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after carrying over: got %q, want %q", got, want)
+	}
+	if !reflect.DeepEqual(note.PendingWork[0].Children, carried.Children) {
+		t.Errorf("lost the carried item's sub-items: %+v", note.PendingWork[0])
+	}
+
+	// Sunday the 18th: nothing is due
+	sunday, err := writer.CreateTodayNote(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sunday.PendingWork) != 0 {
+		t.Errorf("expected no items on a Sunday, got %q", texts(sunday.PendingWork))
+	}
+}
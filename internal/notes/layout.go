@@ -0,0 +1,306 @@
+package notes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLayoutPattern is the classic flat layout: YYYY-MM-DD-WorkplaceName.md
+const DefaultLayoutPattern = "{YYYY}-{MM}-{DD}-{workplace}.md"
+
+// DefaultLayout is the layout used when none is configured
+var DefaultLayout = MustParseLayout(DefaultLayoutPattern)
+
+// layoutTokenRegex matches a {token} placeholder in a layout pattern
+var layoutTokenRegex = regexp.MustCompile(`\{([A-Za-z_]+)\}`)
+
+// layoutTokens maps each supported token to the regex used when matching paths
+var layoutTokens = map[string]string{
+	"YYYY":            `(\d{4})`,
+	"YY":              `(\d{2})`,
+	"MM":              `(\d{2})`,
+	"M":               `(\d{1,2})`,
+	"MMM":             `([A-Z][a-z]{2})`,
+	"MMMM":            `([A-Z][a-z]+)`,
+	"DD":              `(\d{2})`,
+	"D":               `(\d{1,2})`,
+	"ddd":             `([A-Z][a-z]{2})`,
+	"dddd":            `([A-Z][a-z]+)`,
+	"WW":              `(\d{2})`,
+	"GGGG":            `(\d{4})`,
+	"workplace":       ``,
+	"workplace_lower": ``,
+}
+
+// Layout describes where a workplace's notes live relative to the notes directory.
+//
+// A layout is a slash-separated path pattern made of literal text and tokens:
+//
+//	{YYYY} {YY}        year
+//	{MM} {M}           month number (padded / unpadded)
+//	{MMM} {MMMM}       month name (Jan / January)
+//	{DD} {D}           day of month (padded / unpadded)
+//	{ddd} {dddd}       weekday name (Mon / Monday)
+//	{WW} {GGGG}        ISO week number and ISO week-year
+//	{workplace}        workplace name as configured
+//	{workplace_lower}  workplace name in lowercase
+//
+// For example "Work/{workplace}/{YYYY}/{MM}/{YYYY}-{MM}-{DD}.md".
+type Layout struct {
+	pattern string
+}
+
+// ParseLayout validates a layout pattern
+func ParseLayout(pattern string) (Layout, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return Layout{}, fmt.Errorf("layout cannot be empty")
+	}
+	if filepath.IsAbs(pattern) || strings.HasPrefix(pattern, "/") {
+		return Layout{}, fmt.Errorf("layout %q must be relative to the notes directory", pattern)
+	}
+	if !strings.HasSuffix(pattern, ".md") {
+		return Layout{}, fmt.Errorf("layout %q must end with .md", pattern)
+	}
+	for _, segment := range strings.Split(pattern, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return Layout{}, fmt.Errorf("layout %q contains an invalid path segment", pattern)
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, match := range layoutTokenRegex.FindAllStringSubmatch(pattern, -1) {
+		if _, ok := layoutTokens[match[1]]; !ok {
+			return Layout{}, fmt.Errorf("layout %q uses unknown token {%s}", pattern, match[1])
+		}
+		used[match[1]] = true
+	}
+
+	if !used["YYYY"] && !used["YY"] {
+		return Layout{}, fmt.Errorf("layout %q must include a year token ({YYYY} or {YY})", pattern)
+	}
+	if !used["MM"] && !used["M"] && !used["MMM"] && !used["MMMM"] {
+		return Layout{}, fmt.Errorf("layout %q must include a month token ({MM}, {M}, {MMM} or {MMMM})", pattern)
+	}
+	if !used["DD"] && !used["D"] {
+		return Layout{}, fmt.Errorf("layout %q must include a day token ({DD} or {D})", pattern)
+	}
+
+	return Layout{pattern: pattern}, nil
+}
+
+// MustParseLayout is like ParseLayout but panics on an invalid pattern
+func MustParseLayout(pattern string) Layout {
+	layout, err := ParseLayout(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return layout
+}
+
+// String returns the layout pattern
+func (l Layout) String() string {
+	if l.pattern == "" {
+		return DefaultLayoutPattern
+	}
+	return l.pattern
+}
+
+// PerWorkplace reports whether the layout includes the workplace name, so that
+// workplaces sharing a notes directory still get their own files
+func (l Layout) PerWorkplace() bool {
+	for _, match := range layoutTokenRegex.FindAllStringSubmatch(l.String(), -1) {
+		if match[1] == "workplace" || match[1] == "workplace_lower" {
+			return true
+		}
+	}
+	return false
+}
+
+// Path returns the note path for the given date and workplace, relative to the notes directory
+func (l Layout) Path(date time.Time, workplaceName string) string {
+	rel := layoutTokenRegex.ReplaceAllStringFunc(l.String(), func(token string) string {
+		return formatLayoutToken(token[1:len(token)-1], date, workplaceName)
+	})
+	return filepath.FromSlash(rel)
+}
+
+// Match reports whether a path relative to the notes directory is a note of the
+// given workplace under this layout, and returns the note's date if so
+func (l Layout) Match(relPath, workplaceName string) (time.Time, bool) {
+	relPath = filepath.ToSlash(relPath)
+
+	var groups []string
+	var sb strings.Builder
+	sb.WriteString("^")
+	last := 0
+	pattern := l.String()
+	for _, loc := range layoutTokenRegex.FindAllStringSubmatchIndex(pattern, -1) {
+		sb.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		token := pattern[loc[2]:loc[3]]
+		switch token {
+		case "workplace":
+			sb.WriteString(regexp.QuoteMeta(workplaceName))
+		case "workplace_lower":
+			sb.WriteString(regexp.QuoteMeta(ToLowerCase(workplaceName)))
+		default:
+			sb.WriteString(layoutTokens[token])
+			groups = append(groups, token)
+		}
+		last = loc[1]
+	}
+	sb.WriteString(regexp.QuoteMeta(pattern[last:]))
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return time.Time{}, false
+	}
+	matches := re.FindStringSubmatch(relPath)
+	if matches == nil {
+		return time.Time{}, false
+	}
+
+	year, month, day := -1, -1, -1
+	for i, token := range groups {
+		value := matches[i+1]
+		switch token {
+		case "YYYY":
+			year, _ = strconv.Atoi(value)
+		case "YY":
+			if year < 0 {
+				yy, _ := strconv.Atoi(value)
+				year = 2000 + yy
+			}
+		case "MM", "M":
+			month, _ = strconv.Atoi(value)
+		case "MMM", "MMMM":
+			if month < 0 {
+				month = parseMonthName(value)
+			}
+		case "DD", "D":
+			day, _ = strconv.Atoi(value)
+		}
+	}
+	if year < 0 || month < 1 || month > 12 || day < 1 {
+		return time.Time{}, false
+	}
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	// Reject impossible dates and inconsistent tokens (e.g. a weekday that doesn't fit)
+	if filepath.ToSlash(l.Path(date, workplaceName)) != relPath {
+		return time.Time{}, false
+	}
+
+	return date, true
+}
+
+// depth returns how many directory levels the layout spans below the notes directory
+func (l Layout) depth() int {
+	return strings.Count(l.String(), "/")
+}
+
+// formatLayoutToken renders a single layout token
+func formatLayoutToken(token string, date time.Time, workplaceName string) string {
+	switch token {
+	case "YYYY":
+		return date.Format("2006")
+	case "YY":
+		return date.Format("06")
+	case "MM":
+		return date.Format("01")
+	case "M":
+		return date.Format("1")
+	case "MMM":
+		return date.Format("Jan")
+	case "MMMM":
+		return date.Format("January")
+	case "DD":
+		return date.Format("02")
+	case "D":
+		return date.Format("2")
+	case "ddd":
+		return date.Format("Mon")
+	case "dddd":
+		return date.Format("Monday")
+	case "WW":
+		_, week := date.ISOWeek()
+		return fmt.Sprintf("%02d", week)
+	case "GGGG":
+		year, _ := date.ISOWeek()
+		return fmt.Sprintf("%04d", year)
+	case "workplace":
+		return workplaceName
+	case "workplace_lower":
+		return ToLowerCase(workplaceName)
+	}
+	return "{" + token + "}"
+}
+
+// parseMonthName converts a short or long English month name to its number
+func parseMonthName(name string) int {
+	for m := time.January; m <= time.December; m++ {
+		if name == m.String() || name == m.String()[:3] {
+			return int(m)
+		}
+	}
+	return -1
+}
+
+// NoteFile is a note found on disk together with the date encoded in its path
+type NoteFile struct {
+	Path string
+	Date time.Time
+}
+
+// FindNoteFiles lists all notes of a workplace under notesDir that match the layout,
+// sorted by date ascending
+func FindNoteFiles(notesDir, workplaceName string, layout Layout) ([]NoteFile, error) {
+	var files []NoteFile
+	maxDepth := layout.depth()
+
+	err := filepath.WalkDir(notesDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if path == notesDir {
+				return err
+			}
+			return nil // Skip unreadable entries
+		}
+
+		rel, err := filepath.Rel(notesDir, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+
+		if d.IsDir() {
+			// Skip hidden folders such as .obsidian and .trash, and anything deeper than the layout
+			if strings.HasPrefix(d.Name(), ".") || strings.Count(filepath.ToSlash(rel), "/") >= maxDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if date, ok := layout.Match(rel, workplaceName); ok {
+			files = append(files, NoteFile{Path: path, Date: date})
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Date.Before(files[j].Date)
+	})
+
+	return files, nil
+}
@@ -0,0 +1,163 @@
+package notes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ScheduledItem is a work item deferred until a later date
+type ScheduledItem struct {
+	Text       string   `json:"text"`
+	Children   []string `json:"children,omitempty"`
+	Until      string   `json:"until"`       // YYYY-MM-DD
+	DeferredOn string   `json:"deferred_on"` // YYYY-MM-DD
+	From       string   `json:"from,omitempty"`
+}
+
+// Item returns the scheduled work item
+func (s ScheduledItem) Item() WorkItem {
+	return WorkItem{Text: s.Text, Children: s.Children}
+}
+
+// UntilDate returns the date the item comes back
+func (s ScheduledItem) UntilDate() time.Time {
+	t, _ := time.Parse("2006-01-02", s.Until)
+	return t
+}
+
+// Ledger holds the deferred items of a workplace until they are due
+type Ledger struct {
+	path  string
+	Items []ScheduledItem `json:"items"`
+}
+
+// LoadLedger reads a ledger file. A missing file is an empty ledger.
+func LoadLedger(path string) (*Ledger, error) {
+	l := &Ledger{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Save writes the ledger, replacing the file only once it is fully written
+func (l *Ledger) Save() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return err
+	}
+
+	data, err := l.Bytes()
+	if err != nil {
+		return err
+	}
+
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.path)
+}
+
+// Bytes returns the ledger as it is saved
+func (l *Ledger) Bytes() ([]byte, error) {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Merge adds the items of another ledger
+func (l *Ledger) Merge(other *Ledger) {
+	l.Items = append(l.Items, other.Items...)
+	l.sort()
+}
+
+// Add schedules an item to come back on a date
+func (l *Ledger) Add(item WorkItem, until, deferredOn time.Time, from string) {
+	l.Items = append(l.Items, ScheduledItem{
+		Text:       item.Text,
+		Children:   item.Children,
+		Until:      until.Format("2006-01-02"),
+		DeferredOn: deferredOn.Format("2006-01-02"),
+		From:       from,
+	})
+	l.sort()
+}
+
+// Due returns the items that are due on or before a date
+func (l *Ledger) Due(date time.Time) []ScheduledItem {
+	var due []ScheduledItem
+	for _, s := range l.Items {
+		if !s.UntilDate().After(date) {
+			due = append(due, s)
+		}
+	}
+	return due
+}
+
+// Remove drops the items with the given title. It reports whether any were dropped.
+func (l *Ledger) Remove(text string) bool {
+	title := WorkItem{Text: text}.Title()
+	kept := l.Items[:0]
+	for _, s := range l.Items {
+		if !strings.EqualFold(s.Item().Title(), title) {
+			kept = append(kept, s)
+		}
+	}
+	changed := len(kept) != len(l.Items)
+	l.Items = kept
+	return changed
+}
+
+// Delivered drops the items due by the note's date that are now in the note.
+// Notes from the day an item was deferred or earlier don't count. It reports
+// whether anything was dropped.
+func (l *Ledger) Delivered(note *Note) bool {
+	kept := l.Items[:0]
+	for _, s := range l.Items {
+		deferredOn, _ := time.Parse("2006-01-02", s.DeferredOn)
+		if !s.UntilDate().After(note.Date) && note.Date.After(deferredOn) && note.hasItem(s.Text) {
+			continue
+		}
+		kept = append(kept, s)
+	}
+	changed := len(kept) != len(l.Items)
+	l.Items = kept
+	return changed
+}
+
+// sort orders items by the date they come back
+func (l *Ledger) sort() {
+	sort.SliceStable(l.Items, func(i, j int) bool {
+		return l.Items[i].Until < l.Items[j].Until
+	})
+}
+
+// hasItem reports whether the note has a pending or completed item with the
+// given title. Items left behind marked as deferred don't count.
+func (n *Note) hasItem(text string) bool {
+	title := WorkItem{Text: text}.Title()
+	for _, list := range [][]WorkItem{n.PendingWork, n.CompletedWork} {
+		for _, item := range list {
+			if !item.Deferred() && strings.EqualFold(item.Title(), title) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package notes
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// wikiLinkRegex matches wiki-links and embeds such as [[target]],
+// [[target|alias]] and ![[target#heading]], capturing the target and the rest
+var wikiLinkRegex = regexp.MustCompile(`\[\[([^\[\]|#\n]+)([^\[\]\n]*)\]\]`)
+
+// LinkRenames maps the vault paths of renamed notes, without .md, to their new
+// paths, so wiki-links to them can be rewritten
+type LinkRenames struct {
+	byName map[string][][2]string // Old and new paths by the old file name
+}
+
+// NewLinkRenames returns an empty set of renames
+func NewLinkRenames() *LinkRenames {
+	return &LinkRenames{byName: make(map[string][][2]string)}
+}
+
+// Add records that the note at oldPath, relative to the vault root, now lives at newPath
+func (r *LinkRenames) Add(oldPath, newPath string) {
+	oldPath = linkPath(oldPath)
+	newPath = linkPath(newPath)
+	if oldPath == newPath {
+		return
+	}
+	name := path.Base(oldPath)
+	r.byName[name] = append(r.byName[name], [2]string{oldPath, newPath})
+}
+
+// Empty reports whether no notes were renamed
+func (r *LinkRenames) Empty() bool {
+	return len(r.byName) == 0
+}
+
+// Rewrite points the wiki-links of a file at the renamed notes and returns the
+// new content and the number of links changed. A link by file name is
+// rewritten to the new file name, a link by path to the new path with as many
+// folders. Links that could mean more than one renamed note are left alone.
+func (r *LinkRenames) Rewrite(content string) (string, int) {
+	changed := 0
+	rewritten := wikiLinkRegex.ReplaceAllStringFunc(content, func(link string) string {
+		match := wikiLinkRegex.FindStringSubmatch(link)
+		target, rest := match[1], match[2]
+
+		newTarget, ok := r.resolve(target)
+		if !ok {
+			return link
+		}
+		changed++
+		return "[[" + newTarget + rest + "]]"
+	})
+	return rewritten, changed
+}
+
+// resolve returns the new target of a link, if it points at a renamed note
+func (r *LinkRenames) resolve(target string) (string, bool) {
+	trimmed := strings.TrimSpace(target)
+	ext := ""
+	if strings.HasSuffix(trimmed, ".md") {
+		trimmed, ext = strings.TrimSuffix(trimmed, ".md"), ".md"
+	}
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	result := ""
+	for _, rename := range r.byName[path.Base(trimmed)] {
+		oldPath, newPath := rename[0], rename[1]
+		if oldPath != trimmed && !strings.HasSuffix(oldPath, "/"+trimmed) {
+			continue
+		}
+		// Keep as many folders as the link had
+		segments := strings.Split(newPath, "/")
+		if keep := strings.Count(trimmed, "/") + 1; keep < len(segments) {
+			segments = segments[len(segments)-keep:]
+		}
+		candidate := strings.Join(segments, "/")
+		if result != "" && result != candidate {
+			return "", false
+		}
+		result = candidate
+	}
+
+	if result == "" || result == trimmed {
+		return "", false
+	}
+	return result + ext, true
+}
+
+// linkPath returns a vault path as used in links: slash-separated, without .md
+func linkPath(p string) string {
+	return strings.TrimSuffix(filepath.ToSlash(p), ".md")
+}
+
+// FindVaultRoot returns the Obsidian vault a folder belongs to, the closest
+// folder above it with a .obsidian folder, or the folder itself outside a vault
+func FindVaultRoot(dir string) string {
+	dir = filepath.Clean(dir)
+	for current := dir; ; current = filepath.Dir(current) {
+		if info, err := os.Stat(filepath.Join(current, ".obsidian")); err == nil && info.IsDir() {
+			return current
+		}
+		if filepath.Dir(current) == current {
+			return dir
+		}
+	}
+}
+
+// MarkdownFiles returns the markdown files below root, skipping hidden folders
+// such as .obsidian and .trash
+func MarkdownFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), ".md") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,147 @@
+package notes
+
+import (
+	"strings"
+)
+
+// Markers are the section headings and inline summary fields worklog reads and
+// writes. The first entry of each list is the canonical form used when writing;
+// any further entries are aliases that are also accepted when parsing.
+type Markers struct {
+	Pending          []string // Section headings, without the leading "## "
+	Completed        []string
+	Summary          []string // Inline field names, without the trailing "::"
+	YesterdaySummary []string
+}
+
+// DefaultMarkers are the English markers worklog has always used
+var DefaultMarkers = Markers{
+	Pending:          []string{"Pending Work"},
+	Completed:        []string{"Work Completed"},
+	Summary:          []string{"summary"},
+	YesterdaySummary: []string{"yesterday's summary"},
+}
+
+// LocaleMarkers are the bundled markers for each supported note language
+var LocaleMarkers = map[string]Markers{
+	"en": DefaultMarkers,
+	"de": {
+		Pending:          []string{"Offene Aufgaben"},
+		Completed:        []string{"Erledigte Aufgaben"},
+		Summary:          []string{"zusammenfassung"},
+		YesterdaySummary: []string{"zusammenfassung von gestern"},
+	},
+	"ja": {
+		Pending:          []string{"未完了の作業"},
+		Completed:        []string{"完了した作業"},
+		Summary:          []string{"要約"},
+		YesterdaySummary: []string{"昨日の要約"},
+	},
+}
+
+// MarkersForLocale returns the bundled markers for a locale such as "de" or "ja_JP",
+// falling back to English
+func MarkersForLocale(locale string) Markers {
+	if m, ok := LocaleMarkers[baseLocale(locale)]; ok {
+		return m
+	}
+	return DefaultMarkers
+}
+
+// SupportedLocale reports whether markers are bundled for a locale
+func SupportedLocale(locale string) bool {
+	_, ok := LocaleMarkers[baseLocale(locale)]
+	return ok
+}
+
+// WithAliases returns the markers with the entries of other appended as extra aliases
+func (m Markers) WithAliases(other Markers) Markers {
+	return Markers{
+		Pending:          appendUnique(m.Pending, other.Pending),
+		Completed:        appendUnique(m.Completed, other.Completed),
+		Summary:          appendUnique(m.Summary, other.Summary),
+		YesterdaySummary: appendUnique(m.YesterdaySummary, other.YesterdaySummary),
+	}
+}
+
+// PendingHeading returns the canonical pending section heading
+func (m Markers) PendingHeading() string {
+	return canonical(m.Pending, DefaultMarkers.Pending[0])
+}
+
+// CompletedHeading returns the canonical completed section heading
+func (m Markers) CompletedHeading() string {
+	return canonical(m.Completed, DefaultMarkers.Completed[0])
+}
+
+// SummaryField returns the canonical summary field name
+func (m Markers) SummaryField() string {
+	return canonical(m.Summary, DefaultMarkers.Summary[0])
+}
+
+// YesterdaySummaryField returns the canonical yesterday's summary field name
+func (m Markers) YesterdaySummaryField() string {
+	return canonical(m.YesterdaySummary, DefaultMarkers.YesterdaySummary[0])
+}
+
+// KindOf returns which kind of section a heading starts
+func (m Markers) KindOf(heading string) SectionKind {
+	for _, alias := range m.Pending {
+		if strings.HasPrefix(heading, alias) {
+			return SectionPending
+		}
+	}
+	for _, alias := range m.Completed {
+		if strings.HasPrefix(heading, alias) {
+			return SectionCompleted
+		}
+	}
+	return SectionOther
+}
+
+// matchField returns the value of an inline field line if its name is one of the aliases
+func matchField(line string, aliases []string) (string, bool) {
+	for _, alias := range aliases {
+		if strings.HasPrefix(line, alias+"::") {
+			return strings.TrimSpace(strings.TrimPrefix(line, alias+"::")), true
+		}
+	}
+	return "", false
+}
+
+// canonical returns the first non-empty entry of a marker list
+func canonical(list []string, fallback string) string {
+	for _, entry := range list {
+		if entry != "" {
+			return entry
+		}
+	}
+	return fallback
+}
+
+// appendUnique appends the entries of extra that aren't in list yet
+func appendUnique(list, extra []string) []string {
+	result := append([]string{}, list...)
+	for _, entry := range extra {
+		found := false
+		for _, existing := range result {
+			if existing == entry {
+				found = true
+				break
+			}
+		}
+		if !found && entry != "" {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// baseLocale reduces a locale such as "de_DE.UTF-8" or "ja-JP" to its language
+func baseLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "_-."); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
@@ -0,0 +1,107 @@
+package notes
+
+import (
+	"strings"
+)
+
+// Merge folds another note of the same day into this one, item by item.
+// Items already in the note are kept once; an item pending here but done in
+// the other note counts as done. Summaries, tags, frontmatter and other
+// sections the note doesn't have yet are added.
+func (n *Note) Merge(other *Note) {
+	for _, item := range other.CompletedWork {
+		if i := findItem(n.PendingWork, item.Title()); i >= 0 {
+			n.PendingWork = append(n.PendingWork[:i], n.PendingWork[i+1:]...)
+		}
+		if findItem(n.CompletedWork, item.Title()) < 0 {
+			n.CompletedWork = append(n.CompletedWork, item)
+		}
+	}
+	for _, item := range other.PendingWork {
+		if findItem(n.PendingWork, item.Title()) < 0 && findItem(n.CompletedWork, item.Title()) < 0 {
+			n.PendingWork = append(n.PendingWork, item)
+		}
+	}
+
+	n.Summary = joinSummaries(n.Summary, other.Summary)
+	n.YesterdaySummary = joinSummaries(n.YesterdaySummary, other.YesterdaySummary)
+
+	for _, tag := range other.Tags {
+		if !containsString(n.Tags, tag) {
+			n.Tags = append(n.Tags, tag)
+		}
+	}
+	for _, alias := range other.Aliases {
+		if !containsString(n.Aliases, alias) {
+			n.Aliases = append(n.Aliases, alias)
+		}
+	}
+	// Frontmatter keys come with the indented lines of their value
+	copying := false
+	for _, line := range other.Frontmatter {
+		if key, _, ok := strings.Cut(line, ":"); ok && !isIndented(line) && !strings.HasPrefix(line, "- ") {
+			copying = n.frontmatterLine(strings.TrimSpace(key)) < 0
+		}
+		if copying {
+			n.Frontmatter = append(n.Frontmatter, line)
+		}
+	}
+
+	if len(other.Preamble) > 0 && strings.Join(n.Preamble, "\n") != strings.Join(other.Preamble, "\n") {
+		if len(n.Preamble) > 0 {
+			n.Preamble = append(n.Preamble, "")
+		}
+		n.Preamble = append(n.Preamble, other.Preamble...)
+	}
+	for _, section := range other.Sections {
+		if section.Kind != SectionOther {
+			continue
+		}
+		if i := findSection(n.Sections, section.Heading); i >= 0 {
+			n.Sections[i].Lines = append(trimBlankLines(n.Sections[i].Lines), section.Lines...)
+		} else {
+			n.Sections = append(n.Sections, section)
+		}
+	}
+}
+
+// findItem returns the index of the item with the given title, or -1
+func findItem(items []WorkItem, title string) int {
+	for i, item := range items {
+		if strings.EqualFold(item.Title(), title) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findSection returns the index of the unmanaged section with the given heading, or -1
+func findSection(sections []Section, heading string) int {
+	for i, section := range sections {
+		if section.Kind == SectionOther && strings.EqualFold(section.Heading, heading) {
+			return i
+		}
+	}
+	return -1
+}
+
+// joinSummaries combines two summaries, keeping one if they are the same
+func joinSummaries(a, b string) string {
+	switch {
+	case b == "" || a == b:
+		return a
+	case a == "":
+		return b
+	}
+	return a + " " + b
+}
+
+// containsString reports whether a list holds a value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,8 @@
 package notes
 
 import (
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -8,15 +10,49 @@ import (
 type WorkItem struct {
 	Text      string
 	Completed bool
+	State     ItemState // Cancelled, deferred or blocked; StateNone for plain items
+	Children  []string  // Indented lines under the item (sub-items, notes), kept verbatim
+}
+
+// itemTagRegex matches inline #tags in item text
+var itemTagRegex = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_/-]+)`)
+
+// Tags returns the inline #tags of an item, lowercased and without the #
+func (w WorkItem) Tags() []string {
+	var tags []string
+	for _, match := range itemTagRegex.FindAllStringSubmatch(w.Text, -1) {
+		tags = append(tags, ToLowerCase(match[1]))
+	}
+	return tags
+}
+
+// SectionKind identifies what a note section holds
+type SectionKind int
+
+const (
+	// SectionOther is a section worklog doesn't manage, such as "Meetings"
+	SectionOther SectionKind = iota
+	// SectionPending holds the pending work items
+	SectionPending
+	// SectionCompleted holds the completed work items
+	SectionCompleted
+)
+
+// Section is a "## " section of a note body
+type Section struct {
+	Kind    SectionKind
+	Heading string   // Heading text without the leading "## "
+	Lines   []string // Raw content, only kept for SectionOther
 }
 
 // Note represents a daily work note
 type Note struct {
 	// Frontmatter fields
-	ID      string
-	Aliases []string
-	Tags    []string
-	Date    time.Time
+	ID          string
+	Aliases     []string
+	Tags        []string
+	Date        time.Time
+	Frontmatter []string // Other frontmatter lines, kept verbatim
 
 	// Content fields
 	Title            string
@@ -25,6 +61,11 @@ type Note struct {
 	PendingWork      []WorkItem
 	CompletedWork    []WorkItem
 
+	// Free text after the title and the note's sections in file order.
+	// A note without sections gets the standard pending/completed layout.
+	Preamble []string
+	Sections []Section
+
 	// File info
 	FilePath string
 }
@@ -49,6 +90,11 @@ func generateID(date time.Time, workplaceName string) string {
 	return workplaceName + "-" + date.Format("2-Jan-2006")
 }
 
+// GenerateID creates the note ID for a date and workplace (exported for use by other packages)
+func GenerateID(date time.Time, workplaceName string) string {
+	return generateID(date, workplaceName)
+}
+
 // ToLowerCase converts a string to lowercase (exported for use by other packages)
 func ToLowerCase(s string) string {
 	result := make([]byte, len(s))
@@ -68,9 +114,9 @@ func toLowerCase(s string) string {
 	return ToLowerCase(s)
 }
 
-// GenerateFilename creates the filename for a note: YYYY-MM-DD-WorkplaceName.md
+// GenerateFilename creates the filename for a note using the default layout: YYYY-MM-DD-WorkplaceName.md
 func GenerateFilename(date time.Time, workplaceName string) string {
-	return date.Format("2006-01-02") + "-" + workplaceName + ".md"
+	return DefaultLayout.Path(date, workplaceName)
 }
 
 // HasPendingWork returns true if the note has any pending work items
@@ -98,12 +144,24 @@ func (n *Note) MarkItemCompleted(index int) {
 	if index >= 0 && index < len(n.PendingWork) {
 		item := n.PendingWork[index]
 		item.Completed = true
+		item.State = StateNone
 		n.CompletedWork = append(n.CompletedWork, item)
 		// Remove from pending
 		n.PendingWork = append(n.PendingWork[:index], n.PendingWork[index+1:]...)
 	}
 }
 
+// MarkItemPending moves a completed item back to pending
+func (n *Note) MarkItemPending(index int) {
+	if index >= 0 && index < len(n.CompletedWork) {
+		item := n.CompletedWork[index]
+		item.Completed = false
+		item.State = StateNone
+		n.PendingWork = append(n.PendingWork, item)
+		n.CompletedWork = append(n.CompletedWork[:index], n.CompletedWork[index+1:]...)
+	}
+}
+
 // RemovePendingItem removes a pending item at the given index
 func (n *Note) RemovePendingItem(index int) {
 	if index >= 0 && index < len(n.PendingWork) {
@@ -117,3 +175,37 @@ func (n *Note) RemoveCompletedItem(index int) {
 		n.CompletedWork = append(n.CompletedWork[:index], n.CompletedWork[index+1:]...)
 	}
 }
+
+// FrontmatterValue returns the value of a top-level frontmatter key that
+// worklog doesn't manage itself, such as "pomodoros: 3"
+func (n *Note) FrontmatterValue(key string) (string, bool) {
+	if i := n.frontmatterLine(key); i >= 0 {
+		value := strings.TrimSpace(strings.SplitN(n.Frontmatter[i], ":", 2)[1])
+		return strings.Trim(value, `"'`), true
+	}
+	return "", false
+}
+
+// SetFrontmatterValue sets a top-level frontmatter key to a scalar value,
+// adding it after the other keys if it's missing
+func (n *Note) SetFrontmatterValue(key, value string) {
+	line := key + ": " + value
+	if i := n.frontmatterLine(key); i >= 0 {
+		n.Frontmatter[i] = line
+		return
+	}
+	n.Frontmatter = append(n.Frontmatter, line)
+}
+
+// frontmatterLine returns the index of the top-level line of a frontmatter key, or -1
+func (n *Note) frontmatterLine(key string) int {
+	for i, line := range n.Frontmatter {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "- ") {
+			continue
+		}
+		if k, _, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(k) == key {
+			return i
+		}
+	}
+	return -1
+}
@@ -2,11 +2,10 @@ package notes
 
 import (
 	"bufio"
-	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
-	"sort"
 	"strings"
 	"time"
 )
@@ -15,6 +14,8 @@ import (
 type Parser struct {
 	notesDir      string
 	workplaceName string
+	layout        Layout
+	markers       Markers
 }
 
 // NewParser creates a new note parser
@@ -22,9 +23,31 @@ func NewParser(notesDir, workplaceName string) *Parser {
 	return &Parser{
 		notesDir:      notesDir,
 		workplaceName: workplaceName,
+		layout:        DefaultLayout,
+		markers:       DefaultMarkers,
 	}
 }
 
+// SetLayout sets the path layout used to locate notes
+func (p *Parser) SetLayout(layout Layout) {
+	p.layout = layout
+}
+
+// SetMarkers sets the section headings and summary fields the parser recognises
+func (p *Parser) SetMarkers(markers Markers) {
+	p.markers = markers
+}
+
+// NotePath returns the path of the note for the given date
+func (p *Parser) NotePath(date time.Time) string {
+	return filepath.Join(p.notesDir, p.layout.Path(date, p.workplaceName))
+}
+
+// ListNotes returns all notes of the workplace, sorted by date ascending
+func (p *Parser) ListNotes() ([]NoteFile, error) {
+	return FindNoteFiles(p.notesDir, p.workplaceName, p.layout)
+}
+
 // ParseFile reads and parses a markdown note file
 func (p *Parser) ParseFile(filePath string) (*Note, error) {
 	file, err := os.Open(filePath)
@@ -33,177 +56,254 @@ func (p *Parser) ParseFile(filePath string) (*Note, error) {
 	}
 	defer file.Close()
 
+	note, err := parseNote(file, p.markers)
+	if err != nil {
+		return nil, err
+	}
+	note.FilePath = filePath
+
+	return note, nil
+}
+
+// parseNote parses markdown note content. Content worklog doesn't manage (extra
+// frontmatter keys, free text and other sections) is kept so it can be written back.
+func parseNote(r io.Reader, markers Markers) (*Note, error) {
 	note := &Note{
-		FilePath:      filePath,
 		Aliases:       []string{},
 		Tags:          []string{},
 		PendingWork:   []WorkItem{},
 		CompletedWork: []WorkItem{},
 	}
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
 	inFrontmatter := false
-	inPendingSection := false
-	inCompletedSection := false
+	frontmatterKey := ""
+	var current *Section
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		lineNum++
 
-		// Handle frontmatter
-		if line == "---" {
-			if !inFrontmatter {
-				inFrontmatter = true
-				continue
-			} else {
-				inFrontmatter = false
-				continue
-			}
+		// Frontmatter is only recognised at the very top of the file
+		if lineNum == 1 && line == "---" {
+			inFrontmatter = true
+			continue
 		}
 
 		if inFrontmatter {
-			p.parseFrontmatterLine(line, note)
+			if line == "---" {
+				inFrontmatter = false
+				continue
+			}
+			frontmatterKey = parseFrontmatterLine(line, frontmatterKey, note)
 			continue
 		}
 
 		// Handle title
-		if strings.HasPrefix(line, "# ") {
+		if note.Title == "" && current == nil && strings.HasPrefix(line, "# ") {
 			note.Title = strings.TrimPrefix(line, "# ")
 			continue
 		}
 
 		// Handle summary fields
-		if strings.HasPrefix(line, "summary::") {
-			note.Summary = strings.TrimSpace(strings.TrimPrefix(line, "summary::"))
+		if value, ok := matchField(line, markers.YesterdaySummary); ok {
+			note.YesterdaySummary = value
 			continue
 		}
 
-		if strings.HasPrefix(line, "yesterday's summary::") {
-			note.YesterdaySummary = strings.TrimSpace(strings.TrimPrefix(line, "yesterday's summary::"))
+		if value, ok := matchField(line, markers.Summary); ok {
+			note.Summary = value
 			continue
 		}
 
 		// Handle sections
-		if strings.HasPrefix(line, "## Pending Work") {
-			inPendingSection = true
-			inCompletedSection = false
+		if strings.HasPrefix(line, "## ") {
+			heading := strings.TrimSpace(strings.TrimPrefix(line, "## "))
+			note.Sections = append(note.Sections, Section{
+				Kind:    markers.KindOf(heading),
+				Heading: heading,
+			})
+			current = &note.Sections[len(note.Sections)-1]
 			continue
 		}
 
-		if strings.HasPrefix(line, "## Work Completed") {
-			inPendingSection = false
-			inCompletedSection = true
+		if current == nil {
+			note.Preamble = append(note.Preamble, line)
 			continue
 		}
 
-		// Handle work items
-		if inPendingSection {
-			if item := p.parseWorkItem(line); item != nil {
-				note.PendingWork = append(note.PendingWork, *item)
-			}
+		// Handle work items; indented lines belong to the item above
+		var items *[]WorkItem
+		switch current.Kind {
+		case SectionPending:
+			items = &note.PendingWork
+		case SectionCompleted:
+			items = &note.CompletedWork
+		default:
+			current.Lines = append(current.Lines, line)
+			continue
 		}
 
-		if inCompletedSection {
-			if item := p.parseWorkItem(line); item != nil {
-				note.CompletedWork = append(note.CompletedWork, *item)
-			}
+		if isIndented(line) && strings.TrimSpace(line) != "" && len(*items) > 0 {
+			last := &(*items)[len(*items)-1]
+			last.Children = append(last.Children, line)
+			continue
+		}
+		if item := parseWorkItem(line); item != nil {
+			*items = append(*items, *item)
 		}
 	}
 
+	note.Preamble = trimBlankLines(note.Preamble)
+	for i := range note.Sections {
+		note.Sections[i].Lines = trimBlankLines(note.Sections[i].Lines)
+	}
+
 	return note, scanner.Err()
 }
 
-// parseFrontmatterLine parses a single frontmatter line
-func (p *Parser) parseFrontmatterLine(line string, note *Note) {
-	if strings.HasPrefix(line, "id:") {
-		note.ID = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
-	} else if strings.HasPrefix(line, "date:") {
-		dateStr := strings.TrimSpace(strings.TrimPrefix(line, "date:"))
-		if t, err := time.Parse("2006-01-02", dateStr); err == nil {
-			note.Date = t
+// frontmatterToken is a frontmatter line split the way the parser reads it
+type frontmatterToken struct {
+	key    string // The key the line sets or belongs to, empty outside any key
+	value  string // The value after the colon, or the entry of a list item
+	nested bool   // An indented line or list item belonging to the key above
+	item   bool   // A "- " list item
+}
+
+// scanFrontmatterLine splits a frontmatter line, given the key the line above
+// belongs to
+func scanFrontmatterLine(line, currentKey string) frontmatterToken {
+	// Indented lines and list items belong to the previous key
+	if isIndented(line) || strings.HasPrefix(line, "- ") {
+		token := frontmatterToken{key: currentKey, nested: true}
+		if entry, ok := strings.CutPrefix(strings.TrimSpace(line), "- "); ok {
+			token.item, token.value = true, strings.TrimSpace(entry)
 		}
-	} else if strings.HasPrefix(line, "  - ") {
-		// This is a tag or alias item
-		tag := strings.TrimSpace(strings.TrimPrefix(line, "  - "))
-		note.Tags = append(note.Tags, tag)
+		return token
+	}
+
+	key, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return frontmatterToken{}
 	}
+	return frontmatterToken{key: strings.TrimSpace(key), value: strings.TrimSpace(value)}
 }
 
-// parseWorkItem parses a work item line (checkbox format)
-func (p *Parser) parseWorkItem(line string) *WorkItem {
-	line = strings.TrimSpace(line)
+// parseFrontmatterLine parses a single frontmatter line. It returns the key the
+// following indented lines belong to.
+func parseFrontmatterLine(line, currentKey string, note *Note) string {
+	token := scanFrontmatterLine(line, currentKey)
 
-	// Match unchecked: - [ ] task
-	if strings.HasPrefix(line, "- [ ] ") {
-		return &WorkItem{
-			Text:      strings.TrimPrefix(line, "- [ ] "),
-			Completed: false,
+	if token.nested {
+		switch token.key {
+		case "tags":
+			if token.item {
+				note.Tags = append(note.Tags, token.value)
+			}
+		case "aliases":
+			if token.item {
+				note.Aliases = append(note.Aliases, token.value)
+			}
+		case "id", "date":
+			// Managed scalars have no nested content
+		default:
+			note.Frontmatter = append(note.Frontmatter, line)
 		}
+		return token.key
 	}
 
-	// Match checked: - [x] task
-	if strings.HasPrefix(line, "- [x] ") || strings.HasPrefix(line, "- [X] ") {
-		text := strings.TrimPrefix(line, "- [x] ")
-		text = strings.TrimPrefix(text, "- [X] ")
-		return &WorkItem{
-			Text:      text,
-			Completed: true,
+	switch token.key {
+	case "id":
+		note.ID = token.value
+	case "date":
+		if t, err := time.Parse("2006-01-02", token.value); err == nil {
+			note.Date = t
 		}
+	case "tags":
+		note.Tags = append(note.Tags, parseInlineList(token.value)...)
+	case "aliases":
+		note.Aliases = append(note.Aliases, parseInlineList(token.value)...)
+	default:
+		note.Frontmatter = append(note.Frontmatter, line)
 	}
 
-	return nil
+	return token.key
 }
 
-// FindMostRecentNote finds the most recent note before the given date
-func (p *Parser) FindMostRecentNote(beforeDate time.Time) (*Note, error) {
-	pattern := filepath.Join(p.notesDir, fmt.Sprintf("*-%s.md", p.workplaceName))
-	files, err := filepath.Glob(pattern)
-	if err != nil {
-		return nil, err
+// parseInlineList parses an inline YAML list such as "[a, b]" or a single value
+func parseInlineList(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "[]" {
+		return nil
 	}
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
 
-	if len(files) == 0 {
-		return nil, nil
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.Trim(strings.TrimSpace(item), `"'`)
+		if item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
+}
 
-	// Parse dates from filenames and sort
-	type fileDate struct {
-		path string
-		date time.Time
+// trimBlankLines removes leading and trailing blank lines
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
 	}
+	return lines
+}
 
-	var validFiles []fileDate
-	dateRegex := regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-.*\.md$`)
+// workItemRegex matches a checklist line such as "- [ ] task" or "- [-] task"
+var workItemRegex = regexp.MustCompile(`^- \[([^\]])\] (.*)$`)
 
-	for _, f := range files {
-		basename := filepath.Base(f)
-		matches := dateRegex.FindStringSubmatch(basename)
-		if len(matches) >= 2 {
-			if date, err := time.Parse("2006-01-02", matches[1]); err == nil {
-				// Only include dates before the target date
-				if date.Before(beforeDate) {
-					validFiles = append(validFiles, fileDate{path: f, date: date})
-				}
-			}
-		}
+// parseWorkItem parses a work item line (checkbox format). Besides "[ ]" and
+// "[x]", any single checkbox character is kept as the item's state.
+func parseWorkItem(line string) *WorkItem {
+	line = strings.TrimSpace(line)
+
+	match := workItemRegex.FindStringSubmatch(line)
+	if match == nil {
+		return nil
 	}
 
-	if len(validFiles) == 0 {
-		return nil, nil
+	item := &WorkItem{Text: match[2]}
+	switch box := []rune(match[1])[0]; box {
+	case ' ':
+	case 'x', 'X':
+		item.Completed = true
+	default:
+		item.State = ItemState(box)
 	}
+	return item
+}
 
-	// Sort by date descending (most recent first)
-	sort.Slice(validFiles, func(i, j int) bool {
-		return validFiles[i].date.After(validFiles[j].date)
-	})
+// FindMostRecentNote finds the most recent note before the given date
+func (p *Parser) FindMostRecentNote(beforeDate time.Time) (*Note, error) {
+	files, err := p.ListNotes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk backwards from the most recent note
+	for i := len(files) - 1; i >= 0; i-- {
+		if files[i].Date.Before(beforeDate) {
+			return p.ParseFile(files[i].Path)
+		}
+	}
 
-	// Return the most recent note
-	return p.ParseFile(validFiles[0].path)
+	return nil, nil
 }
 
 // FindTodayNote finds today's note if it exists
 func (p *Parser) FindTodayNote(date time.Time) (*Note, error) {
-	filename := GenerateFilename(date, p.workplaceName)
-	filePath := filepath.Join(p.notesDir, filename)
+	filePath := p.NotePath(date)
 
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return nil, nil
@@ -214,8 +314,6 @@ func (p *Parser) FindTodayNote(date time.Time) (*Note, error) {
 
 // NoteExists checks if a note exists for the given date
 func (p *Parser) NoteExists(date time.Time) bool {
-	filename := GenerateFilename(date, p.workplaceName)
-	filePath := filepath.Join(p.notesDir, filename)
-	_, err := os.Stat(filePath)
+	_, err := os.Stat(p.NotePath(date))
 	return err == nil
 }
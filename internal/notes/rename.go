@@ -0,0 +1,100 @@
+package notes
+
+import (
+	"slices"
+	"strings"
+)
+
+// RenameWorkplace points a note's id and workplace tag at another workplace
+func (n *Note) RenameWorkplace(oldName, newName string) {
+	n.ID = renameID(n.ID, oldName, newName)
+
+	var tags []string
+	for _, tag := range n.Tags {
+		tag = renameTag(tag, oldName, newName)
+		if !containsString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	n.Tags = tags
+}
+
+// RenameWorkplaceContent points the id and workplace tag in the frontmatter of
+// a note's content at another workplace. Only those lines change; the rest of
+// the note, task text included, is kept as it is.
+func RenameWorkplaceContent(content, oldName, newName string) string {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) == 0 || strings.TrimRight(lines[0], "\r\n") != "---" {
+		return content
+	}
+
+	var out []string
+	out = append(out, lines[0])
+	key := ""
+	var tags []string
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		text := strings.TrimRight(line, "\r\n")
+		eol := line[len(text):]
+		if text == "---" {
+			out = append(out, lines[i:]...)
+			break
+		}
+
+		token := scanFrontmatterLine(text, key)
+		key = token.key
+		switch {
+		case token.key == "tags" && token.item:
+			renamed := renameTag(token.value, oldName, newName)
+			if containsString(tags, renamed) {
+				continue // Already tagged
+			}
+			tags = append(tags, renamed)
+			line = replaceValue(text, token.value, renamed) + eol
+		case token.key == "tags" && !token.nested:
+			inline := parseInlineList(token.value)
+			var renamed []string
+			for _, tag := range inline {
+				tag = renameTag(tag, oldName, newName)
+				if !containsString(renamed, tag) {
+					renamed = append(renamed, tag)
+				}
+			}
+			if !slices.Equal(renamed, inline) {
+				line = replaceValue(text, token.value, "["+strings.Join(renamed, ", ")+"]") + eol
+			}
+		case token.key == "id" && !token.nested:
+			line = replaceValue(text, token.value, renameID(token.value, oldName, newName)) + eol
+		}
+		out = append(out, line)
+	}
+
+	return strings.Join(out, "")
+}
+
+// replaceValue replaces the value at the end of a frontmatter line, keeping the
+// key and indentation in front of it
+func replaceValue(line, value, replacement string) string {
+	if value == replacement {
+		return line
+	}
+	at := strings.LastIndex(line, value)
+	return line[:at] + replacement + line[at+len(value):]
+}
+
+// renameID returns the id of a note moved to another workplace, such as
+// Acme-1-Mar-2026 becoming Beta-1-Mar-2026
+func renameID(id, oldName, newName string) string {
+	if strings.HasPrefix(id, oldName+"-") {
+		return newName + strings.TrimPrefix(id, oldName)
+	}
+	return id
+}
+
+// renameTag returns a tag with the workplace tag replaced
+func renameTag(tag, oldName, newName string) string {
+	if tag == toLowerCase(oldName) {
+		return toLowerCase(newName)
+	}
+	return tag
+}
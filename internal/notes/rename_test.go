@@ -0,0 +1,98 @@
+package notes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenameWorkplaceContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "id",
+			content: "---\nid: Acme-18-Oct-2026\ndate: 2026-10-18\n---\n",
+			want:    "---\nid: Beta-18-Oct-2026\ndate: 2026-10-18\n---\n",
+		},
+		{
+			name:    "id of another workplace",
+			content: "---\nid: Acmecorp-18-Oct-2026\n---\n",
+			want:    "---\nid: Acmecorp-18-Oct-2026\n---\n",
+		},
+		{
+			name:    "block list tags",
+			content: "---\ntags:\n  - daily\n  - acme\n  - acmecorp\n---\n",
+			want:    "---\ntags:\n  - daily\n  - beta\n  - acmecorp\n---\n",
+		},
+		{
+			name:    "unindented block list tags",
+			content: "---\ntags:\n- acme\n- daily\n---\n",
+			want:    "---\ntags:\n- beta\n- daily\n---\n",
+		},
+		{
+			name:    "block list tag already there",
+			content: "---\ntags:\n  - acme\n  - beta\n---\n",
+			want:    "---\ntags:\n  - beta\n---\n",
+		},
+		{
+			name:    "inline tags",
+			content: "---\ntags: [daily, acme]\n---\n",
+			want:    "---\ntags: [daily, beta]\n---\n",
+		},
+		{
+			name:    "inline tag already there",
+			content: "---\ntags: [acme, beta]\n---\n",
+			want:    "---\ntags: [beta]\n---\n",
+		},
+		{
+			name:    "other keys",
+			content: "---\nproject: acme\naliases:\n  - acme\n---\n",
+			want:    "---\nproject: acme\naliases:\n  - acme\n---\n",
+		},
+		{
+			name:    "body left alone",
+			content: "---\nid: Acme-18-Oct-2026\n---\n\n# Acme\n\n## Pending Work\n\n- [ ] Acme-18-Oct-2026 follow-up\n- [ ] tags: acme\n  - acme\n",
+			want:    "---\nid: Beta-18-Oct-2026\n---\n\n# Acme\n\n## Pending Work\n\n- [ ] Acme-18-Oct-2026 follow-up\n- [ ] tags: acme\n  - acme\n",
+		},
+		{
+			name:    "CRLF line endings",
+			content: "---\r\nid: Acme-18-Oct-2026\r\ntags:\r\n  - acme\r\n---\r\n",
+			want:    "---\r\nid: Beta-18-Oct-2026\r\ntags:\r\n  - beta\r\n---\r\n",
+		},
+		{
+			name:    "no frontmatter",
+			content: "# Acme\n\nid: Acme-18-Oct-2026\n",
+			want:    "# Acme\n\nid: Acme-18-Oct-2026\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenameWorkplaceContent(tt.content, "Acme", "Beta"); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// The rewritten frontmatter should read back the same as a renamed note
+func TestRenameWorkplaceContentMatchesNote(t *testing.T) {
+	content := "---\nid: Acme-18-Oct-2026\ndate: 2026-10-18\ntags:\n  - daily\n  - acme\naliases: [Acme]\n---\n\n# Acme\n"
+	note, err := parseNote(strings.NewReader(content), DefaultMarkers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	note.RenameWorkplace("Acme", "Beta")
+
+	renamed, err := parseNote(strings.NewReader(RenameWorkplaceContent(content, "Acme", "Beta")), DefaultMarkers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.ID != note.ID || !reflect.DeepEqual(renamed.Tags, note.Tags) || !reflect.DeepEqual(renamed.Aliases, note.Aliases) {
+		t.Errorf("got id %q tags %q aliases %q, want id %q tags %q aliases %q",
+			renamed.ID, renamed.Tags, renamed.Aliases, note.ID, note.Tags, note.Aliases)
+	}
+}
@@ -0,0 +1,137 @@
+package notes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ItemState is the checkbox character of an item that isn't simply open or
+// done. The characters follow the Obsidian Tasks conventions.
+type ItemState rune
+
+const (
+	// StateNone is a plain "[ ]" or "[x]" item
+	StateNone ItemState = 0
+	// StateCancelled is dropped work, written "[-]"
+	StateCancelled ItemState = '-'
+	// StateDeferred is work parked until later, written "[>]"
+	StateDeferred ItemState = '>'
+	// StateWaiting is work waiting on someone or an answer, written "[?]"
+	StateWaiting ItemState = '?'
+	// StateBlocked is work that can't go on, written "[!]"
+	StateBlocked ItemState = '!'
+)
+
+// ReasonField is the inline field holding why an item is blocked or cancelled
+const ReasonField = "reason"
+
+// stateNames maps the names used on the command line to states
+var stateNames = map[string]ItemState{
+	"open":      StateNone,
+	"cancelled": StateCancelled,
+	"deferred":  StateDeferred,
+	"waiting":   StateWaiting,
+	"blocked":   StateBlocked,
+}
+
+// ParseState returns the state with the given name
+func ParseState(name string) (ItemState, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "canceled" {
+		name = "cancelled"
+	}
+	if state, ok := stateNames[name]; ok {
+		return state, nil
+	}
+	return StateNone, fmt.Errorf("unknown state %q (use open, blocked, waiting, cancelled or deferred)", name)
+}
+
+// String returns the name of the state
+func (s ItemState) String() string {
+	for name, state := range stateNames {
+		if state == s {
+			return name
+		}
+	}
+	return fmt.Sprintf("[%c]", s)
+}
+
+// Box returns the markdown checkbox of an item
+func (w WorkItem) Box() string {
+	switch {
+	case w.State != StateNone:
+		return fmt.Sprintf("[%c]", w.State)
+	case w.Completed:
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+// Done reports whether the item was completed
+func (w WorkItem) Done() bool {
+	return w.Completed && w.State == StateNone
+}
+
+// Cancelled reports whether the item was dropped
+func (w WorkItem) Cancelled() bool {
+	return w.State == StateCancelled
+}
+
+// Deferred reports whether the item was parked until later
+func (w WorkItem) Deferred() bool {
+	return w.State == StateDeferred
+}
+
+// Blocked reports whether the item is blocked or waiting
+func (w WorkItem) Blocked() bool {
+	return w.State == StateBlocked || w.State == StateWaiting
+}
+
+// Active reports whether the item still needs doing: open or blocked, but not
+// done, cancelled or deferred. Checkbox characters worklog doesn't know, such
+// as Obsidian's "[/]" for in progress, count as active.
+func (w WorkItem) Active() bool {
+	return !w.Completed && !w.Cancelled() && !w.Deferred()
+}
+
+// Reason returns why the item is blocked or cancelled, if given
+func (w WorkItem) Reason() string {
+	reason, _ := w.Field(ReasonField)
+	return reason
+}
+
+// SetState changes the state of an item. Setting any state reopens a
+// completed item. A reason is stored as an inline field; an empty reason
+// removes it.
+func (w *WorkItem) SetState(state ItemState, reason string) {
+	w.State = state
+	w.Completed = false
+	if reason == "" {
+		w.RemoveField(ReasonField)
+	} else {
+		w.SetField(ReasonField, reason)
+	}
+}
+
+// ActivePending returns the indices of the pending items that still need doing
+func (n *Note) ActivePending() []int {
+	var indices []int
+	for i, item := range n.PendingWork {
+		if item.Active() {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// DoneWork returns the completed items, leaving out any cancelled or otherwise
+// marked items kept in the completed section
+func (n *Note) DoneWork() []WorkItem {
+	var done []WorkItem
+	for _, item := range n.CompletedWork {
+		if item.Done() {
+			done = append(done, item)
+		}
+	}
+	return done
+}
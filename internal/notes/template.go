@@ -0,0 +1,169 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templaterRegex matches Templater-style placeholders with a format, e.g. {{date:YYYY-MM-DD}}
+var templaterRegex = regexp.MustCompile(`\{\{\s*(date|time|yesterday|tomorrow)\s*:\s*([^}]*?)\s*\}\}`)
+
+// momentTokens lists the moment.js-style format tokens, longest first
+var momentTokens = []struct {
+	token  string
+	layout string
+}{
+	{"YYYY", "2006"},
+	{"YY", "06"},
+	{"MMMM", "January"},
+	{"MMM", "Jan"},
+	{"MM", "01"},
+	{"M", "1"},
+	{"dddd", "Monday"},
+	{"ddd", "Mon"},
+	{"DD", "02"},
+	{"D", "2"},
+	{"HH", "15"},
+	{"hh", "03"},
+	{"h", "3"},
+	{"mm", "04"},
+	{"ss", "05"},
+	{"A", "PM"},
+	{"a", "pm"},
+}
+
+// TemplateData is the data available to note templates
+type TemplateData struct {
+	Date      time.Time
+	Workplace string
+	ID        string
+	Title     string
+	Tags      []string
+}
+
+// Template renders the initial content of new daily notes.
+//
+// Templates are Go text/template files. Obsidian Templater-style placeholders
+// are also understood:
+//
+//	{{date}} {{date:YYYY-MM-DD}} {{time}} {{time:HH:mm}}
+//	{{yesterday}} {{tomorrow:dddd}} {{title}} {{workplace}}
+//
+// as well as Go template fields such as {{.Date.Format "2006-01-02"}},
+// {{.Workplace}}, {{.ID}}, {{.Title}} and {{.Tags}}.
+type Template struct {
+	tmpl *template.Template
+}
+
+// ParseTemplate parses note template text
+func ParseTemplate(name, text string) (*Template, error) {
+	// Rewrite {{date:FORMAT}} into a function call Go templates understand
+	text = templaterRegex.ReplaceAllStringFunc(text, func(match string) string {
+		parts := templaterRegex.FindStringSubmatch(match)
+		return fmt.Sprintf("{{%s %q}}", parts[1], parts[2])
+	})
+
+	// Functions are bound per render; these placeholders only let the text parse
+	tmpl, err := template.New(name).Funcs(templateFuncs(TemplateData{})).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid note template: %w", err)
+	}
+
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Render renders the template for a workplace's note on the given date
+func (t *Template) Render(date time.Time, workplaceName string) (string, error) {
+	defaults := NewNote(date, workplaceName)
+	data := TemplateData{
+		Date:      date,
+		Workplace: workplaceName,
+		ID:        defaults.ID,
+		Title:     defaults.Title,
+		Tags:      defaults.Tags,
+	}
+
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Funcs(templateFuncs(data)).Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("error rendering note template: %w", err)
+	}
+
+	return sb.String(), nil
+}
+
+// templateFuncs returns the Templater-style helper functions for a note
+func templateFuncs(data TemplateData) template.FuncMap {
+	now := time.Now()
+	at := func(date time.Time, defaultFormat string) func(...string) string {
+		return func(format ...string) string {
+			if len(format) > 0 && format[0] != "" {
+				return FormatMoment(date, format[0])
+			}
+			return FormatMoment(date, defaultFormat)
+		}
+	}
+
+	return template.FuncMap{
+		"date":      at(data.Date, "YYYY-MM-DD"),
+		"yesterday": at(data.Date.AddDate(0, 0, -1), "YYYY-MM-DD"),
+		"tomorrow":  at(data.Date.AddDate(0, 0, 1), "YYYY-MM-DD"),
+		"time":      at(now, "HH:mm"),
+		"title":     func() string { return data.Title },
+		"workplace": func() string { return data.Workplace },
+		"lower":     ToLowerCase,
+	}
+}
+
+// FormatMoment formats a time using moment.js-style tokens (YYYY-MM-DD, dddd, HH:mm, ...).
+// Text inside square brackets is kept literally.
+func FormatMoment(t time.Time, format string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(format); {
+		// [literal text]
+		if format[i] == '[' {
+			if end := strings.IndexByte(format[i:], ']'); end > 0 {
+				sb.WriteString(format[i+1 : i+end])
+				i += end + 1
+				continue
+			}
+		}
+
+		matched := false
+		for _, mt := range momentTokens {
+			if strings.HasPrefix(format[i:], mt.token) {
+				sb.WriteString(t.Format(mt.layout))
+				i += len(mt.token)
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		// ISO week tokens aren't supported by time.Format
+		if strings.HasPrefix(format[i:], "GGGG") || strings.HasPrefix(format[i:], "WW") {
+			token := "WW"
+			if format[i] == 'G' {
+				token = "GGGG"
+			}
+			sb.WriteString(formatLayoutToken(token, t, ""))
+			i += len(token)
+			continue
+		}
+
+		sb.WriteByte(format[i])
+		i++
+	}
+
+	return sb.String()
+}
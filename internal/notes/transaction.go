@@ -0,0 +1,274 @@
+package notes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FileMove describes moving a single file
+type FileMove struct {
+	From string
+	To   string
+}
+
+// FileWrite describes writing a file, replacing it if it exists
+type FileWrite struct {
+	Path string
+	Data []byte
+}
+
+// Transaction applies a batch of file operations, undoing everything already
+// done if any step fails
+type Transaction struct {
+	moves    []FileMove
+	writes   []FileWrite
+	removals []string
+}
+
+// NewTransaction creates an empty transaction
+func NewTransaction() *Transaction {
+	return &Transaction{}
+}
+
+// Move queues moving a file from one path to another
+func (t *Transaction) Move(from, to string) {
+	t.moves = append(t.moves, FileMove{From: from, To: to})
+}
+
+// Write queues writing a file. Writes happen after the moves, so a moved file
+// can be written at its new path.
+func (t *Transaction) Write(path string, data []byte) {
+	t.writes = append(t.writes, FileWrite{Path: path, Data: data})
+}
+
+// Remove queues deleting a file
+func (t *Transaction) Remove(path string) {
+	t.removals = append(t.removals, path)
+}
+
+// Moves returns the queued file moves
+func (t *Transaction) Moves() []FileMove {
+	return t.moves
+}
+
+// Writes returns the queued file writes
+func (t *Transaction) Writes() []FileWrite {
+	return t.writes
+}
+
+// Removals returns the queued file deletions
+func (t *Transaction) Removals() []string {
+	return t.removals
+}
+
+// Empty reports whether nothing is queued
+func (t *Transaction) Empty() bool {
+	return len(t.moves) == 0 && len(t.writes) == 0 && len(t.removals) == 0
+}
+
+// Validate checks that the queued operations can be applied without overwriting anything
+func (t *Transaction) Validate() error {
+	sources := make(map[string]bool)
+	for _, m := range t.moves {
+		sources[filepath.Clean(m.From)] = true
+	}
+
+	targets := make(map[string]string)
+	for _, m := range t.moves {
+		to := filepath.Clean(m.To)
+		if other, exists := targets[to]; exists {
+			return fmt.Errorf("both %s and %s would be moved to %s", other, m.From, m.To)
+		}
+		targets[to] = m.From
+
+		if _, err := os.Stat(m.From); err != nil {
+			return fmt.Errorf("cannot move %s: %w", m.From, err)
+		}
+		// Moving onto a file that is itself being moved away is fine
+		if _, err := os.Stat(m.To); err == nil && !sources[to] {
+			return fmt.Errorf("cannot move %s: %s already exists", m.From, m.To)
+		}
+	}
+
+	for _, w := range t.writes {
+		if sources[filepath.Clean(w.Path)] && targets[filepath.Clean(w.Path)] == "" {
+			return fmt.Errorf("cannot write %s: it is being moved away", w.Path)
+		}
+	}
+
+	written := make(map[string]bool)
+	for _, w := range t.writes {
+		written[filepath.Clean(w.Path)] = true
+	}
+	for _, path := range t.removals {
+		if sources[filepath.Clean(path)] || targets[filepath.Clean(path)] != "" {
+			return fmt.Errorf("cannot remove %s: it is being moved", path)
+		}
+		if written[filepath.Clean(path)] {
+			return fmt.Errorf("cannot remove %s: it is being written", path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("cannot remove %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// Commit applies all queued operations: moves, then writes, then removals. If
+// any step fails, the operations already applied are rolled back and the
+// original error is returned.
+func (t *Transaction) Commit() error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	var undo []func()
+	var created []string
+	rollback := func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+		removeEmptyDirs(created)
+	}
+	makeDir := func(dir string) error {
+		missing := missingDirs(dir)
+		if len(missing) == 0 {
+			return nil
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("error creating directory %s: %w", dir, err)
+		}
+		created = append(created, missing...)
+		return nil
+	}
+
+	// Move everything to temporary names first so that moves whose targets are
+	// other moves' sources (e.g. swapping layouts) cannot clobber each other
+	temps := make([]string, len(t.moves))
+	for i, m := range t.moves {
+		temp := fmt.Sprintf("%s.worklog-tmp-%d", m.From, i)
+		if err := os.Rename(m.From, temp); err != nil {
+			rollback()
+			return fmt.Errorf("error moving %s: %w", m.From, err)
+		}
+		temps[i] = temp
+		undo = append(undo, func() { os.Rename(temp, m.From) })
+	}
+
+	for i, m := range t.moves {
+		if err := makeDir(filepath.Dir(m.To)); err != nil {
+			rollback()
+			return err
+		}
+		if err := os.Rename(temps[i], m.To); err != nil {
+			rollback()
+			return fmt.Errorf("error moving %s to %s: %w", m.From, m.To, err)
+		}
+		undo = append(undo, func() { os.Rename(m.To, temps[i]) })
+	}
+
+	for _, w := range t.writes {
+		old, err := os.ReadFile(w.Path)
+		existed := err == nil
+		if err != nil && !os.IsNotExist(err) {
+			rollback()
+			return fmt.Errorf("error reading %s: %w", w.Path, err)
+		}
+		if err := makeDir(filepath.Dir(w.Path)); err != nil {
+			rollback()
+			return err
+		}
+		if err := writeFileAtomic(w.Path, w.Data); err != nil {
+			rollback()
+			return fmt.Errorf("error writing %s: %w", w.Path, err)
+		}
+		undo = append(undo, func() {
+			if existed {
+				writeFileAtomic(w.Path, old)
+			} else {
+				os.Remove(w.Path)
+			}
+		})
+	}
+
+	// Removed files are only set aside until everything else has succeeded
+	var removed []string
+	for i, path := range t.removals {
+		temp := fmt.Sprintf("%s.worklog-rm-%d", path, i)
+		if err := os.Rename(path, temp); err != nil {
+			rollback()
+			return fmt.Errorf("error removing %s: %w", path, err)
+		}
+		removed = append(removed, temp)
+		undo = append(undo, func() { os.Rename(temp, path) })
+	}
+	for _, temp := range removed {
+		os.Remove(temp)
+	}
+
+	return nil
+}
+
+// writeFileAtomic replaces a file with data in one step
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".worklog-new"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// PruneEmptyDirs removes the now-empty directories left behind by moved and
+// removed files, stopping at root
+func (t *Transaction) PruneEmptyDirs(root string) {
+	root = filepath.Clean(root)
+	var paths []string
+	for _, m := range t.moves {
+		paths = append(paths, m.From)
+	}
+	paths = append(paths, t.removals...)
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		for dir != root && len(dir) > len(root) {
+			if err := os.Remove(dir); err != nil {
+				break // Not empty (or not removable) - stop climbing
+			}
+			dir = filepath.Dir(dir)
+		}
+	}
+}
+
+// missingDirs returns dir and those of its ancestors that don't exist yet, deepest first
+func missingDirs(dir string) []string {
+	var missing []string
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		missing = append(missing, dir)
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return missing
+}
+
+// removeEmptyDirs removes directories created during a failed commit, deepest first
+// so that parents are only removed once their children are gone
+func removeEmptyDirs(dirs []string) {
+	sort.Slice(dirs, func(i, j int) bool {
+		return len(dirs[i]) > len(dirs[j])
+	})
+	for _, dir := range dirs {
+		os.Remove(dir)
+	}
+}
@@ -6,12 +6,20 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/sandepten/work-obsidian-noter/internal/recurring"
 )
 
 // Writer handles writing markdown notes to disk
 type Writer struct {
 	notesDir      string
 	workplaceName string
+	layout        Layout
+	markers       Markers
+	template      *Template
+	recurring     []recurring.Task
+	ledgerPath    string
+	tags          []string
 }
 
 // NewWriter creates a new note writer
@@ -19,24 +27,150 @@ func NewWriter(notesDir, workplaceName string) *Writer {
 	return &Writer{
 		notesDir:      notesDir,
 		workplaceName: workplaceName,
+		layout:        DefaultLayout,
+		markers:       DefaultMarkers,
 	}
 }
 
+// SetLayout sets the path layout used to place new notes
+func (w *Writer) SetLayout(layout Layout) {
+	w.layout = layout
+}
+
+// SetMarkers sets the section headings and summary fields the writer uses.
+// Notes are always written with the canonical form of each marker.
+func (w *Writer) SetMarkers(markers Markers) {
+	w.markers = markers
+}
+
+// SetTemplate sets the template new notes are rendered from. A nil template
+// uses the built-in layout.
+func (w *Writer) SetTemplate(template *Template) {
+	w.template = template
+}
+
+// SetTags sets the tags of new notes. No tags keeps the default of the
+// lowercased workplace name and "job".
+func (w *Writer) SetTags(tags []string) {
+	w.tags = tags
+}
+
+// SetRecurring sets the recurring tasks added to new notes on the days they're due
+func (w *Writer) SetRecurring(tasks []recurring.Task) {
+	w.recurring = tasks
+}
+
+// SetLedger sets the file deferred items are kept in until they are due
+func (w *Writer) SetLedger(path string) {
+	w.ledgerPath = path
+}
+
+// NotePath returns the path of the note for the given date
+func (w *Writer) NotePath(date time.Time) string {
+	return filepath.Join(w.notesDir, w.layout.Path(date, w.workplaceName))
+}
+
 // WriteNote writes a note to disk
 func (w *Writer) WriteNote(note *Note) error {
 	if note.FilePath == "" {
-		note.FilePath = filepath.Join(w.notesDir, GenerateFilename(note.Date, w.workplaceName))
+		note.FilePath = w.NotePath(note.Date)
+	}
+
+	// Nested layouts may need their year/month folders created first
+	if err := os.MkdirAll(filepath.Dir(note.FilePath), 0755); err != nil {
+		return err
 	}
 
 	content := w.generateMarkdown(note)
-	return os.WriteFile(note.FilePath, []byte(content), 0644)
+	if err := os.WriteFile(note.FilePath, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	// Deferred items that made it into the note are no longer scheduled
+	if w.ledgerPath == "" {
+		return nil
+	}
+	ledger, err := LoadLedger(w.ledgerPath)
+	if err != nil {
+		return fmt.Errorf("error reading scheduled items: %w", err)
+	}
+	if ledger.Delivered(note) {
+		if err := ledger.Save(); err != nil {
+			return fmt.Errorf("error saving scheduled items: %w", err)
+		}
+	}
+	return nil
+}
+
+// AddScheduledItems adds the deferred items due by the note's date to its
+// pending work. They leave the ledger once the note is written.
+func (w *Writer) AddScheduledItems(note *Note) error {
+	if w.ledgerPath == "" {
+		return nil
+	}
+	ledger, err := LoadLedger(w.ledgerPath)
+	if err != nil {
+		return fmt.Errorf("error reading scheduled items: %w", err)
+	}
+	for _, s := range ledger.Due(note.Date) {
+		if !note.hasItem(s.Text) {
+			note.PendingWork = append(note.PendingWork, s.Item())
+		}
+	}
+	return nil
 }
 
-// CreateTodayNote creates a new note for today
-func (w *Writer) CreateTodayNote(date time.Time) *Note {
+// CreateTodayNote creates a new note for today, rendered from the note template if one is set
+func (w *Writer) CreateTodayNote(date time.Time) (*Note, error) {
 	note := NewNote(date, w.workplaceName)
-	note.FilePath = filepath.Join(w.notesDir, GenerateFilename(date, w.workplaceName))
-	return note
+	if len(w.tags) > 0 {
+		note.Tags = w.tags
+	}
+
+	if w.template != nil {
+		content, err := w.template.Render(date, w.workplaceName)
+		if err != nil {
+			return nil, err
+		}
+
+		rendered, err := parseNote(strings.NewReader(content), w.markers)
+		if err != nil {
+			return nil, fmt.Errorf("error reading rendered template: %w", err)
+		}
+
+		// Fields the template leaves out keep their defaults
+		if rendered.ID == "" {
+			rendered.ID = note.ID
+		}
+		if len(rendered.Tags) == 0 {
+			rendered.Tags = note.Tags
+		}
+		if rendered.Date.IsZero() {
+			rendered.Date = note.Date
+		}
+		if rendered.Title == "" {
+			rendered.Title = note.Title
+		}
+		note = rendered
+	}
+
+	for _, text := range recurring.Due(w.recurring, date) {
+		if note.FindPendingItem(text) < 0 {
+			note.AddPendingItem(text)
+		}
+	}
+
+	if err := w.AddScheduledItems(note); err != nil {
+		return nil, err
+	}
+
+	note.FilePath = w.NotePath(date)
+	return note, nil
+}
+
+// Markdown returns the content a note is written with
+func (w *Writer) Markdown(note *Note) []byte {
+	return []byte(w.generateMarkdown(note))
 }
 
 // generateMarkdown generates the markdown content for a note
@@ -46,36 +180,89 @@ func (w *Writer) generateMarkdown(note *Note) string {
 	// Frontmatter
 	sb.WriteString("---\n")
 	sb.WriteString(fmt.Sprintf("id: %s\n", note.ID))
-	sb.WriteString("aliases: []\n")
+	if len(note.Aliases) == 0 {
+		sb.WriteString("aliases: []\n")
+	} else {
+		sb.WriteString("aliases:\n")
+		for _, alias := range note.Aliases {
+			sb.WriteString(fmt.Sprintf("  - %s\n", alias))
+		}
+	}
 	sb.WriteString("tags:\n")
 	for _, tag := range note.Tags {
 		sb.WriteString(fmt.Sprintf("  - %s\n", tag))
 	}
 	sb.WriteString(fmt.Sprintf("date: %s\n", note.Date.Format("2006-01-02")))
+	for _, line := range note.Frontmatter {
+		sb.WriteString(line + "\n")
+	}
 	sb.WriteString("---\n\n")
 
 	// Title
 	sb.WriteString(fmt.Sprintf("# %s\n\n", note.Title))
 
 	// Summary fields
-	sb.WriteString(fmt.Sprintf("summary::%s\n\n", formatInlineSummary(note.Summary)))
-	sb.WriteString(fmt.Sprintf("yesterday's summary::%s\n\n", formatInlineSummary(note.YesterdaySummary)))
+	sb.WriteString(fmt.Sprintf("%s::%s\n\n", w.markers.SummaryField(), formatInlineSummary(note.Summary)))
+	sb.WriteString(fmt.Sprintf("%s::%s\n\n", w.markers.YesterdaySummaryField(), formatInlineSummary(note.YesterdaySummary)))
+
+	// Free text between the title and the first section
+	if len(note.Preamble) > 0 {
+		sb.WriteString(strings.Join(note.Preamble, "\n"))
+		sb.WriteString("\n\n")
+	}
+
+	// Sections in their original order; pending and completed are always written
+	wrotePending, wroteCompleted := false, false
+	for _, section := range note.Sections {
+		switch section.Kind {
+		case SectionPending:
+			if !wrotePending {
+				w.writePendingSection(&sb, note)
+				wrotePending = true
+			}
+		case SectionCompleted:
+			if !wroteCompleted {
+				w.writeCompletedSection(&sb, note)
+				wroteCompleted = true
+			}
+		default:
+			sb.WriteString(fmt.Sprintf("## %s\n", section.Heading))
+			if len(section.Lines) > 0 {
+				sb.WriteString("\n")
+				sb.WriteString(strings.Join(section.Lines, "\n"))
+				sb.WriteString("\n")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	if !wrotePending {
+		w.writePendingSection(&sb, note)
+	}
+	if !wroteCompleted {
+		w.writeCompletedSection(&sb, note)
+	}
 
-	// Pending Work section
-	sb.WriteString("## Pending Work\n\n")
+	return sb.String()
+}
+
+// writePendingSection writes the pending work section
+func (w *Writer) writePendingSection(sb *strings.Builder, note *Note) {
+	sb.WriteString(fmt.Sprintf("## %s\n\n", w.markers.PendingHeading()))
 	for _, item := range note.PendingWork {
-		sb.WriteString(fmt.Sprintf("- [ ] %s\n", item.Text))
+		item.Completed = false
+		sb.WriteString(FormatItem(item))
 	}
 	sb.WriteString("\n")
+}
 
-	// Work Completed section
-	sb.WriteString("## Work Completed\n\n")
+// writeCompletedSection writes the completed work section
+func (w *Writer) writeCompletedSection(sb *strings.Builder, note *Note) {
+	sb.WriteString(fmt.Sprintf("## %s\n\n", w.markers.CompletedHeading()))
 	for _, item := range note.CompletedWork {
-		sb.WriteString(fmt.Sprintf("- [x] %s\n", item.Text))
+		item.Completed = true
+		sb.WriteString(FormatItem(item))
 	}
 	sb.WriteString("\n")
-
-	return sb.String()
 }
 
 // formatInlineSummary formats the summary for inline display
@@ -0,0 +1,141 @@
+package recurring
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cron is a five-field cron expression. Notes are daily, so the minute and
+// hour fields are checked for validity but don't affect when a task is due.
+type cron struct {
+	days     map[int]bool
+	months   map[int]bool
+	weekdays map[int]bool
+	anyDay   bool // Day of month field is *
+	anyWeek  bool // Day of week field is *
+}
+
+// monthNames and dayNames are the names cron accepts in place of numbers
+var (
+	monthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	dayNames = map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}
+)
+
+// parseCron parses an expression such as "0 9 * * 1-5"
+func parseCron(expr string) (Rule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected an RRULE, a shortcut such as @weekly, or five cron fields", expr)
+	}
+
+	if _, err := parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute %q: %w", fields[0], err)
+	}
+	if _, err := parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour %q: %w", fields[1], err)
+	}
+
+	c := &cron{anyDay: fields[2] == "*", anyWeek: fields[4] == "*"}
+	var err error
+	if c.days, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day of month %q: %w", fields[2], err)
+	}
+	if c.months, err = parseCronField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("invalid month %q: %w", fields[3], err)
+	}
+	if c.weekdays, err = parseCronField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("invalid day of week %q: %w", fields[4], err)
+	}
+	if c.weekdays[7] {
+		c.weekdays[0] = true // 7 is Sunday too
+	}
+
+	return c, nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges (1-5) and
+// steps (*/2, 1-15/3) into the set of matching values
+func parseCronField(field string, min, max int, names map[string]int) (map[int]bool, error) {
+	values := make(map[int]bool)
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+			step = n
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = cronValue(bounds[0], min, max, names); err != nil {
+				return nil, err
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = cronValue(bounds[1], min, max, names); err != nil {
+					return nil, err
+				}
+			} else if step > 1 {
+				hi = max // 5/10 means from 5 on, every 10
+			}
+			if hi < lo {
+				return nil, fmt.Errorf("range %q runs backwards", part)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			values[v] = true
+		}
+	}
+
+	return values, nil
+}
+
+// cronValue parses a single number or name within bounds
+func cronValue(s string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("%d is outside %d-%d", v, min, max)
+	}
+	return v, nil
+}
+
+// Due reports whether the expression matches a date. As in cron, when both the
+// day of month and day of week are restricted, matching either is enough.
+func (c *cron) Due(date time.Time) bool {
+	if !c.months[int(date.Month())] {
+		return false
+	}
+
+	dayMatch := c.days[date.Day()]
+	weekMatch := c.weekdays[int(date.Weekday())]
+
+	switch {
+	case c.anyDay && c.anyWeek:
+		return true
+	case c.anyDay:
+		return weekMatch
+	case c.anyWeek:
+		return dayMatch
+	}
+	return dayMatch || weekMatch
+}
@@ -0,0 +1,105 @@
+package recurring
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCronDates(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		from string
+		n    int
+		want []string
+	}{
+		{"weekdays", "0 9 * * 1-5", "2026-10-16", 3, []string{"2026-10-16", "2026-10-19", "2026-10-20"}},
+		{"weekday names", "0 9 * * MON,fri", "2026-10-18", 3, []string{"2026-10-19", "2026-10-23", "2026-10-26"}},
+		{"7 is Sunday", "0 0 * * 7", "2026-10-18", 2, []string{"2026-10-18", "2026-10-25"}},
+		{"day of month list", "0 0 1,15 * *", "2026-10-02", 3, []string{"2026-10-15", "2026-11-01", "2026-11-15"}},
+		{"day of month step", "0 0 */10 * *", "2026-10-02", 4, []string{"2026-10-11", "2026-10-21", "2026-10-31", "2026-11-01"}},
+		{"step from a value", "0 0 5/10 * *", "2026-10-01", 3, []string{"2026-10-05", "2026-10-15", "2026-10-25"}},
+		{"range with a step", "0 0 1-10/3 * *", "2026-10-01", 4, []string{"2026-10-01", "2026-10-04", "2026-10-07", "2026-10-10"}},
+		{"months", "0 0 1 JAN,jul *", "2026-02-01", 2, []string{"2026-07-01", "2027-01-01"}},
+		// With both day fields restricted, either one is enough
+		{"day of month or day of week", "0 9 1 * MON", "2026-10-01", 4, []string{"2026-10-01", "2026-10-05", "2026-10-12", "2026-10-19"}},
+		{"the 13th or a Friday", "0 0 13 * 5", "2026-11-01", 4, []string{"2026-11-06", "2026-11-13", "2026-11-20", "2026-11-27"}},
+		{"the 31st skips short months", "0 0 31 * *", "2026-04-01", 2, []string{"2026-05-31", "2026-07-31"}},
+		{"@weekdays", "@weekdays", "2026-10-17", 2, []string{"2026-10-19", "2026-10-20"}},
+		{"@weekly", "@weekly", "2026-10-18", 2, []string{"2026-10-19", "2026-10-26"}},
+		{"@monthly", "@monthly", "2026-10-18", 2, []string{"2026-11-01", "2026-12-01"}},
+		{"@yearly", "@Yearly", "2026-10-18", 1, []string{"2027-01-01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextDates(t, tt.expr, tt.from, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"0 9 * *", "five cron fields"},
+		{"60 9 * * *", "invalid minute"},
+		{"0 24 * * *", "invalid hour"},
+		{"0 0 0 * *", "invalid day of month"},
+		{"0 0 * 13 *", "invalid month"},
+		{"0 0 * * 8", "invalid day of week"},
+		{"0 0 * * FRI-MON", "runs backwards"},
+		{"0 0 */0 * *", "invalid step"},
+		{"0 0 * * funday", "is not a number"},
+		{"@hourly", "unknown schedule"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseRule(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseAndDue(t *testing.T) {
+	tasks, err := Parse(strings.NewReader(strings.Join([]string{
+		"# Recurring items",
+		"",
+		"@weekdays | Check the on-call dashboard",
+		"FREQ=MONTHLY;BYDAY=-1FR | Send the monthly report #reports",
+		"0 9 * * MON | Plan the week | with pipes",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 || tasks[0].Line != 3 || tasks[2].Text != "Plan the week | with pipes" {
+		t.Fatalf("unexpected tasks: %+v", tasks)
+	}
+
+	tests := []struct {
+		date string
+		want []string
+	}{
+		{"2026-10-30", []string{"Check the on-call dashboard", "Send the monthly report #reports"}},
+		{"2026-10-26", []string{"Check the on-call dashboard", "Plan the week | with pipes"}},
+		{"2026-10-25", nil},
+	}
+	for _, tt := range tests {
+		if got := Due(tasks, day(t, tt.date)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.date, got, tt.want)
+		}
+	}
+
+	for _, line := range []string{"@weekdays", "@weekdays |  ", "FREQ=WEEKLY | No day"} {
+		if _, err := Parse(strings.NewReader(line)); err == nil || !strings.Contains(err.Error(), "line 1") {
+			t.Errorf("%q: expected an error on line 1, got %v", line, err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package recurring
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Rule decides on which days a recurring task is due
+type Rule interface {
+	Due(date time.Time) bool
+}
+
+// Task is a recurring work item
+type Task struct {
+	Expr string // The schedule as written in the definitions file
+	Text string
+	Rule Rule
+	Line int
+}
+
+// Due reports whether the task is due on a date
+func (t Task) Due(date time.Time) bool {
+	return t.Rule.Due(date)
+}
+
+// Next returns the next n days on or after from that the task is due, looking
+// at most a year ahead
+func (t Task) Next(from time.Time, n int) []time.Time {
+	var dates []time.Time
+	for d := from; len(dates) < n && d.Before(from.AddDate(1, 0, 1)); d = d.AddDate(0, 0, 1) {
+		if t.Due(d) {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+// Load reads a definitions file. A missing file has no tasks.
+func Load(path string) ([]Task, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	tasks, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return tasks, nil
+}
+
+// Parse reads task definitions, one per line as "<schedule> | <item text>".
+// The schedule is an RRULE such as FREQ=WEEKLY;BYDAY=FR, a five-field cron
+// expression such as "0 9 * * 1-5", or a shortcut such as @weekdays. Blank
+// lines and lines starting with # are ignored.
+func Parse(r io.Reader) ([]Task, error) {
+	var tasks []Task
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "|", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("line %d: expected \"<schedule> | <item text>\"", lineNum)
+		}
+
+		expr := strings.TrimSpace(parts[0])
+		rule, err := ParseRule(expr)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		tasks = append(tasks, Task{
+			Expr: expr,
+			Text: strings.TrimSpace(parts[1]),
+			Rule: rule,
+			Line: lineNum,
+		})
+	}
+
+	return tasks, scanner.Err()
+}
+
+// ParseRule parses a schedule in RRULE, cron or shortcut form
+func ParseRule(expr string) (Rule, error) {
+	expr = strings.TrimSpace(expr)
+	upper := strings.ToUpper(expr)
+
+	switch {
+	case strings.HasPrefix(expr, "@"):
+		cron, ok := shortcuts[strings.ToLower(expr)]
+		if !ok {
+			return nil, fmt.Errorf("unknown schedule %q (use @daily, @weekdays, @weekly, @monthly or @yearly)", expr)
+		}
+		return parseCron(cron)
+	case strings.HasPrefix(upper, "RRULE:"):
+		return parseRRule(expr[len("RRULE:"):])
+	case strings.Contains(upper, "FREQ="):
+		return parseRRule(expr)
+	}
+	return parseCron(expr)
+}
+
+// shortcuts maps schedule shortcuts to cron expressions
+var shortcuts = map[string]string{
+	"@daily":    "0 0 * * *",
+	"@weekdays": "0 0 * * 1-5",
+	"@weekly":   "0 0 * * 1",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+// Due returns the texts of the tasks due on a date
+func Due(tasks []Task, date time.Time) []string {
+	var texts []string
+	for _, task := range tasks {
+		if task.Due(date) {
+			texts = append(texts, task.Text)
+		}
+	}
+	return texts
+}
@@ -0,0 +1,308 @@
+package recurring
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rrule is the subset of RFC 5545 recurrence rules that makes sense for daily
+// notes: FREQ, INTERVAL, BYDAY, BYMONTHDAY, BYMONTH, DTSTART, UNTIL and COUNT
+type rrule struct {
+	freq       string
+	interval   int
+	byDay      []weekdayNum
+	byMonthDay []int
+	byMonth    []time.Month
+	start      time.Time
+	until      time.Time
+	count      int
+}
+
+// weekdayNum is a BYDAY entry such as MO, 1MO or -1FR
+type weekdayNum struct {
+	weekday time.Weekday
+	n       int // 0 for every such weekday
+}
+
+// weekdayCodes maps RRULE weekday codes to weekdays
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// parseRRule parses a rule such as FREQ=WEEKLY;INTERVAL=2;BYDAY=MO;DTSTART=2025-01-06
+func parseRRule(expr string) (Rule, error) {
+	r := &rrule{interval: 1}
+
+	for _, part := range strings.Split(expr, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		key, value := strings.ToUpper(strings.TrimSpace(kv[0])), strings.TrimSpace(kv[1])
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.freq = strings.ToUpper(value)
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err == nil && r.interval < 1 {
+				err = fmt.Errorf("must be at least 1")
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, err := parseWeekdayNum(day)
+				if err != nil {
+					return nil, err
+				}
+				r.byDay = append(r.byDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(strings.TrimSpace(day))
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", day)
+				}
+				r.byMonthDay = append(r.byMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, month := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(strings.TrimSpace(month))
+				if err != nil || n < 1 || n > 12 {
+					return nil, fmt.Errorf("invalid BYMONTH %q", month)
+				}
+				r.byMonth = append(r.byMonth, time.Month(n))
+			}
+		case "DTSTART":
+			r.start, err = parseRuleDate(value)
+		case "UNTIL":
+			r.until, err = parseRuleDate(value)
+		case "COUNT":
+			r.count, err = strconv.Atoi(value)
+		default:
+			return nil, fmt.Errorf("unsupported rule part %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", key, value, err)
+		}
+	}
+
+	switch r.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	case "":
+		return nil, fmt.Errorf("rule has no FREQ")
+	default:
+		return nil, fmt.Errorf("unsupported FREQ %s (use DAILY, WEEKLY, MONTHLY or YEARLY)", r.freq)
+	}
+
+	if r.start.IsZero() {
+		if r.interval > 1 {
+			return nil, fmt.Errorf("INTERVAL needs a DTSTART to count from")
+		}
+		if r.count > 0 {
+			return nil, fmt.Errorf("COUNT needs a DTSTART to count from")
+		}
+		if r.freq == "WEEKLY" && len(r.byDay) == 0 {
+			return nil, fmt.Errorf("weekly rules need BYDAY or DTSTART")
+		}
+		if r.freq == "MONTHLY" && len(r.byDay) == 0 && len(r.byMonthDay) == 0 {
+			return nil, fmt.Errorf("monthly rules need BYDAY, BYMONTHDAY or DTSTART")
+		}
+		if r.freq == "YEARLY" && (len(r.byMonth) == 0 || len(r.byMonthDay) == 0) {
+			return nil, fmt.Errorf("yearly rules need BYMONTH and BYMONTHDAY, or DTSTART")
+		}
+	}
+
+	return r, nil
+}
+
+// parseWeekdayNum parses a BYDAY entry
+func parseWeekdayNum(value string) (weekdayNum, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return weekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+
+	wd, ok := weekdayCodes[value[len(value)-2:]]
+	if !ok {
+		return weekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+
+	n := 0
+	if prefix := value[:len(value)-2]; prefix != "" {
+		var err error
+		n, err = strconv.Atoi(strings.TrimPrefix(prefix, "+"))
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return weekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+		}
+	}
+	return weekdayNum{weekday: wd, n: n}, nil
+}
+
+// parseRuleDate parses YYYY-MM-DD or the RFC 5545 forms YYYYMMDD and YYYYMMDDTHHMMSSZ
+func parseRuleDate(value string) (time.Time, error) {
+	if len(value) > 8 && value[8] == 'T' {
+		value = value[:8]
+	}
+	for _, layout := range []string{"2006-01-02", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("use YYYY-MM-DD")
+}
+
+// Due reports whether the rule has an occurrence on a date
+func (r *rrule) Due(date time.Time) bool {
+	date = dayOf(date)
+	if !r.start.IsZero() && date.Before(r.start) {
+		return false
+	}
+	if !r.until.IsZero() && date.After(r.until) {
+		return false
+	}
+	if !r.matches(date) {
+		return false
+	}
+
+	if r.count > 0 {
+		// Count the occurrences from the start up to this date
+		n := 0
+		for d := r.start; !d.After(date); d = d.AddDate(0, 0, 1) {
+			if r.matches(d) {
+				n++
+			}
+			if n > r.count {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matches checks the frequency, interval and BY* filters, ignoring COUNT
+func (r *rrule) matches(date time.Time) bool {
+	if len(r.byMonth) > 0 && !containsMonth(r.byMonth, date.Month()) {
+		return false
+	}
+
+	switch r.freq {
+	case "DAILY":
+		if !r.start.IsZero() && daysBetween(r.start, date)%r.interval != 0 {
+			return false
+		}
+		return r.matchesByDay(date, false) && r.matchesByMonthDay(date)
+
+	case "WEEKLY":
+		if !r.start.IsZero() && daysBetween(weekStart(r.start), weekStart(date))/7%r.interval != 0 {
+			return false
+		}
+		if len(r.byDay) == 0 {
+			return date.Weekday() == r.start.Weekday()
+		}
+		return r.matchesByDay(date, false)
+
+	case "MONTHLY":
+		if !r.start.IsZero() && monthsBetween(r.start, date)%r.interval != 0 {
+			return false
+		}
+		if len(r.byDay) == 0 && len(r.byMonthDay) == 0 {
+			return date.Day() == r.start.Day()
+		}
+		return r.matchesByDay(date, true) && r.matchesByMonthDay(date)
+
+	case "YEARLY":
+		if !r.start.IsZero() && (date.Year()-r.start.Year())%r.interval != 0 {
+			return false
+		}
+		if len(r.byMonth) == 0 && date.Month() != r.start.Month() {
+			return false
+		}
+		if len(r.byDay) == 0 && len(r.byMonthDay) == 0 {
+			return date.Day() == r.start.Day()
+		}
+		return r.matchesByDay(date, true) && r.matchesByMonthDay(date)
+	}
+
+	return false
+}
+
+// matchesByDay checks BYDAY. Ordinals such as 1MO or -1FR only count within a
+// month, so they are ignored for daily and weekly rules.
+func (r *rrule) matchesByDay(date time.Time, ordinals bool) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+	for _, wd := range r.byDay {
+		if wd.weekday != date.Weekday() {
+			continue
+		}
+		if wd.n == 0 || !ordinals {
+			return true
+		}
+		if wd.n > 0 && (date.Day()-1)/7+1 == wd.n {
+			return true
+		}
+		daysInMonth := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		if wd.n < 0 && (daysInMonth-date.Day())/7+1 == -wd.n {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesByMonthDay checks BYMONTHDAY; negative days count from the month's end
+func (r *rrule) matchesByMonthDay(date time.Time) bool {
+	if len(r.byMonthDay) == 0 {
+		return true
+	}
+	daysInMonth := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, day := range r.byMonthDay {
+		if day == date.Day() || (day < 0 && daysInMonth+day+1 == date.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsMonth reports whether months includes m
+func containsMonth(months []time.Month, m time.Month) bool {
+	for _, month := range months {
+		if month == m {
+			return true
+		}
+	}
+	return false
+}
+
+// dayOf strips the time of day, keeping the calendar date
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween returns the number of days from a to b
+func daysBetween(a, b time.Time) int {
+	return int(dayOf(b).Sub(dayOf(a)).Hours() / 24)
+}
+
+// weekStart returns the Monday of the week containing t
+func weekStart(t time.Time) time.Time {
+	return dayOf(t).AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+}
+
+// monthsBetween returns the number of calendar months from a to b
+func monthsBetween(a, b time.Time) int {
+	return (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+}
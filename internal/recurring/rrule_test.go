@@ -0,0 +1,139 @@
+package recurring
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// day parses a YYYY-MM-DD date
+func day(t *testing.T, value string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// nextDates returns the next n days a schedule is due on or after from, as YYYY-MM-DD
+func nextDates(t *testing.T, expr, from string, n int) []string {
+	t.Helper()
+	rule, err := ParseRule(expr)
+	if err != nil {
+		t.Fatalf("%s: %v", expr, err)
+	}
+	var dates []string
+	for _, d := range (Task{Rule: rule}).Next(day(t, from), n) {
+		dates = append(dates, d.Format("2006-01-02"))
+	}
+	return dates
+}
+
+func TestRRuleDates(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		from string
+		n    int
+		want []string
+	}{
+		{"every other Monday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO;DTSTART=2026-01-05", "2026-01-01", 4,
+			[]string{"2026-01-05", "2026-01-19", "2026-02-02", "2026-02-16"}},
+		{"every other Monday from a Wednesday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO;DTSTART=2026-01-07", "2026-01-01", 3,
+			[]string{"2026-01-19", "2026-02-02", "2026-02-16"}},
+		{"every other week on the start's weekday", "FREQ=WEEKLY;INTERVAL=2;DTSTART=2026-10-14", "2026-10-01", 3,
+			[]string{"2026-10-14", "2026-10-28", "2026-11-11"}},
+		{"weekly on two days", "FREQ=WEEKLY;BYDAY=TU,TH", "2026-10-18", 4,
+			[]string{"2026-10-20", "2026-10-22", "2026-10-27", "2026-10-29"}},
+		{"every third day", "FREQ=DAILY;INTERVAL=3;DTSTART=2026-10-18", "2026-10-17", 3,
+			[]string{"2026-10-18", "2026-10-21", "2026-10-24"}},
+		{"last Friday of the month", "FREQ=MONTHLY;BYDAY=-1FR", "2026-01-01", 5,
+			[]string{"2026-01-30", "2026-02-27", "2026-03-27", "2026-04-24", "2026-05-29"}},
+		{"first Monday of the month", "FREQ=MONTHLY;BYDAY=1MO", "2026-01-01", 3,
+			[]string{"2026-01-05", "2026-02-02", "2026-03-02"}},
+		{"second to last Tuesday", "FREQ=MONTHLY;BYDAY=-2TU", "2026-09-01", 2,
+			[]string{"2026-09-22", "2026-10-20"}},
+		{"the 31st skips short months", "FREQ=MONTHLY;BYMONTHDAY=31", "2026-01-01", 4,
+			[]string{"2026-01-31", "2026-03-31", "2026-05-31", "2026-07-31"}},
+		{"monthly from the 31st skips short months", "FREQ=MONTHLY;DTSTART=2026-01-31", "2026-01-01", 3,
+			[]string{"2026-01-31", "2026-03-31", "2026-05-31"}},
+		{"last day of the month", "FREQ=MONTHLY;BYMONTHDAY=-1", "2026-01-01", 4,
+			[]string{"2026-01-31", "2026-02-28", "2026-03-31", "2026-04-30"}},
+		{"last day of February in a leap year", "FREQ=MONTHLY;BYMONTHDAY=-1;BYMONTH=2", "2027-06-01", 1,
+			[]string{"2028-02-29"}},
+		{"quarterly", "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=1;DTSTART=2026-01-01", "2026-01-01", 3,
+			[]string{"2026-01-01", "2026-04-01", "2026-07-01"}},
+		{"yearly from the start", "FREQ=YEARLY;DTSTART=2025-03-15", "2026-01-01", 1,
+			[]string{"2026-03-15"}},
+		{"yearly by month and day", "FREQ=YEARLY;BYMONTH=12;BYMONTHDAY=24", "2026-01-01", 1,
+			[]string{"2026-12-24"}},
+		{"count", "FREQ=DAILY;COUNT=3;DTSTART=2026-10-18", "2026-10-01", 10,
+			[]string{"2026-10-18", "2026-10-19", "2026-10-20"}},
+		{"count with several days a week", "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3;DTSTART=2026-10-19", "2026-10-01", 10,
+			[]string{"2026-10-19", "2026-10-21", "2026-10-26"}},
+		{"count from later on", "FREQ=WEEKLY;BYDAY=MO;COUNT=4;DTSTART=2026-10-05", "2026-10-20", 10,
+			[]string{"2026-10-26"}},
+		{"until", "FREQ=WEEKLY;BYDAY=FR;UNTIL=2026-10-30", "2026-10-18", 10,
+			[]string{"2026-10-23", "2026-10-30"}},
+		{"until in RFC 5545 form", "RRULE:FREQ=WEEKLY;BYDAY=FR;UNTIL=20261030T000000Z", "2026-10-18", 10,
+			[]string{"2026-10-23", "2026-10-30"}},
+		{"lowercase", "freq=weekly;byday=fr", "2026-10-18", 1,
+			[]string{"2026-10-23"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextDates(t, tt.expr, tt.from, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRRuleBeforeStart(t *testing.T) {
+	rule, err := ParseRule("FREQ=DAILY;DTSTART=2026-10-18")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.Due(day(t, "2026-10-17")) {
+		t.Error("a rule shouldn't be due before its DTSTART")
+	}
+	if !rule.Due(day(t, "2026-10-18").Add(15 * time.Hour)) {
+		t.Error("the time of day shouldn't matter")
+	}
+}
+
+func TestRRuleErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"RRULE:BYDAY=MO", "no FREQ"},
+		{"FREQ=HOURLY", "unsupported FREQ"},
+		{"FREQ=DAILY;INTERVAL=2", "INTERVAL needs a DTSTART"},
+		{"FREQ=DAILY;COUNT=5", "COUNT needs a DTSTART"},
+		{"FREQ=WEEKLY", "weekly rules need BYDAY or DTSTART"},
+		{"FREQ=MONTHLY", "monthly rules need BYDAY, BYMONTHDAY or DTSTART"},
+		{"FREQ=YEARLY;BYMONTH=3", "yearly rules need BYMONTH and BYMONTHDAY"},
+		{"FREQ=DAILY;INTERVAL=0;DTSTART=2026-10-18", "invalid INTERVAL"},
+		{"FREQ=WEEKLY;BYDAY=XX", "invalid BYDAY"},
+		{"FREQ=MONTHLY;BYDAY=6MO", "invalid BYDAY"},
+		{"FREQ=MONTHLY;BYMONTHDAY=0", "invalid BYMONTHDAY"},
+		{"FREQ=MONTHLY;BYMONTHDAY=32", "invalid BYMONTHDAY"},
+		{"FREQ=YEARLY;BYMONTH=13;BYMONTHDAY=1", "invalid BYMONTH"},
+		{"FREQ=DAILY;DTSTART=18.10.2026", "invalid DTSTART"},
+		{"FREQ=DAILY;WKST=SU", "unsupported rule part WKST"},
+		{"FREQ=DAILY;BYDAY", "invalid rule part"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseRule(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+)
+
+// Mode selects how the query is matched against item text
+type Mode string
+
+const (
+	// ModeSubstring matches the query anywhere in the text, ignoring case
+	ModeSubstring Mode = "substring"
+	// ModeRegex matches the query as a regular expression
+	ModeRegex Mode = "regex"
+	// ModeFuzzy matches the query's characters in order, allowing gaps
+	ModeFuzzy Mode = "fuzzy"
+)
+
+// Status filters items by the section they're in. Pending items can also be
+// filtered by their state, with a state name such as "blocked".
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+	StatusSummary   = "summary"
+)
+
+// Query describes what to search for
+type Query struct {
+	Text          string
+	Mode          Mode
+	CaseSensitive bool
+	From          time.Time // Inclusive, zero for no lower bound
+	To            time.Time // Inclusive, zero for no upper bound
+	Status        string    // StatusPending, StatusCompleted, StatusSummary, a state name or "" for all
+	Tags          []string  // Note tags or inline #tags, all of which must be present
+}
+
+// Source is a workplace's notes to search
+type Source struct {
+	Workplace string
+	Parser    *notes.Parser
+	Markers   notes.Markers
+}
+
+// Result is a single matching item
+type Result struct {
+	Workplace string    `json:"workplace"`
+	Date      time.Time `json:"date"`
+	File      string    `json:"file"`
+	Section   string    `json:"section"`
+	Status    string    `json:"status"`
+	State     string    `json:"state,omitempty"` // State of a pending item, such as "blocked"
+	Text      string    `json:"text"`
+	Context   []string  `json:"context,omitempty"`
+	Score     float64   `json:"score"`
+	Matches   [][2]int  `json:"matches,omitempty"` // Byte ranges of Text that matched
+}
+
+// matcher scores a piece of text, returning ok=false when it doesn't match
+type matcher func(text string) (score float64, matches [][2]int, ok bool)
+
+// Search runs a query across the notes of the given workplaces and returns the
+// results ranked by score, most recent first for equal scores
+func Search(sources []Source, query Query) ([]Result, error) {
+	match, err := newMatcher(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, source := range sources {
+		files, err := source.Parser.ListNotes()
+		if err != nil {
+			return nil, fmt.Errorf("error listing notes for %s: %w", source.Workplace, err)
+		}
+
+		for _, file := range files {
+			if !query.From.IsZero() && file.Date.Before(query.From) {
+				continue
+			}
+			if !query.To.IsZero() && file.Date.After(query.To) {
+				continue
+			}
+
+			note, err := source.Parser.ParseFile(file.Path)
+			if err != nil {
+				return nil, fmt.Errorf("error reading %s: %w", file.Path, err)
+			}
+
+			results = append(results, searchNote(note, file, source.Workplace, source.Markers, query, match)...)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Date.After(results[j].Date)
+	})
+
+	return results, nil
+}
+
+// searchNote finds matching items in a single note
+func searchNote(note *notes.Note, file notes.NoteFile, workplace string, markers notes.Markers, query Query, match matcher) []Result {
+	var results []Result
+
+	noteTags := make(map[string]bool)
+	for _, tag := range note.Tags {
+		noteTags[strings.ToLower(tag)] = true
+	}
+
+	add := func(section, status, state, text string, context []string, tags []string) {
+		if query.Status != "" && query.Status != status && query.Status != state {
+			return
+		}
+		if !hasTags(query.Tags, noteTags, tags) {
+			return
+		}
+
+		score, matches, ok := match(text)
+		if !ok {
+			return
+		}
+
+		results = append(results, Result{
+			Workplace: workplace,
+			Date:      file.Date,
+			File:      file.Path,
+			Section:   section,
+			Status:    status,
+			State:     state,
+			Text:      text,
+			Context:   context,
+			Score:     score,
+			Matches:   matches,
+		})
+	}
+
+	for i, item := range note.PendingWork {
+		add(markers.PendingHeading(), StatusPending, item.State.String(), item.Text, neighbours(note.PendingWork, i), item.Tags())
+	}
+	for i, item := range note.CompletedWork {
+		add(markers.CompletedHeading(), StatusCompleted, "", item.Text, neighbours(note.CompletedWork, i), item.Tags())
+	}
+	if note.Summary != "" {
+		add(markers.SummaryField(), StatusSummary, "", note.Summary, nil, nil)
+	}
+
+	return results
+}
+
+// neighbours returns the texts of the items around index, for context
+func neighbours(items []notes.WorkItem, index int) []string {
+	var context []string
+	if index > 0 {
+		context = append(context, items[index-1].Text)
+	}
+	if index < len(items)-1 {
+		context = append(context, items[index+1].Text)
+	}
+	return context
+}
+
+// hasTags reports whether every wanted tag is on the note or the item
+func hasTags(wanted []string, noteTags map[string]bool, itemTags []string) bool {
+	for _, tag := range wanted {
+		tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+		if noteTags[tag] {
+			continue
+		}
+		found := false
+		for _, t := range itemTags {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// newMatcher builds the matcher for a query
+func newMatcher(query Query) (matcher, error) {
+	text := query.Text
+	if text == "" {
+		// No query text: list everything that passes the filters
+		return func(string) (float64, [][2]int, bool) { return 0, nil, true }, nil
+	}
+
+	switch query.Mode {
+	case ModeRegex:
+		pattern := text
+		if !query.CaseSensitive {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		return func(s string) (float64, [][2]int, bool) {
+			locs := re.FindAllStringIndex(s, -1)
+			if locs == nil {
+				return 0, nil, false
+			}
+			return scoreRanges(s, toRanges(locs)), toRanges(locs), true
+		}, nil
+
+	case ModeFuzzy:
+		return func(s string) (float64, [][2]int, bool) {
+			return fuzzyMatch(s, text, query.CaseSensitive)
+		}, nil
+
+	case ModeSubstring, "":
+		needle, _ := foldRunes(text, query.CaseSensitive)
+		return func(s string) (float64, [][2]int, bool) {
+			haystack, offsets := foldRunes(s, query.CaseSensitive)
+			var ranges [][2]int
+			for i := 0; i+len(needle) <= len(haystack); {
+				if !hasRunesAt(haystack, needle, i) {
+					i++
+					continue
+				}
+				ranges = append(ranges, [2]int{offsets[i], offsets[i+len(needle)]})
+				i += len(needle)
+			}
+			if ranges == nil {
+				return 0, nil, false
+			}
+			return scoreRanges(s, ranges), ranges, true
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown search mode %q (use substring, regex or fuzzy)", query.Mode)
+}
+
+// foldRunes returns the runes of text, lowercased unless caseSensitive, and
+// the byte offset in text of each one followed by len(text). Folding rune by
+// rune keeps the positions of matches in step with the original text.
+func foldRunes(text string, caseSensitive bool) ([]rune, []int) {
+	runes := make([]rune, 0, len(text))
+	offsets := make([]int, 0, len(text)+1)
+	for i, r := range text {
+		if !caseSensitive {
+			r = unicode.ToLower(r)
+		}
+		runes = append(runes, r)
+		offsets = append(offsets, i)
+	}
+	return runes, append(offsets, len(text))
+}
+
+// hasRunesAt reports whether needle occurs in haystack at index i
+func hasRunesAt(haystack, needle []rune, i int) bool {
+	for j, r := range needle {
+		if haystack[i+j] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// toRanges converts regexp index pairs to ranges
+func toRanges(locs [][]int) [][2]int {
+	ranges := make([][2]int, 0, len(locs))
+	for _, loc := range locs {
+		if loc[1] > loc[0] {
+			ranges = append(ranges, [2]int{loc[0], loc[1]})
+		}
+	}
+	return ranges
+}
+
+// scoreRanges scores exact matches: more of the text covered, whole words and
+// earlier matches rank higher
+func scoreRanges(text string, ranges [][2]int) float64 {
+	if len(text) == 0 || len(ranges) == 0 {
+		return 0
+	}
+
+	covered := 0
+	wordBonus := 0.0
+	for _, r := range ranges {
+		covered += r[1] - r[0]
+		if isWordBoundary(text, r[0]) && isWordBoundary(text, r[1]) {
+			wordBonus += 0.5
+		}
+	}
+
+	position := 1.0 - float64(ranges[0][0])/float64(len(text))
+	return 1.0 + float64(covered)/float64(len(text)) + wordBonus + 0.25*position
+}
+
+// isWordBoundary reports whether index sits at the start or end of a word
+func isWordBoundary(text string, index int) bool {
+	if index <= 0 || index >= len(text) {
+		return true
+	}
+	before, _ := utf8.DecodeLastRuneInString(text[:index])
+	after, _ := utf8.DecodeRuneInString(text[index:])
+	return isWordRune(before) != isWordRune(after)
+}
+
+// isWordRune reports whether r is part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// fuzzyMatch matches the pattern's characters in order. Consecutive characters and
+// characters at word starts score higher.
+func fuzzyMatch(text, pattern string, caseSensitive bool) (float64, [][2]int, bool) {
+	haystack, offsets := foldRunes(text, caseSensitive)
+	needle, _ := foldRunes(strings.ReplaceAll(pattern, " ", ""), caseSensitive)
+	if len(needle) == 0 {
+		return 0, nil, true
+	}
+
+	var ranges [][2]int
+	score := 0.0
+	ni := 0
+	lastMatch := -2
+	for i := 0; i < len(haystack) && ni < len(needle); i++ {
+		if haystack[i] != needle[ni] {
+			continue
+		}
+
+		score += 1.0
+		if lastMatch == i-1 {
+			score += 1.5 // Consecutive characters
+			ranges[len(ranges)-1][1] = offsets[i+1]
+		} else {
+			ranges = append(ranges, [2]int{offsets[i], offsets[i+1]})
+		}
+		if i == 0 || !isWordRune(haystack[i-1]) {
+			score += 1.0 // Start of a word
+		}
+		lastMatch = i
+		ni++
+	}
+
+	if ni < len(needle) {
+		return 0, nil, false
+	}
+
+	// Normalise so fuzzy scores sit below exact matches of similar length
+	return score / float64(len(needle)*4), ranges, true
+}
@@ -0,0 +1,166 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+)
+
+// matched returns the pieces of text covered by ranges
+func matched(text string, ranges [][2]int) []string {
+	var pieces []string
+	for _, r := range ranges {
+		pieces = append(pieces, text[r[0]:r[1]])
+	}
+	return pieces
+}
+
+func TestMatchers(t *testing.T) {
+	tests := []struct {
+		name  string
+		query Query
+		text  string
+		want  []string // Matched pieces, nil when the text shouldn't match
+	}{
+		{"substring", Query{Text: "deploy"}, "Deploy the app, then deploy docs", []string{"Deploy", "deploy"}},
+		{"substring case-sensitive", Query{Text: "deploy", CaseSensitive: true}, "Deploy the app, then deploy docs", []string{"deploy"}},
+		{"substring no match", Query{Text: "release"}, "Deploy the app", nil},
+		{"substring after umlauts", Query{Text: "prüfen"}, "Änderungen PRÜFEN", []string{"PRÜFEN"}},
+		{"substring with a longer lowercase", Query{Text: "x"}, "İx", []string{"x"}},
+		{"substring in Japanese", Query{Text: "確認"}, "資料を確認する", []string{"確認"}},
+		{"regex", Query{Text: `PR-\d+`, Mode: ModeRegex}, "Review pr-12 and PR-345", []string{"pr-12", "PR-345"}},
+		{"regex case-sensitive", Query{Text: `PR-\d+`, Mode: ModeRegex, CaseSensitive: true}, "Review pr-12 and PR-345", []string{"PR-345"}},
+		{"regex no match", Query{Text: `^done`, Mode: ModeRegex}, "not done", nil},
+		{"fuzzy", Query{Text: "dplprod", Mode: ModeFuzzy}, "deploy to production", []string{"d", "pl", "prod"}},
+		{"fuzzy with spaces", Query{Text: "dp pr", Mode: ModeFuzzy}, "deploy to production", []string{"d", "p", "pr"}},
+		{"fuzzy out of order", Query{Text: "prd", Mode: ModeFuzzy}, "deploy", nil},
+		{"fuzzy over umlauts", Query{Text: "übg", Mode: ModeFuzzy}, "Übersetzung prüfen", []string{"Üb", "g"}},
+		{"fuzzy doesn't match part of a rune", Query{Text: "ü", Mode: ModeFuzzy}, "ö", nil},
+		{"fuzzy in Japanese", Query{Text: "資確", Mode: ModeFuzzy}, "資料を確認する", []string{"資", "確"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := newMatcher(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, ranges, ok := match(tt.text)
+			if ok != (tt.want != nil) {
+				t.Fatalf("matched: got %v, want %v (ranges %v)", ok, tt.want != nil, ranges)
+			}
+			for _, r := range ranges {
+				if !utf8.ValidString(tt.text[r[0]:r[1]]) {
+					t.Errorf("range %v cuts a rune in half", r)
+				}
+			}
+			if got := matched(tt.text, ranges); ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := newMatcher(Query{Text: "(", Mode: ModeRegex}); err == nil {
+		t.Error("expected an invalid regular expression to be an error")
+	}
+	if _, err := newMatcher(Query{Text: "x", Mode: "phonetic"}); err == nil {
+		t.Error("expected an unknown mode to be an error")
+	}
+}
+
+func TestScoreRanges(t *testing.T) {
+	// Each text should score higher than the next for the query "api"
+	ranked := []string{
+		"api",                     // The whole text
+		"api docs",                // A whole word at the start
+		"update the api",          // A whole word later on
+		"apis and more apis here", // Part of a word
+	}
+	match, err := newMatcher(Query{Text: "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var previous float64
+	for i, text := range ranked {
+		score, _, ok := match(text)
+		if !ok {
+			t.Fatalf("%q didn't match", text)
+		}
+		if i > 0 && score >= previous {
+			t.Errorf("%q scored %.3f, not below %q at %.3f", text, score, ranked[i-1], previous)
+		}
+		previous = score
+	}
+
+	// Word boundaries are found next to multi-byte letters
+	if !isWordBoundary("größe x", len("größe")) || isWordBoundary("größe", len("grö")) {
+		t.Error("word boundaries are wrong around multi-byte letters")
+	}
+
+	// Fuzzy matches rank below exact ones
+	exact, _, _ := match("api")
+	fuzzy, _, _ := fuzzyMatch("a pretty idea", "api", false)
+	if fuzzy >= exact {
+		t.Errorf("fuzzy score %.3f should be below the exact score %.3f", fuzzy, exact)
+	}
+	consecutive, _, _ := fuzzyMatch("api gateway", "api", false)
+	if consecutive <= fuzzy {
+		t.Errorf("consecutive characters %.3f should score above scattered ones %.3f", consecutive, fuzzy)
+	}
+}
+
+func TestSearchNoteFilters(t *testing.T) {
+	note := notes.NewNote(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), "Acme")
+	note.Tags = []string{"acme", "job"}
+	note.PendingWork = []notes.WorkItem{
+		{Text: "Write report #docs"},
+		{Text: "Fix login #backend", State: notes.StateBlocked},
+		{Text: "Old migration", State: notes.StateCancelled},
+		{Text: "Call vendor", State: notes.StateWaiting},
+		{Text: "Plan offsite", State: notes.StateDeferred},
+	}
+	note.CompletedWork = []notes.WorkItem{{Text: "Ship release #backend", Completed: true}}
+	note.Summary = "Shipped the release"
+	file := notes.NoteFile{Path: "/notes/2026-10-18-Acme.md", Date: note.Date}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"everything", Query{}, []string{"Write report #docs", "Fix login #backend", "Old migration", "Call vendor", "Plan offsite", "Ship release #backend", "Shipped the release"}},
+		{"pending", Query{Status: StatusPending}, []string{"Write report #docs", "Fix login #backend", "Old migration", "Call vendor", "Plan offsite"}},
+		{"completed", Query{Status: StatusCompleted}, []string{"Ship release #backend"}},
+		{"summary", Query{Status: StatusSummary}, []string{"Shipped the release"}},
+		{"open", Query{Status: notes.StateNone.String()}, []string{"Write report #docs"}},
+		{"blocked", Query{Status: notes.StateBlocked.String()}, []string{"Fix login #backend"}},
+		{"cancelled", Query{Status: notes.StateCancelled.String()}, []string{"Old migration"}},
+		{"waiting", Query{Status: notes.StateWaiting.String()}, []string{"Call vendor"}},
+		{"deferred", Query{Status: notes.StateDeferred.String()}, []string{"Plan offsite"}},
+		{"item tag", Query{Tags: []string{"backend"}}, []string{"Fix login #backend", "Ship release #backend"}},
+		{"tag with #", Query{Tags: []string{"#Docs"}}, []string{"Write report #docs"}},
+		{"note tag", Query{Tags: []string{"acme"}, Status: StatusCompleted}, []string{"Ship release #backend"}},
+		{"every tag required", Query{Tags: []string{"backend", "docs"}}, nil},
+		{"tag and state", Query{Tags: []string{"backend"}, Status: notes.StateBlocked.String()}, []string{"Fix login #backend"}},
+		{"text and state", Query{Text: "login", Status: notes.StateCancelled.String()}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := newMatcher(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, r := range searchNote(note, file, "Acme", notes.DefaultMarkers, tt.query, match) {
+				got = append(got, r.Text)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+)
+
+// Day holds the activity of a single day in the period
+type Day struct {
+	Date      time.Time `json:"date"`
+	HasNote   bool      `json:"has_note"`
+	Added     int       `json:"added"`
+	Completed int       `json:"completed"`
+	Pending   int       `json:"pending"`
+	Carried   int       `json:"carried"`
+}
+
+// WeekdayCount is the number of items completed on a weekday
+type WeekdayCount struct {
+	Weekday   string `json:"weekday"`
+	Completed int    `json:"completed"`
+}
+
+// TagCount is the activity of items carrying an inline #tag
+type TagCount struct {
+	Tag       string `json:"tag"`
+	Added     int    `json:"added"`
+	Completed int    `json:"completed"`
+}
+
+// Report is the statistics of one workplace over a period
+type Report struct {
+	Workplace      string         `json:"workplace"`
+	From           time.Time      `json:"from"`
+	To             time.Time      `json:"to"`
+	Notes          int            `json:"notes"`
+	Added          int            `json:"added"`
+	Completed      int            `json:"completed"`
+	CompletionRate float64        `json:"completion_rate"`  // Share of items added in the period that are done
+	AvgDaysToDone  float64        `json:"avg_days_to_done"` // Days from first appearance to completion
+	CarryOverRatio float64        `json:"carry_over_ratio"` // Share of pending items carried from an earlier note
+	CurrentStreak  int            `json:"current_streak"`
+	LongestStreak  int            `json:"longest_streak"`
+	Weekdays       []WeekdayCount `json:"weekdays"`
+	Tags           []TagCount     `json:"tags"`
+	Days           []Day          `json:"days"`
+}
+
+// openItem is a pending item that hasn't been completed yet
+type openItem struct {
+	firstSeen time.Time
+	inPeriod  bool
+}
+
+// Compute builds the report for a workplace between from and to (inclusive).
+// Items are followed across notes by their text, so time-to-done and carry-over
+// use the whole history even when the period is shorter. Streaks always cover the
+// whole history up to today.
+func Compute(workplace string, parser *notes.Parser, from, to, today time.Time) (*Report, error) {
+	files, err := parser.ListNotes()
+	if err != nil {
+		return nil, fmt.Errorf("error listing notes for %s: %w", workplace, err)
+	}
+
+	if to.IsZero() {
+		to = today
+	}
+	if from.IsZero() {
+		from = to
+		if len(files) > 0 {
+			from = files[0].Date
+		}
+	}
+
+	report := &Report{
+		Workplace: workplace,
+		From:      from,
+		To:        to,
+		Tags:      []TagCount{},
+	}
+
+	days := make(map[string]*Day)
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		report.Days = append(report.Days, Day{Date: d})
+	}
+	for i := range report.Days {
+		days[dayKey(report.Days[i].Date)] = &report.Days[i]
+	}
+
+	open := make(map[string]openItem)
+	tags := make(map[string]*TagCount)
+	weekdays := make([]int, 7)
+	var noteDates []time.Time
+	addedInPeriod, doneOfAdded := 0, 0
+	totalDaysToDone, doneCount := 0.0, 0
+	pendingEntries, carriedEntries := 0, 0
+
+	for _, file := range files {
+		note, err := parser.ParseFile(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", file.Path, err)
+		}
+		noteDates = append(noteDates, file.Date)
+
+		inPeriod := !file.Date.Before(from) && !file.Date.After(to)
+		day := days[dayKey(file.Date)]
+		if inPeriod && day != nil {
+			day.HasNote = true
+			report.Notes++
+		}
+
+		countTags := func(item notes.WorkItem, added, completed bool) {
+			if !inPeriod {
+				return
+			}
+			for _, tag := range item.Tags() {
+				tc, ok := tags[tag]
+				if !ok {
+					tc = &TagCount{Tag: tag}
+					tags[tag] = tc
+				}
+				if added {
+					tc.Added++
+				}
+				if completed {
+					tc.Completed++
+				}
+			}
+		}
+
+		for _, item := range note.PendingWork {
+			key := itemKey(item.Text)
+			if !item.Active() {
+				// Cancelled and deferred work is neither pending nor done
+				delete(open, key)
+				continue
+			}
+			if _, ok := open[key]; ok {
+				if inPeriod {
+					pendingEntries++
+					carriedEntries++
+					day.Pending++
+					day.Carried++
+				}
+				continue
+			}
+
+			open[key] = openItem{firstSeen: file.Date, inPeriod: inPeriod}
+			if inPeriod {
+				pendingEntries++
+				day.Pending++
+				day.Added++
+				addedInPeriod++
+			}
+			countTags(item, true, false)
+		}
+
+		for _, item := range note.CompletedWork {
+			if !item.Done() {
+				continue
+			}
+			key := itemKey(item.Text)
+			first, ok := open[key]
+			delete(open, key)
+			if !ok {
+				// Added and completed in the same note
+				first = openItem{firstSeen: file.Date, inPeriod: inPeriod}
+				if inPeriod {
+					day.Added++
+					addedInPeriod++
+				}
+			}
+
+			if first.inPeriod {
+				doneOfAdded++
+			}
+			countTags(item, !ok, true)
+
+			if !inPeriod {
+				continue
+			}
+			day.Completed++
+			report.Completed++
+			weekdays[file.Date.Weekday()]++
+			totalDaysToDone += file.Date.Sub(first.firstSeen).Hours() / 24
+			doneCount++
+		}
+	}
+
+	report.Added = addedInPeriod
+	if addedInPeriod > 0 {
+		report.CompletionRate = float64(doneOfAdded) / float64(addedInPeriod)
+	}
+	if doneCount > 0 {
+		report.AvgDaysToDone = totalDaysToDone / float64(doneCount)
+	}
+	if pendingEntries > 0 {
+		report.CarryOverRatio = float64(carriedEntries) / float64(pendingEntries)
+	}
+
+	report.CurrentStreak, report.LongestStreak = Streaks(noteDates, today)
+
+	// Monday first
+	for i := 1; i <= 7; i++ {
+		wd := time.Weekday(i % 7)
+		report.Weekdays = append(report.Weekdays, WeekdayCount{Weekday: wd.String(), Completed: weekdays[wd]})
+	}
+
+	for _, tc := range tags {
+		report.Tags = append(report.Tags, *tc)
+	}
+	sort.Slice(report.Tags, func(i, j int) bool {
+		a, b := report.Tags[i], report.Tags[j]
+		if a.Added+a.Completed != b.Added+b.Completed {
+			return a.Added+a.Completed > b.Added+b.Completed
+		}
+		return a.Tag < b.Tag
+	})
+
+	return report, nil
+}
+
+// Streaks returns the current and longest run of logged workdays. Weekends
+// extend a streak when logged but never break one.
+func Streaks(dates []time.Time, today time.Time) (current, longest int) {
+	logged := make(map[string]bool)
+	for _, d := range dates {
+		logged[dayKey(d)] = true
+	}
+	if len(logged) == 0 {
+		return 0, 0
+	}
+
+	sorted := append([]time.Time(nil), dates...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	run := 0
+	for d := sorted[0]; !d.After(sorted[len(sorted)-1]); d = d.AddDate(0, 0, 1) {
+		switch {
+		case logged[dayKey(d)]:
+			run++
+		case isWeekend(d):
+			// Unlogged weekends neither break nor extend a streak
+		default:
+			run = 0
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+
+	// The current streak may end today or on the last workday, since today's
+	// note may not exist yet
+	d := today
+	if !logged[dayKey(d)] {
+		d = d.AddDate(0, 0, -1)
+	}
+	for {
+		switch {
+		case logged[dayKey(d)]:
+			current++
+		case isWeekend(d):
+		default:
+			return current, longest
+		}
+		d = d.AddDate(0, 0, -1)
+		if d.Before(sorted[0]) {
+			return current, longest
+		}
+	}
+}
+
+// isWeekend reports whether the date is a Saturday or Sunday
+func isWeekend(d time.Time) bool {
+	return d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+}
+
+// dayKey identifies a calendar day
+func dayKey(d time.Time) string {
+	return d.Format("2006-01-02")
+}
+
+// itemKey identifies the same item across notes
+func itemKey(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
@@ -14,26 +14,57 @@ import (
 	"github.com/sandepten/work-obsidian-noter/internal/notes"
 )
 
+// Summarizer summarizes completed work items. The OpenCode Client and
+// summarizer plugins implement it.
+type Summarizer interface {
+	// SummarizeWorkItems summarizes the completed items of one workplace
+	SummarizeWorkItems(items []notes.WorkItem) (string, error)
+	// SummarizeGroups summarizes the completed items of several workplaces together
+	SummarizeGroups(groups []ItemGroup) (string, error)
+	// TestConnection checks that summaries can be made
+	TestConnection() error
+	// SetTimeout sets how long making a summary may take
+	SetTimeout(timeout time.Duration)
+}
+
 // Client handles communication with the OpenCode server for AI summaries
 type Client struct {
 	baseURL    string
 	providerID string
 	modelID    string
+	prompt     string
 	httpClient *http.Client
 }
 
+// DefaultPrompt is the instruction completed work items are summarized with
+const DefaultPrompt = "Summarize the following completed work items in 1-2 concise sentences. Focus on the key accomplishments and outcomes. Keep it brief and professional. Do not use any tools, just respond with plain text:"
+
 // NewClient creates a new OpenCode API client
 func NewClient(baseURL, providerID, modelID string) *Client {
 	return &Client{
 		baseURL:    strings.TrimSuffix(baseURL, "/"),
 		providerID: providerID,
 		modelID:    modelID,
+		prompt:     DefaultPrompt,
 		httpClient: &http.Client{
 			Timeout: 120 * time.Second,
 		},
 	}
 }
 
+// SetPrompt sets the instruction items are summarized with. An empty prompt
+// keeps the default.
+func (c *Client) SetPrompt(prompt string) {
+	if prompt != "" {
+		c.prompt = prompt
+	}
+}
+
+// SetTimeout sets how long requests to the server may take
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.httpClient.Timeout = timeout
+}
+
 // Session represents an OpenCode session
 type Session struct {
 	ID string `json:"id"`
@@ -290,12 +321,47 @@ func (c *Client) SummarizeWorkItems(items []notes.WorkItem) (string, error) {
 
 	// Build the prompt
 	var sb strings.Builder
-	sb.WriteString("Summarize the following completed work items in 1-2 concise sentences. Focus on the key accomplishments and outcomes. Keep it brief and professional. Do not use any tools, just respond with plain text:\n\n")
+	sb.WriteString(c.prompt + "\n\n")
 
 	for _, item := range items {
 		sb.WriteString(fmt.Sprintf("- %s\n", item.Text))
 	}
 
+	return c.summarize(sb.String())
+}
+
+// ItemGroup is the completed work of one workplace
+type ItemGroup struct {
+	Name  string
+	Items []notes.WorkItem
+}
+
+// SummarizeGroups generates one AI summary of the completed work of several
+// workplaces, listing the items under the name of their workplace
+func (c *Client) SummarizeGroups(groups []ItemGroup) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(c.prompt + "\n")
+
+	count := 0
+	for _, group := range groups {
+		if len(group.Items) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n%s:\n", group.Name))
+		for _, item := range group.Items {
+			sb.WriteString(fmt.Sprintf("- %s\n", item.Text))
+			count++
+		}
+	}
+	if count == 0 {
+		return "No work items to summarize.", nil
+	}
+
+	return c.summarize(sb.String())
+}
+
+// summarize sends a prompt in a new session and returns the AI's answer
+func (c *Client) summarize(prompt string) (string, error) {
 	// Create session
 	session, err := c.createSession()
 	if err != nil {
@@ -313,7 +379,7 @@ func (c *Client) SummarizeWorkItems(items []notes.WorkItem) (string, error) {
 	time.Sleep(100 * time.Millisecond)
 
 	// Send message asynchronously
-	if err := c.sendMessageAsync(session.ID, sb.String()); err != nil {
+	if err := c.sendMessageAsync(session.ID, prompt); err != nil {
 		return "", fmt.Errorf("failed to send message: %w", err)
 	}
 
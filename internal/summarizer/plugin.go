@@ -0,0 +1,187 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+)
+
+// PluginPrefix starts the names of summarizer plugin executables, which are
+// found on PATH by the rest of their name
+const PluginPrefix = "worklog-summarizer-"
+
+// PluginProtocol is the version of the protocol plugins are spoken to with
+const PluginProtocol = 1
+
+// PluginRequest is what a summarizer plugin reads as JSON from stdin. Items
+// are set for one workplace and Groups for several.
+type PluginRequest struct {
+	Protocol int           `json:"protocol"`
+	Prompt   string        `json:"prompt"`
+	Items    []PluginItem  `json:"items,omitempty"`
+	Groups   []PluginGroup `json:"groups,omitempty"`
+}
+
+// PluginItem is a work item as summarizer plugins receive it
+type PluginItem struct {
+	Text     string   `json:"text"`
+	Done     bool     `json:"done"`
+	State    string   `json:"state,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Children []string `json:"children,omitempty"` // Indented lines under the item
+}
+
+// PluginGroup is the completed work of one workplace
+type PluginGroup struct {
+	Name  string       `json:"name"`
+	Items []PluginItem `json:"items"`
+}
+
+// PluginResponse is what a summarizer plugin writes as JSON to stdout
+type PluginResponse struct {
+	Summary string `json:"summary"`
+	Error   string `json:"error,omitempty"` // Set instead of the summary when the plugin failed
+}
+
+// Plugin is a summarizer run as an external executable, speaking JSON over
+// stdin and stdout
+type Plugin struct {
+	name    string
+	prompt  string
+	timeout time.Duration
+}
+
+// NewPlugin creates a summarizer for a plugin, given by the rest of its
+// executable's name after PluginPrefix or by its path
+func NewPlugin(name string) *Plugin {
+	return &Plugin{name: name, prompt: DefaultPrompt, timeout: 120 * time.Second}
+}
+
+// SetPrompt sets the instruction passed to the plugin. An empty prompt keeps
+// the default.
+func (p *Plugin) SetPrompt(prompt string) {
+	if prompt != "" {
+		p.prompt = prompt
+	}
+}
+
+// SetTimeout sets how long the plugin may run
+func (p *Plugin) SetTimeout(timeout time.Duration) {
+	p.timeout = timeout
+}
+
+// Path returns the executable of the plugin
+func (p *Plugin) Path() (string, error) {
+	command := p.name
+	if !strings.ContainsRune(p.name, filepath.Separator) && !strings.ContainsRune(p.name, '/') {
+		command = PluginPrefix + p.name
+	}
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return "", fmt.Errorf("summarizer plugin %s not found: %w", p.name, err)
+	}
+	return path, nil
+}
+
+// TestConnection checks that the plugin can be run
+func (p *Plugin) TestConnection() error {
+	_, err := p.Path()
+	return err
+}
+
+// SummarizeWorkItems asks the plugin for a summary of completed work items
+func (p *Plugin) SummarizeWorkItems(items []notes.WorkItem) (string, error) {
+	if len(items) == 0 {
+		return "No work items to summarize.", nil
+	}
+	return p.summarize(PluginRequest{Items: pluginItems(items)})
+}
+
+// SummarizeGroups asks the plugin for one summary of the completed work of
+// several workplaces
+func (p *Plugin) SummarizeGroups(groups []ItemGroup) (string, error) {
+	var request PluginRequest
+	for _, group := range groups {
+		if len(group.Items) > 0 {
+			request.Groups = append(request.Groups, PluginGroup{Name: group.Name, Items: pluginItems(group.Items)})
+		}
+	}
+	if len(request.Groups) == 0 {
+		return "No work items to summarize.", nil
+	}
+	return p.summarize(request)
+}
+
+// summarize runs the plugin with a request and returns its summary
+func (p *Plugin) summarize(request PluginRequest) (string, error) {
+	path, err := p.Path()
+	if err != nil {
+		return "", err
+	}
+	request.Protocol = PluginProtocol
+	request.Prompt = p.prompt
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = os.Environ()
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("summarizer plugin %s timed out after %s", p.name, p.timeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("summarizer plugin %s failed: %s", p.name, message)
+		}
+		return "", fmt.Errorf("summarizer plugin %s failed: exit status %d", p.name, exitErr.ExitCode())
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to run summarizer plugin %s: %w", p.name, err)
+	}
+
+	var response PluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return "", fmt.Errorf("invalid response from summarizer plugin %s: %w", p.name, err)
+	}
+	if response.Error != "" {
+		return "", fmt.Errorf("summarizer plugin %s failed: %s", p.name, response.Error)
+	}
+	summary := strings.TrimSpace(response.Summary)
+	if summary == "" {
+		return "", fmt.Errorf("summarizer plugin %s returned no summary", p.name)
+	}
+	return summary, nil
+}
+
+// pluginItems converts work items for a plugin
+func pluginItems(items []notes.WorkItem) []PluginItem {
+	converted := make([]PluginItem, len(items))
+	for i, item := range items {
+		converted[i] = PluginItem{Text: item.Text, Done: item.Completed, Tags: item.Tags(), Children: item.Children}
+		if item.State != notes.StateNone {
+			converted[i].State = item.State.String()
+		}
+	}
+	return converted
+}
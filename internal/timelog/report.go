@@ -0,0 +1,140 @@
+package timelog
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Grouping decides which rows of a report an entry counts towards
+type Grouping string
+
+const (
+	ByWorkplace Grouping = "workplace"
+	ByTag       Grouping = "tag"
+	ByItem      Grouping = "item"
+)
+
+// ParseGrouping returns the grouping with the given name
+func ParseGrouping(name string) (Grouping, error) {
+	switch g := Grouping(name); g {
+	case ByWorkplace, ByTag, ByItem:
+		return g, nil
+	}
+	return "", fmt.Errorf("invalid grouping %q (use workplace, tag or item)", name)
+}
+
+// keys returns the rows an entry counts towards
+func (g Grouping) keys(e Entry) []string {
+	switch g {
+	case ByTag:
+		if len(e.Tags) == 0 {
+			return []string{"(untagged)"}
+		}
+		return e.Tags
+	case ByItem:
+		return []string{e.Item}
+	}
+	return []string{e.Workplace}
+}
+
+// Row is the tracked time of one workplace, tag or item
+type Row struct {
+	Key   string
+	Days  []time.Duration
+	Total time.Duration
+}
+
+// Report is the tracked time per day over a date range
+type Report struct {
+	From  time.Time
+	To    time.Time
+	By    Grouping
+	Days  []time.Time
+	Rows  []Row
+	Daily []time.Duration // Total per day over all rows
+	Total time.Duration
+}
+
+// NewReport adds up the time tracked on each day from from to to, inclusive.
+// Intervals running past midnight are split between the days. Entries with a
+// tag count in full towards each of their tags.
+func NewReport(entries []Entry, from, to, now time.Time, by Grouping, match func(Entry) bool) Report {
+	r := Report{From: from, To: to, By: by}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		r.Days = append(r.Days, d)
+	}
+	r.Daily = make([]time.Duration, len(r.Days))
+
+	rows := make(map[string]*Row)
+	for _, e := range entries {
+		if match != nil && !match(e) {
+			continue
+		}
+		end := e.End
+		if e.Running() {
+			end = now
+		}
+
+		for i, day := range r.Days {
+			d := overlap(e.Start, end, localDay(day), localDay(day).AddDate(0, 0, 1))
+			if d <= 0 {
+				continue
+			}
+			for _, key := range by.keys(e) {
+				row, ok := rows[key]
+				if !ok {
+					row = &Row{Key: key, Days: make([]time.Duration, len(r.Days))}
+					rows[key] = row
+				}
+				row.Days[i] += d
+				row.Total += d
+			}
+			r.Daily[i] += d
+			r.Total += d
+		}
+	}
+
+	for _, row := range rows {
+		r.Rows = append(r.Rows, *row)
+	}
+	sort.Slice(r.Rows, func(i, j int) bool {
+		if r.Rows[i].Total != r.Rows[j].Total {
+			return r.Rows[i].Total > r.Rows[j].Total
+		}
+		return r.Rows[i].Key < r.Rows[j].Key
+	})
+	return r
+}
+
+// localDay returns local midnight of a note date, which is UTC midnight
+func localDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// overlap returns how much of [start, end) falls within [from, to)
+func overlap(start, end, from, to time.Time) time.Duration {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// FormatDuration renders a duration as hours and minutes, such as 1h30m or 45m
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh%02dm", h, m)
+}
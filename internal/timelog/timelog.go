@@ -0,0 +1,204 @@
+package timelog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Event types written to the log
+const (
+	EventStart = "start"
+	EventStop  = "stop"
+)
+
+// Event is one line of the log. The log is only ever appended to, so a crash
+// loses at most the line being written.
+type Event struct {
+	Type      string    `json:"event"`
+	Time      time.Time `json:"time"`
+	Workplace string    `json:"workplace,omitempty"`
+	Item      string    `json:"item,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+}
+
+// Entry is a tracked interval
+type Entry struct {
+	Workplace string
+	Item      string
+	Tags      []string
+	Start     time.Time
+	End       time.Time // Zero while the timer is running
+}
+
+// Running reports whether the timer of the entry is still running
+func (e Entry) Running() bool {
+	return e.End.IsZero()
+}
+
+// Duration returns the tracked time, counting a running timer up to now
+func (e Entry) Duration(now time.Time) time.Duration {
+	end := e.End
+	if e.Running() {
+		end = now
+	}
+	return end.Sub(e.Start)
+}
+
+// Log is an append-only file of timer events
+type Log struct {
+	path string
+}
+
+// Open returns the log stored at path. The file is created on the first write.
+func Open(path string) *Log {
+	return &Log{path: path}
+}
+
+// Entries reads every tracked interval in the order they were started
+func (l *Log) Entries() ([]Entry, error) {
+	file, err := os.Open(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	running := -1
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			// A line cut short by a crash; the rest of the log is still good
+			continue
+		}
+
+		switch event.Type {
+		case EventStart:
+			if running >= 0 {
+				entries[running].End = event.Time
+			}
+			entries = append(entries, Entry{
+				Workplace: event.Workplace,
+				Item:      event.Item,
+				Tags:      event.Tags,
+				Start:     event.Time,
+			})
+			running = len(entries) - 1
+		case EventStop:
+			if running >= 0 {
+				entries[running].End = event.Time
+				running = -1
+			}
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown event %q", l.path, lineNum, event.Type)
+		}
+	}
+
+	return entries, scanner.Err()
+}
+
+// Running returns the entry whose timer is running, or nil
+func (l *Log) Running() (*Entry, error) {
+	entries, err := l.Entries()
+	if err != nil {
+		return nil, err
+	}
+	if n := len(entries); n > 0 && entries[n-1].Running() {
+		return &entries[n-1], nil
+	}
+	return nil, nil
+}
+
+// Start starts a timer on an item. A timer that is already running is stopped
+// first and returned.
+func (l *Log) Start(workplace, item string, tags []string, at time.Time) (*Entry, error) {
+	stopped, err := l.Stop(at)
+	if err != nil {
+		return nil, err
+	}
+	err = l.append(Event{Type: EventStart, Time: at, Workplace: workplace, Item: item, Tags: tags})
+	return stopped, err
+}
+
+// Stop stops the running timer and returns its entry, or nil if none was running
+func (l *Log) Stop(at time.Time) (*Entry, error) {
+	running, err := l.Running()
+	if err != nil || running == nil {
+		return nil, err
+	}
+	if err := l.append(Event{Type: EventStop, Time: at}); err != nil {
+		return nil, err
+	}
+	running.End = at
+	return running, nil
+}
+
+// append writes an event as a single line and syncs it to disk
+func (l *Log) append(event Event) error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// RenameWorkplace returns the log's content with the events of one workplace
+// moved to another, and how many events changed. Other lines are kept as they are.
+func (l *Log) RenameWorkplace(oldName, newName string) ([]byte, int, error) {
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	changed := 0
+	for i, line := range lines {
+		var event Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil || event.Workplace != oldName {
+			continue
+		}
+		event.Workplace = newName
+		renamed, err := json.Marshal(event)
+		if err != nil {
+			return nil, 0, err
+		}
+		lines[i] = string(renamed) + "\n"
+		changed++
+	}
+	return []byte(strings.Join(lines, "")), changed, nil
+}
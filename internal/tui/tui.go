@@ -0,0 +1,476 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sandepten/work-obsidian-noter/internal/hooks"
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+)
+
+// reloadInterval is how often the open note is checked for changes on disk
+const reloadInterval = time.Second
+
+// Workspace is a workplace the TUI can switch to
+type Workspace struct {
+	Name   string
+	Parser *notes.Parser
+	Writer *notes.Writer
+}
+
+// Notifier is told about a change once its note is saved, with the hook event
+// it is. A returned error is shown in the status line.
+type Notifier func(event, workplace string, note *notes.Note, items []notes.WorkItem) error
+
+// pane is one of the two item lists
+type pane int
+
+const (
+	panePending pane = iota
+	paneCompleted
+)
+
+// kind returns the note section a pane shows
+func (p pane) kind() notes.SectionKind {
+	if p == paneCompleted {
+		return notes.SectionCompleted
+	}
+	return notes.SectionPending
+}
+
+// mode is what keyboard input currently does
+type mode int
+
+const (
+	modeNormal mode = iota
+	modeAdd
+	modeEdit
+	modeConfirmDelete
+	modeHelp
+)
+
+// tickMsg triggers a check for changes on disk
+type tickMsg time.Time
+
+// Model is the Bubble Tea model of the worklog TUI
+type Model struct {
+	workspaces []Workspace
+	current    int
+	notify     Notifier // nil when nothing is told about changes
+	today      time.Time
+	date       time.Time
+
+	note    *notes.Note // nil when the day has no note yet
+	modTime time.Time   // Modification time of the note when last read or written
+
+	focus  pane
+	cursor [2]int
+	mode   mode
+	input  textinput.Model
+
+	status string
+	err    error
+	width  int
+	height int
+}
+
+// New creates the TUI model showing today's note of the workspace at index
+// current, telling notify about the changes made
+func New(workspaces []Workspace, current int, today time.Time, notify Notifier) Model {
+	input := textinput.New()
+	input.Prompt = "› "
+	input.CharLimit = 500
+
+	m := Model{
+		workspaces: workspaces,
+		current:    current,
+		notify:     notify,
+		today:      today,
+		date:       today,
+		input:      input,
+		width:      80,
+		height:     24,
+	}
+	m.load()
+	return m
+}
+
+// Init starts the live reload ticker
+func (m Model) Init() tea.Cmd {
+	return tick()
+}
+
+// tick schedules the next check for changes on disk
+func tick() tea.Cmd {
+	return tea.Tick(reloadInterval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// workspace returns the active workspace
+func (m *Model) workspace() Workspace {
+	return m.workspaces[m.current]
+}
+
+// notePath returns the path of the note for the selected day
+func (m *Model) notePath() string {
+	return m.workspace().Parser.NotePath(m.date)
+}
+
+// load reads the note for the selected workspace and day
+func (m *Model) load() {
+	m.err = nil
+	m.note = nil
+	m.modTime = time.Time{}
+
+	note, err := m.workspace().Parser.FindTodayNote(m.date)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.note = note
+	if info, err := os.Stat(m.notePath()); err == nil {
+		m.modTime = info.ModTime()
+	}
+	m.clampCursors()
+}
+
+// save writes the note and remembers its modification time so our own write
+// isn't mistaken for an outside change. It reports whether the note was saved.
+func (m *Model) save() bool {
+	if err := m.workspace().Writer.WriteNote(m.note); err != nil {
+		m.err = fmt.Errorf("error saving note: %w", err)
+		return false
+	}
+	if info, err := os.Stat(m.note.FilePath); err == nil {
+		m.modTime = info.ModTime()
+	}
+	m.clampCursors()
+	return true
+}
+
+// changed tells the notifier about a saved change to the note
+func (m *Model) changed(event string, items []notes.WorkItem) {
+	if m.notify == nil {
+		return
+	}
+	if err := m.notify(event, m.workspace().Name, m.note, items); err != nil {
+		m.err = err
+	}
+}
+
+// ensureNote creates the day's note if it doesn't exist yet
+func (m *Model) ensureNote() bool {
+	if m.note != nil {
+		return true
+	}
+	note, err := m.workspace().Writer.CreateTodayNote(m.date)
+	if err != nil {
+		m.err = fmt.Errorf("error creating note: %w", err)
+		return false
+	}
+	m.note = note
+	return true
+}
+
+// items returns the items of a pane
+func (m *Model) items(p pane) []notes.WorkItem {
+	if m.note == nil {
+		return nil
+	}
+	if p == panePending {
+		return m.note.PendingWork
+	}
+	return m.note.CompletedWork
+}
+
+// clampCursors keeps both cursors inside their lists
+func (m *Model) clampCursors() {
+	for _, p := range []pane{panePending, paneCompleted} {
+		n := len(m.items(p))
+		if m.cursor[p] >= n {
+			m.cursor[p] = n - 1
+		}
+		if m.cursor[p] < 0 {
+			m.cursor[p] = 0
+		}
+	}
+}
+
+// selected returns the focused item index, or -1 when the pane is empty
+func (m *Model) selected() int {
+	if len(m.items(m.focus)) == 0 {
+		return -1
+	}
+	return m.cursor[m.focus]
+}
+
+// Update handles a message
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.input.Width = msg.Width - 6
+		return m, nil
+
+	case tickMsg:
+		m.checkReload()
+		return m, tick()
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case modeAdd, modeEdit:
+			return m.updateInput(msg)
+		case modeConfirmDelete:
+			return m.updateConfirmDelete(msg), nil
+		case modeHelp:
+			m.mode = modeNormal
+			return m, nil
+		}
+		return m.updateNormal(msg)
+	}
+
+	return m, nil
+}
+
+// checkReload reloads the note when it was changed outside the TUI
+func (m *Model) checkReload() {
+	if m.mode != modeNormal && m.mode != modeHelp {
+		return // Don't pull the note out from under an edit
+	}
+
+	info, err := os.Stat(m.notePath())
+	switch {
+	case err != nil && m.note != nil && os.IsNotExist(err):
+		m.load()
+		m.status = "Note was deleted on disk"
+	case err == nil && !info.ModTime().Equal(m.modTime):
+		m.load()
+		m.status = "Reloaded: note changed on disk"
+	}
+}
+
+// updateNormal handles keys while browsing
+func (m Model) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+
+	switch msg.String() {
+	case "q", "ctrl+c", "esc":
+		return m, tea.Quit
+
+	case "?":
+		m.mode = modeHelp
+
+	case "tab", "shift+tab":
+		m.focus = 1 - m.focus
+
+	case "up", "k":
+		if m.cursor[m.focus] > 0 {
+			m.cursor[m.focus]--
+		}
+	case "down", "j":
+		if m.cursor[m.focus] < len(m.items(m.focus))-1 {
+			m.cursor[m.focus]++
+		}
+
+	case "shift+up", "K":
+		m.moveItem(-1)
+	case "shift+down", "J":
+		m.moveItem(1)
+
+	case "left", "h":
+		m.date = m.date.AddDate(0, 0, -1)
+		m.load()
+	case "right", "l":
+		m.date = m.date.AddDate(0, 0, 1)
+		m.load()
+	case "t":
+		m.date = m.today
+		m.load()
+
+	case "[":
+		m.current = (m.current + len(m.workspaces) - 1) % len(m.workspaces)
+		m.load()
+	case "]":
+		m.current = (m.current + 1) % len(m.workspaces)
+		m.load()
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if i := int(msg.String()[0] - '1'); i < len(m.workspaces) {
+			m.current = i
+			m.load()
+		}
+
+	case "r":
+		m.load()
+		m.status = "Reloaded"
+
+	case "a":
+		m.mode = modeAdd
+		m.input.SetValue("")
+		m.input.Placeholder = "New pending item"
+		return m, m.input.Focus()
+
+	case "e", "enter":
+		if i := m.selected(); i >= 0 {
+			m.mode = modeEdit
+			m.input.SetValue(m.items(m.focus)[i].Title())
+			m.input.CursorEnd()
+			return m, m.input.Focus()
+		}
+
+	case " ", "x":
+		m.toggleItem()
+	case "b":
+		m.toggleState(notes.StateBlocked)
+	case "c":
+		m.toggleState(notes.StateCancelled)
+
+	case "d", "delete":
+		if m.selected() >= 0 {
+			m.mode = modeConfirmDelete
+		}
+	}
+
+	return m, nil
+}
+
+// updateInput handles keys while adding or editing an item
+func (m Model) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.mode = modeNormal
+		m.input.Blur()
+		return m, nil
+
+	case "enter":
+		text := strings.TrimSpace(m.input.Value())
+		adding := m.mode == modeAdd
+		m.mode = modeNormal
+		m.input.Blur()
+		if text == "" {
+			return m, nil
+		}
+
+		if adding {
+			created := m.note == nil
+			if !m.ensureNote() {
+				return m, nil
+			}
+			m.note.AddPendingItem(text)
+			m.focus = panePending
+			m.cursor[panePending] = len(m.note.PendingWork) - 1
+			m.status = "Added item"
+			if m.save() {
+				if created {
+					m.changed(hooks.NoteCreated, nil)
+				}
+				m.changed(hooks.ItemAdded, m.note.PendingWork[len(m.note.PendingWork)-1:])
+			}
+			return m, nil
+		}
+		if i := m.selected(); i >= 0 {
+			if err := m.note.EditItem(m.focus.kind(), i, text); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.status = "Updated item"
+		}
+		m.save()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// updateConfirmDelete handles the answer to a delete confirmation
+func (m Model) updateConfirmDelete(msg tea.KeyMsg) Model {
+	m.mode = modeNormal
+	if msg.String() != "y" && msg.String() != "Y" {
+		return m
+	}
+
+	i := m.selected()
+	if i < 0 {
+		return m
+	}
+	item := m.items(m.focus)[i]
+	if m.focus == panePending {
+		m.note.RemovePendingItem(i)
+	} else {
+		m.note.RemoveCompletedItem(i)
+	}
+	m.status = "Deleted item"
+	if m.save() {
+		m.changed(hooks.ItemDeleted, []notes.WorkItem{item})
+	}
+	return m
+}
+
+// toggleItem moves the selected item between pending and completed
+func (m *Model) toggleItem() {
+	i := m.selected()
+	if i < 0 {
+		return
+	}
+	if m.focus == panePending {
+		m.note.MarkItemCompleted(i)
+		m.status = "Marked as done"
+		if m.save() {
+			m.changed(hooks.ItemCompleted, m.note.CompletedWork[len(m.note.CompletedWork)-1:])
+		}
+		return
+	}
+	m.note.MarkItemPending(i)
+	m.status = "Moved back to pending"
+	m.save()
+}
+
+// toggleState marks the selected pending item with a state, or reopens it
+// when it already has that state
+func (m *Model) toggleState(state notes.ItemState) {
+	i := m.selected()
+	if i < 0 || m.focus != panePending {
+		return
+	}
+	item := &m.note.PendingWork[i]
+	if item.Deferred() {
+		m.status = "Deferred items are scheduled; use 'worklog reopen' to bring one back"
+		return
+	}
+	if item.State == state {
+		item.SetState(notes.StateNone, "")
+		m.status = "Reopened item"
+	} else {
+		item.SetState(state, item.Reason())
+		m.status = "Marked as " + state.String()
+	}
+	m.save()
+}
+
+// moveItem moves the selected item up (-1) or down (+1) within its list
+func (m *Model) moveItem(delta int) {
+	i := m.selected()
+	j := i + delta
+	if i < 0 || j < 0 || j >= len(m.items(m.focus)) {
+		return
+	}
+	if err := m.note.MoveItem(m.focus.kind(), i, j); err != nil {
+		m.err = err
+		return
+	}
+	m.cursor[m.focus] = j
+	m.save()
+}
+
+// Run starts the TUI full-screen and blocks until the user quits
+func Run(workspaces []Workspace, current int, today time.Time, notify Notifier) error {
+	if len(workspaces) == 0 {
+		return fmt.Errorf("no workplaces configured")
+	}
+	_, err := tea.NewProgram(New(workspaces, current, today, notify), tea.WithAltScreen()).Run()
+	return err
+}
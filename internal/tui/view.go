@@ -0,0 +1,251 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sandepten/work-obsidian-noter/internal/notes"
+	"github.com/sandepten/work-obsidian-noter/internal/ui"
+)
+
+var (
+	activeTabStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(ui.White).
+			Background(ui.Purple).
+			Padding(0, 1)
+
+	inactiveTabStyle = lipgloss.NewStyle().
+				Foreground(ui.Gray).
+				Padding(0, 1)
+
+	cursorStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(ui.Cyan)
+
+	keyStyle = lipgloss.NewStyle().
+			Foreground(ui.Cyan)
+)
+
+// View renders the whole screen
+func (m Model) View() string {
+	if m.mode == modeHelp {
+		return m.viewHelp()
+	}
+
+	var sections []string
+	sections = append(sections, m.viewTabs(), m.viewDate(), "")
+
+	paneWidth := (m.width - 5) / 2
+	if paneWidth < 20 {
+		paneWidth = 20
+	}
+	panes := lipgloss.JoinHorizontal(lipgloss.Top,
+		m.viewPane(panePending, paneWidth),
+		" ",
+		m.viewPane(paneCompleted, paneWidth),
+	)
+	sections = append(sections, panes, m.viewSummary(), m.viewFooter())
+
+	return strings.Join(sections, "\n")
+}
+
+// viewTabs renders one tab per workplace
+func (m Model) viewTabs() string {
+	var tabs []string
+	for i, ws := range m.workspaces {
+		label := fmt.Sprintf("%d %s", i+1, ws.Name)
+		if i == m.current {
+			tabs = append(tabs, activeTabStyle.Render(label))
+		} else {
+			tabs = append(tabs, inactiveTabStyle.Render(label))
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+}
+
+// viewDate renders the selected day
+func (m Model) viewDate() string {
+	date := ui.TitleStyle.Render("📅 " + m.date.Format("Monday, Jan 2 2006"))
+	switch days := int(m.date.Sub(m.today).Hours() / 24); {
+	case days == 0:
+		date += "  " + ui.MutedStyle.Render("today")
+	case days == -1:
+		date += "  " + ui.MutedStyle.Render("yesterday")
+	case days == 1:
+		date += "  " + ui.MutedStyle.Render("tomorrow")
+	}
+	return ui.MutedStyle.Render("◀ ") + date + ui.MutedStyle.Render(" ▶")
+}
+
+// viewPane renders the pending or completed list
+func (m Model) viewPane(p pane, width int) string {
+	style := ui.PendingCardStyle
+	title := ui.PendingItemStyle.Bold(true).Render(ui.T(ui.MsgPendingHeader))
+	icon := ui.PendingItemStyle.Render(ui.IconPending)
+	empty := ui.T(ui.MsgNoPending)
+	if p == paneCompleted {
+		style = ui.CompletedCardStyle
+		title = ui.CompletedItemStyle.Bold(true).Render(ui.T(ui.MsgDoneHeader))
+		icon = ui.CompletedItemStyle.Render(ui.IconCompleted)
+		empty = ui.T(ui.MsgNoCompleted)
+	}
+	if p != m.focus {
+		style = style.BorderForeground(ui.Subtle)
+	}
+
+	items := m.items(p)
+	lines := []string{fmt.Sprintf("%s %s", title, ui.MutedStyle.Render(fmt.Sprintf("(%d)", len(items)))), ""}
+
+	if m.note == nil && p == panePending {
+		lines = append(lines, ui.RenderEmptyState("No note for this day — press a to start one"))
+	} else if len(items) == 0 {
+		lines = append(lines, ui.RenderEmptyState(empty))
+	}
+
+	textWidth := width - 8
+	for i, item := range items {
+		text := truncate(item.Text, textWidth)
+		itemIcon, itemStyle := stateIcon(item)
+		if itemIcon == "" {
+			itemIcon = icon
+		}
+		prefix := "  "
+		switch {
+		case p == m.focus && i == m.cursor[p]:
+			prefix = cursorStyle.Render("› ")
+			text = cursorStyle.Render(text)
+		case itemStyle != nil:
+			text = itemStyle.Render(text)
+		case p == paneCompleted:
+			text = ui.CompletedItemStyle.Render(text)
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %s", prefix, itemIcon, text))
+	}
+
+	return style.Width(width).Render(strings.Join(lines, "\n"))
+}
+
+// stateIcon returns the icon and text style of a cancelled, deferred or
+// blocked item, or "" and nil for plain items
+func stateIcon(item notes.WorkItem) (string, *lipgloss.Style) {
+	var icon string
+	var style lipgloss.Style
+	switch {
+	case item.Cancelled():
+		icon, style = ui.IconCancelled, ui.CancelledItemStyle
+	case item.Deferred():
+		icon, style = ui.IconDeferred, ui.DeferredItemStyle
+	case item.State == notes.StateWaiting:
+		icon, style = ui.IconWaiting, ui.BlockedItemStyle
+	case item.State == notes.StateBlocked:
+		icon, style = ui.IconBlocked, ui.BlockedItemStyle
+	default:
+		return "", nil
+	}
+	return style.UnsetStrikethrough().Render(icon), &style
+}
+
+// viewSummary renders the note's summaries and today's progress
+func (m Model) viewSummary() string {
+	if m.note == nil {
+		return ""
+	}
+
+	var lines []string
+	pending, completed := len(m.note.ActivePending()), len(m.note.DoneWork())
+	progress := fmt.Sprintf("%d pending · %d done", pending, completed)
+	if total := pending + completed; total > 0 {
+		progress += fmt.Sprintf(" · %d%% complete", completed*100/total)
+	}
+	lines = append(lines, ui.MutedStyle.Render(progress))
+
+	if m.note.Summary != "" {
+		lines = append(lines, ui.RenderSummary("Summary", truncate(m.note.Summary, m.width-12)))
+	}
+	if m.note.YesterdaySummary != "" {
+		lines = append(lines, ui.RenderSummary("Yesterday", truncate(m.note.YesterdaySummary, m.width-14)))
+	}
+
+	return "\n" + strings.Join(lines, "\n")
+}
+
+// viewFooter renders the input line, status and key hints
+func (m Model) viewFooter() string {
+	var lines []string
+	lines = append(lines, "")
+
+	switch m.mode {
+	case modeAdd, modeEdit:
+		lines = append(lines, m.input.View())
+		lines = append(lines, hints("enter", "save", "esc", "cancel"))
+		return strings.Join(lines, "\n")
+	case modeConfirmDelete:
+		text := m.items(m.focus)[m.selected()].Text
+		lines = append(lines, ui.RenderWarning(fmt.Sprintf("Delete \"%s\"? (y/n)", truncate(text, m.width-20))))
+		return strings.Join(lines, "\n")
+	}
+
+	switch {
+	case m.err != nil:
+		lines = append(lines, ui.RenderError(m.err.Error()))
+	case m.status != "":
+		lines = append(lines, ui.RenderSuccess(m.status))
+	default:
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, hints("a", "add", "e", "edit", "space", "done", "d", "delete",
+		"←/→", "day", "[/]", "workplace", "?", "help", "q", "quit"))
+	return strings.Join(lines, "\n")
+}
+
+// viewHelp renders the list of key bindings
+func (m Model) viewHelp() string {
+	bindings := [][2]string{
+		{"↑/k ↓/j", "Move the cursor"},
+		{"tab", "Switch between pending and completed"},
+		{"a", "Add a pending item"},
+		{"e / enter", "Edit the selected item"},
+		{"space / x", "Mark done, or move back to pending"},
+		{"d", "Delete the selected item"},
+		{"b / c", "Mark the selected pending item blocked or cancelled (again to reopen)"},
+		{"K/J", "Move the selected item up or down"},
+		{"←/h →/l", "Previous or next day"},
+		{"t", "Jump to today"},
+		{"[ ] 1-9", "Switch workplace"},
+		{"r", "Reload the note from disk"},
+		{"q / esc", "Quit"},
+	}
+
+	var lines []string
+	lines = append(lines, ui.TitleStyle.Render("Worklog — keys"), "")
+	for _, b := range bindings {
+		lines = append(lines, fmt.Sprintf("  %s  %s", keyStyle.Render(fmt.Sprintf("%-10s", b[0])), b[1]))
+	}
+	lines = append(lines, "", ui.MutedStyle.Render("The note reloads automatically when it changes on disk."),
+		"", ui.MutedStyle.Render("Press any key to go back"))
+	return strings.Join(lines, "\n")
+}
+
+// hints renders key/description pairs for the footer
+func hints(pairs ...string) string {
+	var parts []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, keyStyle.Render(pairs[i])+" "+ui.MutedStyle.Render(pairs[i+1]))
+	}
+	return strings.Join(parts, ui.MutedStyle.Render(" • "))
+}
+
+// truncate shortens text to width cells, adding an ellipsis
+func truncate(text string, width int) string {
+	if width <= 1 || lipgloss.Width(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && lipgloss.Width(string(runes)) > width-1 {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
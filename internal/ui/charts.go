@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sparkBlocks are the bar heights used by RenderSparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// RenderSparkline renders values as a one-line bar chart. Zero values are drawn
+// as the lowest bar in the muted color.
+func RenderSparkline(values []int) string {
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		if v <= 0 || max == 0 {
+			b.WriteString(MutedStyle.Render(string(sparkBlocks[0])))
+			continue
+		}
+		level := (v*(len(sparkBlocks)-1) + max - 1) / max
+		b.WriteString(CompletedItemStyle.Render(string(sparkBlocks[level])))
+	}
+	return b.String()
+}
+
+// RenderBar renders a horizontal bar of value relative to max, width cells wide
+func RenderBar(value, max, width int, style lipgloss.Style) string {
+	if max <= 0 || value <= 0 {
+		return ""
+	}
+	filled := value * width / max
+	if filled == 0 {
+		filled = 1
+	}
+	return style.Render(strings.Repeat("█", filled))
+}
+
+// HeatLevel maps a value to one of the HeatColors levels relative to max
+func HeatLevel(value, max int) int {
+	if value <= 0 || max <= 0 {
+		return 0
+	}
+	levels := len(HeatColors) - 1
+	level := (value*levels + max - 1) / max
+	if level > levels {
+		level = levels
+	}
+	return level
+}
+
+// RenderHeatCell renders text in the color of a heatmap level. Days without a
+// note are drawn in red so gaps stand out.
+func RenderHeatCell(text string, level int, missing bool) string {
+	if missing {
+		return lipgloss.NewStyle().Foreground(Red).Render(text)
+	}
+	return lipgloss.NewStyle().Foreground(HeatColors[level]).Render(text)
+}
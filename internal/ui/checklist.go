@@ -0,0 +1,343 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/manifoldco/promptui"
+	"github.com/mattn/go-isatty"
+)
+
+// ErrCancelled is returned when the user cancels a checklist
+var ErrCancelled = errors.New("cancelled")
+
+// fallbackError turns a plain prompt the user interrupted or closed with ^D
+// into ErrCancelled
+func fallbackError(err error) error {
+	if err == promptui.ErrInterrupt || err == promptui.ErrEOF {
+		return ErrCancelled
+	}
+	return err
+}
+
+// checklistHeight is the number of items shown at once
+const checklistHeight = 15
+
+// checklistModel is a multi-select list with filtering
+type checklistModel struct {
+	items      []string
+	checked    []bool
+	deferred   []bool // Items marked with d, when deferrable
+	deferrable bool
+	visible    []int // Indices of items matching the filter
+	cursor     int   // Position in visible
+	offset     int   // First visible row shown
+	filter     string
+	filtering  bool
+	done       bool
+	cancelled  bool
+}
+
+// newChecklist creates a checklist with nothing selected
+func newChecklist(items []string) checklistModel {
+	m := checklistModel{
+		items:    items,
+		checked:  make([]bool, len(items)),
+		deferred: make([]bool, len(items)),
+	}
+	m.applyFilter()
+	return m
+}
+
+// applyFilter recomputes the visible items from the filter text
+func (m *checklistModel) applyFilter() {
+	m.visible = m.visible[:0]
+	needle := strings.ToLower(m.filter)
+	for i, item := range m.items {
+		if needle == "" || strings.Contains(strings.ToLower(item), needle) {
+			m.visible = append(m.visible, i)
+		}
+	}
+	if m.cursor >= len(m.visible) {
+		m.cursor = len(m.visible) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	m.scroll()
+}
+
+// scroll keeps the cursor inside the shown window
+func (m *checklistModel) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+checklistHeight {
+		m.offset = m.cursor - checklistHeight + 1
+	}
+	if m.offset > len(m.visible)-checklistHeight {
+		m.offset = max(len(m.visible)-checklistHeight, 0)
+	}
+}
+
+// Init implements tea.Model
+func (m checklistModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m checklistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if key.String() == "ctrl+c" {
+		m.cancelled = true
+		return m, tea.Quit
+	}
+
+	if m.filtering {
+		switch key.Type {
+		case tea.KeyEnter:
+			m.filtering = false
+		case tea.KeyEsc:
+			m.filtering = false
+			m.filter = ""
+			m.applyFilter()
+		case tea.KeyBackspace:
+			if r := []rune(m.filter); len(r) > 0 {
+				m.filter = string(r[:len(r)-1])
+				m.applyFilter()
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			m.filter += string(key.Runes)
+			m.applyFilter()
+		}
+		return m, nil
+	}
+
+	switch key.String() {
+	case "esc", "q":
+		if m.filter != "" {
+			m.filter = ""
+			m.applyFilter()
+			return m, nil
+		}
+		m.cancelled = true
+		return m, tea.Quit
+	case "enter":
+		m.done = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.visible)-1 {
+			m.cursor++
+		}
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = len(m.visible) - 1
+	case " ", "x":
+		if len(m.visible) > 0 {
+			i := m.visible[m.cursor]
+			m.checked[i] = !m.checked[i]
+			m.deferred[i] = false
+		}
+	case "d":
+		if m.deferrable && len(m.visible) > 0 {
+			i := m.visible[m.cursor]
+			m.deferred[i] = !m.deferred[i]
+			m.checked[i] = false
+		}
+	case "a":
+		// Select every shown item, or clear them when they're all selected
+		all := true
+		for _, i := range m.visible {
+			all = all && m.checked[i]
+		}
+		for _, i := range m.visible {
+			m.checked[i] = !all
+			m.deferred[i] = false
+		}
+	case "/":
+		m.filtering = true
+	}
+
+	m.scroll()
+	return m, nil
+}
+
+// View implements tea.Model
+func (m checklistModel) View() string {
+	if m.done || m.cancelled {
+		return ""
+	}
+
+	var b strings.Builder
+	if m.filtering || m.filter != "" {
+		cursor := ""
+		if m.filtering {
+			cursor = "█"
+		}
+		b.WriteString(PromptStyle.Render(T(MsgChecklistFilter)) + m.filter + cursor + "\n")
+	}
+
+	if len(m.visible) == 0 {
+		b.WriteString(RenderEmptyState("  "+T(MsgChecklistNoMatches)) + "\n")
+	}
+
+	end := min(m.offset+checklistHeight, len(m.visible))
+	if m.offset > 0 {
+		b.WriteString(MutedStyle.Render("  ↑ …") + "\n")
+	}
+	for row := m.offset; row < end; row++ {
+		i := m.visible[row]
+		box := MutedStyle.Render("[ ]")
+		text := m.items[i]
+		switch {
+		case m.checked[i]:
+			box = CompletedItemStyle.Render("[" + IconCompleted + "]")
+			text = CompletedItemStyle.Render(text)
+		case m.deferred[i]:
+			box = WarningStyle.Render("[>]")
+			text = WarningStyle.Render(text)
+		}
+		prefix := "  "
+		if row == m.cursor {
+			prefix = lipgloss.NewStyle().Foreground(Cyan).Bold(true).Render("› ")
+			if !m.checked[i] && !m.deferred[i] {
+				text = lipgloss.NewStyle().Foreground(Cyan).Render(text)
+			}
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s\n", prefix, box, text))
+	}
+	if end < len(m.visible) {
+		b.WriteString(MutedStyle.Render("  ↓ …") + "\n")
+	}
+
+	status := Tf(MsgChecklistSelected, len(m.selected()), len(m.items))
+	help := T(MsgChecklistHelp)
+	if m.deferrable {
+		status += " • " + Tf(MsgChecklistDeferred, len(marked(m.deferred)))
+		help = T(MsgChecklistDeferHelp)
+	}
+	b.WriteString("\n" + MutedStyle.Render(status+" • "+help) + "\n")
+	return b.String()
+}
+
+// selected returns the indices of the checked items in ascending order
+func (m checklistModel) selected() []int {
+	return marked(m.checked)
+}
+
+// marked returns the indices of the set flags in ascending order
+func marked(flags []bool) []int {
+	var indices []int
+	for i, set := range flags {
+		if set {
+			indices = append(indices, i)
+		}
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// checklistSupported reports whether the terminal can run the interactive checklist
+func checklistSupported() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+}
+
+// Checklist lets the user pick any number of items and returns their indices
+// in ascending order. It returns ErrCancelled when the user cancels, in the
+// checklist or in the fallback. When the terminal lacks raw-mode support, the
+// fallback asks about each item in turn.
+func (p *Prompter) Checklist(title string, items []string, fallback func(index int) (bool, error)) ([]int, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	fmt.Println(RenderInfo(title))
+	fmt.Println()
+
+	if m, ok, err := runChecklist(newChecklist(items)); ok {
+		if err != nil {
+			return nil, err
+		}
+		return m.selected(), nil
+	}
+
+	var selectedIndices []int
+	for i := range items {
+		ok, err := fallback(i)
+		if err != nil {
+			return nil, fallbackError(err)
+		}
+		if ok {
+			selectedIndices = append(selectedIndices, i)
+		}
+	}
+	return selectedIndices, nil
+}
+
+// runChecklist runs a checklist full-screen. ok is false when the terminal
+// can't run it and the caller should fall back to plain prompts.
+func runChecklist(m checklistModel) (result checklistModel, ok bool, err error) {
+	if !checklistSupported() {
+		return m, false, nil
+	}
+	final, err := tea.NewProgram(m).Run()
+	if err != nil {
+		// Raw mode unavailable
+		return m, false, nil
+	}
+	result = final.(checklistModel)
+	if result.cancelled {
+		return result, true, ErrCancelled
+	}
+	return result, true, nil
+}
+
+// ReviewChecklist lets the user tick items as done with space or mark them
+// deferred with d, and returns the indices of both in ascending order. When
+// the terminal lacks raw-mode support, the fallback asks about each item and
+// returns 'y', 'd' or 'n'.
+func (p *Prompter) ReviewChecklist(title string, items []string, fallback func(index int) (rune, error)) (done, deferred []int, err error) {
+	if len(items) == 0 {
+		return nil, nil, nil
+	}
+
+	fmt.Println(RenderInfo(title))
+	fmt.Println()
+
+	m := newChecklist(items)
+	m.deferrable = true
+	if m, ok, err := runChecklist(m); ok {
+		if err != nil {
+			return nil, nil, err
+		}
+		return m.selected(), marked(m.deferred), nil
+	}
+
+	for i := range items {
+		choice, err := fallback(i)
+		if err != nil {
+			return nil, nil, fallbackError(err)
+		}
+		switch choice {
+		case 'y':
+			done = append(done, i)
+		case 'd':
+			deferred = append(deferred, i)
+		}
+	}
+	return done, deferred, nil
+}
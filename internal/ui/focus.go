@@ -0,0 +1,274 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ErrNoTerminal is returned when focus mode can't take over the terminal
+var ErrNoTerminal = errors.New("focus mode needs an interactive terminal")
+
+// focusBarWidth is the width of the progress bar in cells
+const focusBarWidth = 30
+
+// FocusPhase is a stage of a Pomodoro session
+type FocusPhase int
+
+const (
+	FocusWork FocusPhase = iota
+	FocusBreak
+	FocusLongBreak
+)
+
+// FocusSession configures a Pomodoro session on a work item
+type FocusSession struct {
+	Item           string
+	Work           time.Duration
+	ShortBreak     time.Duration
+	LongBreak      time.Duration
+	LongBreakEvery int // Pomodoros before a long break
+	Target         int // Pomodoros to run, 0 to run until stopped
+
+	// OnWorkStart is called when a stretch of focused work starts, and
+	// OnWorkEnd when it ends: completed is true for a full pomodoro and false
+	// when it was paused or stopped early. An error ends the session.
+	OnWorkStart func(at time.Time) error
+	OnWorkEnd   func(start, end time.Time, completed bool) error
+}
+
+// FocusResult is what a Pomodoro session achieved
+type FocusResult struct {
+	Pomodoros int
+	Focused   time.Duration
+}
+
+// focusTick is sent every second while the session runs
+type focusTick time.Time
+
+// focusModel runs the Pomodoro timer
+type focusModel struct {
+	session   FocusSession
+	phase     FocusPhase
+	length    time.Duration // Length of the current phase
+	deadline  time.Time     // End of the current phase while running
+	remaining time.Duration // Time left while paused
+	workStart time.Time     // Start of the current stretch of work
+	paused    bool
+	waiting   bool // The break is over and the next pomodoro waits for enter
+	now       time.Time
+	result    FocusResult
+	err       error
+	quitting  bool
+}
+
+// Focus runs a full-screen Pomodoro timer until the target is reached or the
+// user stops it
+func (p *Prompter) Focus(session FocusSession) (FocusResult, error) {
+	if !checklistSupported() {
+		return FocusResult{}, ErrNoTerminal
+	}
+
+	now := time.Now()
+	m := focusModel{session: session, now: now}
+	m.startWork(now)
+	if m.err != nil {
+		return m.result, m.err
+	}
+
+	final, err := tea.NewProgram(m).Run()
+	if err != nil {
+		// The work started above still has to be logged
+		m.endWork(time.Now(), false)
+		return m.result, errors.Join(ErrNoTerminal, m.err)
+	}
+	result := final.(focusModel)
+	return result.result, result.err
+}
+
+// Init implements tea.Model
+func (m focusModel) Init() tea.Cmd {
+	return focusTicker()
+}
+
+// focusTicker schedules the next tick
+func focusTicker() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return focusTick(t)
+	})
+}
+
+// bell rings the terminal bell when a phase ends
+func bell() tea.Msg {
+	fmt.Fprint(os.Stderr, "\a")
+	return nil
+}
+
+// Update implements tea.Model
+func (m focusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case focusTick:
+		m.now = time.Time(msg)
+		if m.paused || m.waiting || m.now.Before(m.deadline) {
+			return m, focusTicker()
+		}
+		m.finishPhase()
+		if m.quitting {
+			return m, tea.Sequence(bell, tea.Quit)
+		}
+		return m, tea.Batch(bell, focusTicker())
+
+	case tea.KeyMsg:
+		m.now = time.Now()
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			if m.phase == FocusWork && !m.paused && !m.waiting {
+				m.endWork(m.now, false)
+			}
+			m.quitting = true
+			return m, tea.Quit
+		case " ", "p":
+			if !m.waiting {
+				m.togglePause()
+			}
+		case "s":
+			if m.phase != FocusWork && !m.waiting {
+				m.waiting = true
+			}
+		case "enter":
+			if m.waiting {
+				m.waiting = false
+				m.startWork(m.now)
+			}
+		}
+		if m.err != nil {
+			m.quitting = true
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// startWork starts a pomodoro
+func (m *focusModel) startWork(at time.Time) {
+	m.phase = FocusWork
+	m.length = m.session.Work
+	m.deadline = at.Add(m.length)
+	m.workStart = at
+	if m.session.OnWorkStart != nil {
+		m.err = m.session.OnWorkStart(at)
+	}
+}
+
+// endWork ends a stretch of focused work
+func (m *focusModel) endWork(at time.Time, completed bool) {
+	m.result.Focused += at.Sub(m.workStart)
+	if m.session.OnWorkEnd != nil {
+		if err := m.session.OnWorkEnd(m.workStart, at, completed); err != nil {
+			m.err = err
+		}
+	}
+}
+
+// finishPhase moves on when the time of a phase is up
+func (m *focusModel) finishPhase() {
+	if m.phase != FocusWork {
+		m.waiting = true
+		return
+	}
+
+	m.endWork(m.deadline, true)
+	m.result.Pomodoros++
+	if m.err != nil || (m.session.Target > 0 && m.result.Pomodoros >= m.session.Target) {
+		m.quitting = true
+		return
+	}
+
+	m.phase, m.length = FocusBreak, m.session.ShortBreak
+	if m.session.LongBreakEvery > 0 && m.result.Pomodoros%m.session.LongBreakEvery == 0 {
+		m.phase, m.length = FocusLongBreak, m.session.LongBreak
+	}
+	m.deadline = m.deadline.Add(m.length)
+}
+
+// togglePause pauses or resumes the running phase. Paused work isn't logged.
+func (m *focusModel) togglePause() {
+	if m.paused {
+		m.paused = false
+		if m.phase == FocusWork {
+			m.startWork(m.now)
+		}
+		m.deadline = m.now.Add(m.remaining)
+		return
+	}
+
+	m.paused = true
+	m.remaining = m.deadline.Sub(m.now)
+	if m.phase == FocusWork {
+		m.endWork(m.now, false)
+	}
+}
+
+// left returns the time left in the current phase
+func (m focusModel) left() time.Duration {
+	if m.paused {
+		return m.remaining
+	}
+	if left := m.deadline.Sub(m.now); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// View implements tea.Model
+func (m focusModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("🍅 "+m.session.Item) + "\n\n")
+
+	label, style := T(MsgFocusWork), lipgloss.NewStyle().Foreground(Red).Bold(true)
+	switch m.phase {
+	case FocusBreak:
+		label, style = T(MsgFocusBreak), lipgloss.NewStyle().Foreground(Green).Bold(true)
+	case FocusLongBreak:
+		label, style = T(MsgFocusLongBreak), lipgloss.NewStyle().Foreground(Green).Bold(true)
+	}
+	if m.paused {
+		label += " · " + T(MsgFocusPaused)
+	}
+
+	left := m.left().Round(time.Second)
+	clock := fmt.Sprintf("%02d:%02d", int(left.Minutes()), int(left.Seconds())%60)
+	b.WriteString("  " + style.Render(label) + "  " + style.Render(clock) + "\n")
+
+	filled := focusBarWidth
+	if m.length > 0 {
+		filled = int(float64(focusBarWidth) * float64(m.length-left) / float64(m.length))
+	}
+	filled = min(max(filled, 0), focusBarWidth)
+	b.WriteString("  " + style.Render(strings.Repeat("█", filled)) + MutedStyle.Render(strings.Repeat("░", focusBarWidth-filled)) + "\n\n")
+
+	done := strings.Repeat("●", m.result.Pomodoros)
+	if m.session.Target > m.result.Pomodoros {
+		done += strings.Repeat("○", m.session.Target-m.result.Pomodoros)
+	}
+	b.WriteString("  ")
+	if done != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(Red).Render(done) + "  ")
+	}
+	b.WriteString(MutedStyle.Render(Tf(MsgFocusCount, m.result.Pomodoros)) + "\n\n")
+
+	if m.waiting {
+		b.WriteString(InfoStyle.Render(T(MsgFocusReady)) + "\n")
+	}
+	b.WriteString(MutedStyle.Render(T(MsgFocusHelp)) + "\n")
+	return b.String()
+}
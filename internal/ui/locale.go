@@ -0,0 +1,218 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Message keys for strings rendered by the ui package
+const (
+	MsgConfirmCompletion   = "confirm_completion"
+	MsgReviewPending       = "review_pending"
+	MsgEnterNewItem        = "enter_new_item"
+	MsgTaskNumber          = "task_number"
+	MsgPendingHeader       = "pending_header"
+	MsgDoneHeader          = "done_header"
+	MsgNoPending           = "no_pending"
+	MsgNoCompleted         = "no_completed"
+	MsgStatsTasks          = "stats_tasks"
+	MsgStatsPending        = "stats_pending"
+	MsgStatsCompleted      = "stats_completed"
+	MsgSelectTasksToDelete = "select_tasks_to_delete"
+	MsgDeleteTask          = "delete_task"
+	MsgTaskTypePending     = "pending"
+	MsgTaskTypeCompleted   = "completed"
+	MsgSelectWorkplace     = "select_workplace"
+	MsgSelectRename        = "select_workplace_rename"
+	MsgWorkplace           = "workplace"
+	MsgNameEmpty           = "name_empty"
+	MsgNameCommas          = "name_commas"
+	MsgPickDay             = "pick_day"
+	MsgChecklistHelp       = "checklist_help"
+	MsgChecklistSelected   = "checklist_selected"
+	MsgChecklistFilter     = "checklist_filter"
+	MsgChecklistNoMatches  = "checklist_no_matches"
+	MsgChecklistDeferred   = "checklist_deferred"
+	MsgChecklistDeferHelp  = "checklist_defer_help"
+	MsgReviewChoice        = "review_choice"
+	MsgDeferUntil          = "defer_until"
+	MsgFocusWork           = "focus_work"
+	MsgFocusBreak          = "focus_break"
+	MsgFocusLongBreak      = "focus_long_break"
+	MsgFocusPaused         = "focus_paused"
+	MsgFocusCount          = "focus_count"
+	MsgFocusReady          = "focus_ready"
+	MsgFocusHelp           = "focus_help"
+	MsgFocusMarkDone       = "focus_mark_done"
+)
+
+// locales holds the bundled UI translations. English is complete; other
+// locales fall back to English for any missing key.
+var locales = map[string]map[string]string{
+	"en": {
+		MsgConfirmCompletion:   "Did you complete: \"%s\"",
+		MsgReviewPending:       "Review pending items:",
+		MsgEnterNewItem:        "Enter new work item (leave empty to skip)",
+		MsgTaskNumber:          "Task #%d",
+		MsgPendingHeader:       "Pending",
+		MsgDoneHeader:          "Done",
+		MsgNoPending:           "No pending items — you're all caught up!",
+		MsgNoCompleted:         "No completed items yet",
+		MsgStatsTasks:          "Tasks: ",
+		MsgStatsPending:        " pending  ",
+		MsgStatsCompleted:      " completed",
+		MsgSelectTasksToDelete: "Select %s tasks to delete:",
+		MsgDeleteTask:          "Delete %s task: \"%s\"",
+		MsgTaskTypePending:     "pending",
+		MsgTaskTypeCompleted:   "completed",
+		MsgSelectWorkplace:     "Select workplace",
+		MsgSelectRename:        "Select workplace to rename",
+		MsgWorkplace:           "Workplace",
+		MsgNameEmpty:           "workplace name cannot be empty",
+		MsgNameCommas:          "workplace name cannot contain commas",
+		MsgPickDay:             "Show a day (YYYY-MM-DD, empty to quit)",
+		MsgChecklistHelp:       "space toggle • a all • / filter • enter confirm • esc cancel",
+		MsgChecklistSelected:   "%d of %d selected",
+		MsgChecklistFilter:     "Filter: ",
+		MsgChecklistNoMatches:  "No matching items",
+		MsgChecklistDeferred:   "%d deferred",
+		MsgChecklistDeferHelp:  "space done • d defer • a all • / filter • enter confirm • esc cancel",
+		MsgReviewChoice:        "Did you complete: \"%s\" (y = done, n = keep, d = defer)",
+		MsgDeferUntil:          "Defer \"%s\" until (YYYY-MM-DD, tomorrow, +1w, friday…)",
+		MsgFocusWork:           "Focus",
+		MsgFocusBreak:          "Short break",
+		MsgFocusLongBreak:      "Long break",
+		MsgFocusPaused:         "paused",
+		MsgFocusCount:          "Pomodoros: %d",
+		MsgFocusReady:          "Break is over — press enter to start the next pomodoro",
+		MsgFocusHelp:           "space pause • s skip break • q stop",
+		MsgFocusMarkDone:       "Mark \"%s\" as done",
+	},
+	"de": {
+		MsgConfirmCompletion:   "Erledigt: \"%s\"",
+		MsgReviewPending:       "Offene Aufgaben prüfen:",
+		MsgEnterNewItem:        "Neue Aufgabe eingeben (leer lassen zum Überspringen)",
+		MsgTaskNumber:          "Aufgabe #%d",
+		MsgPendingHeader:       "Offen",
+		MsgDoneHeader:          "Erledigt",
+		MsgNoPending:           "Keine offenen Aufgaben — alles erledigt!",
+		MsgNoCompleted:         "Noch nichts erledigt",
+		MsgStatsTasks:          "Aufgaben: ",
+		MsgStatsPending:        " offen  ",
+		MsgStatsCompleted:      " erledigt",
+		MsgSelectTasksToDelete: "Zu löschende %s Aufgaben auswählen:",
+		MsgDeleteTask:          "%s Aufgabe löschen: \"%s\"",
+		MsgTaskTypePending:     "offene",
+		MsgTaskTypeCompleted:   "erledigte",
+		MsgSelectWorkplace:     "Arbeitsbereich auswählen",
+		MsgSelectRename:        "Umzubenennenden Arbeitsbereich auswählen",
+		MsgWorkplace:           "Arbeitsbereich",
+		MsgNameEmpty:           "Name des Arbeitsbereichs darf nicht leer sein",
+		MsgNameCommas:          "Name des Arbeitsbereichs darf keine Kommas enthalten",
+		MsgPickDay:             "Tag anzeigen (JJJJ-MM-TT, leer zum Beenden)",
+		MsgChecklistHelp:       "Leertaste auswählen • a alle • / filtern • Enter bestätigen • Esc abbrechen",
+		MsgChecklistSelected:   "%d von %d ausgewählt",
+		MsgChecklistFilter:     "Filter: ",
+		MsgChecklistNoMatches:  "Keine passenden Aufgaben",
+		MsgChecklistDeferred:   "%d verschoben",
+		MsgChecklistDeferHelp:  "Leertaste erledigt • d verschieben • a alle • / filtern • Enter bestätigen • Esc abbrechen",
+		MsgReviewChoice:        "Erledigt: \"%s\" (y = erledigt, n = behalten, d = verschieben)",
+		MsgDeferUntil:          "\"%s\" verschieben bis (JJJJ-MM-TT, tomorrow, +1w, friday…)",
+		MsgFocusWork:           "Fokus",
+		MsgFocusBreak:          "Kurze Pause",
+		MsgFocusLongBreak:      "Lange Pause",
+		MsgFocusPaused:         "pausiert",
+		MsgFocusCount:          "Pomodoros: %d",
+		MsgFocusReady:          "Die Pause ist vorbei — Enter startet den nächsten Pomodoro",
+		MsgFocusHelp:           "Leertaste pausieren • s Pause überspringen • q beenden",
+		MsgFocusMarkDone:       "\"%s\" als erledigt markieren",
+	},
+	"ja": {
+		MsgConfirmCompletion:   "完了しましたか: 「%s」",
+		MsgReviewPending:       "未完了の項目を確認:",
+		MsgEnterNewItem:        "新しい作業を入力 (空欄でスキップ)",
+		MsgTaskNumber:          "タスク #%d",
+		MsgPendingHeader:       "未完了",
+		MsgDoneHeader:          "完了",
+		MsgNoPending:           "未完了の項目はありません — すべて完了です!",
+		MsgNoCompleted:         "完了した項目はまだありません",
+		MsgStatsTasks:          "タスク: ",
+		MsgStatsPending:        " 未完了  ",
+		MsgStatsCompleted:      " 完了",
+		MsgSelectTasksToDelete: "削除する%sタスクを選択:",
+		MsgDeleteTask:          "%sタスクを削除: 「%s」",
+		MsgTaskTypePending:     "未完了の",
+		MsgTaskTypeCompleted:   "完了した",
+		MsgSelectWorkplace:     "ワークプレースを選択",
+		MsgSelectRename:        "名前を変更するワークプレースを選択",
+		MsgWorkplace:           "ワークプレース",
+		MsgNameEmpty:           "ワークプレース名は空にできません",
+		MsgNameCommas:          "ワークプレース名にカンマは使えません",
+		MsgPickDay:             "表示する日 (YYYY-MM-DD、空欄で終了)",
+		MsgChecklistHelp:       "スペース 選択 • a すべて • / 絞り込み • Enter 確定 • Esc キャンセル",
+		MsgChecklistSelected:   "%d / %d 件選択",
+		MsgChecklistFilter:     "絞り込み: ",
+		MsgChecklistNoMatches:  "一致する項目はありません",
+		MsgChecklistDeferred:   "%d 件延期",
+		MsgChecklistDeferHelp:  "スペース 完了 • d 延期 • a すべて • / 絞り込み • Enter 確定 • Esc キャンセル",
+		MsgReviewChoice:        "完了しましたか: 「%s」 (y = 完了, n = 保留, d = 延期)",
+		MsgDeferUntil:          "「%s」の延期先 (YYYY-MM-DD, tomorrow, +1w, friday…)",
+		MsgFocusWork:           "集中",
+		MsgFocusBreak:          "短い休憩",
+		MsgFocusLongBreak:      "長い休憩",
+		MsgFocusPaused:         "一時停止中",
+		MsgFocusCount:          "ポモドーロ: %d",
+		MsgFocusReady:          "休憩終了 — Enter で次のポモドーロを開始",
+		MsgFocusHelp:           "スペース 一時停止 • s 休憩をスキップ • q 終了",
+		MsgFocusMarkDone:       "「%s」を完了にしますか",
+	},
+}
+
+// currentLocale is the active UI locale
+var currentLocale = "en"
+
+// SetLocale sets the language of UI strings. Regional variants such as
+// "de_DE.UTF-8" use their base language.
+func SetLocale(locale string) error {
+	base := strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(base, "_-."); i >= 0 {
+		base = base[:i]
+	}
+	if base == "" {
+		base = "en"
+	}
+
+	if _, ok := locales[base]; !ok {
+		return fmt.Errorf("unsupported locale %q (available: %s)", locale, strings.Join(Locales(), ", "))
+	}
+
+	currentLocale = base
+	return nil
+}
+
+// Locales returns the bundled UI locales
+func Locales() []string {
+	var names []string
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// T returns the UI string for a message key in the active locale
+func T(key string) string {
+	if msg, ok := locales[currentLocale][key]; ok {
+		return msg
+	}
+	if msg, ok := locales["en"][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// Tf formats the UI string for a message key in the active locale
+func Tf(key string, args ...interface{}) string {
+	return fmt.Sprintf(T(key), args...)
+}
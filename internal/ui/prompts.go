@@ -20,7 +20,7 @@ func NewPrompter() *Prompter {
 // ConfirmCompletion asks if a work item was completed
 func (p *Prompter) ConfirmCompletion(item notes.WorkItem) (bool, error) {
 	prompt := promptui.Prompt{
-		Label:     fmt.Sprintf("Did you complete: \"%s\"", item.Text),
+		Label:     Tf(MsgConfirmCompletion, item.Text),
 		IsConfirm: true,
 	}
 
@@ -37,42 +37,66 @@ func (p *Prompter) ConfirmCompletion(item notes.WorkItem) (bool, error) {
 
 // SelectPendingItems allows selecting multiple pending items to mark as done
 func (p *Prompter) SelectPendingItems(items []notes.WorkItem) ([]int, error) {
-	if len(items) == 0 {
-		return nil, nil
-	}
+	return p.Checklist(T(MsgReviewPending), itemTexts(items), func(i int) (bool, error) {
+		return p.ConfirmCompletion(items[i])
+	})
+}
 
-	templates := &promptui.SelectTemplates{
-		Label:    "{{ . }}",
-		Active:   "> {{ .Text | cyan }}",
-		Inactive: "  {{ .Text }}",
-		Selected: "{{ .Text | green }}",
-	}
+// ReviewPendingItems lets the user mark pending items as done or deferred
+func (p *Prompter) ReviewPendingItems(items []notes.WorkItem) (done, deferred []int, err error) {
+	return p.ReviewChecklist(T(MsgReviewPending), itemTexts(items), func(i int) (rune, error) {
+		return p.ConfirmReview(items[i])
+	})
+}
 
-	var selectedIndices []int
+// ConfirmReview asks whether an item was completed, kept or should be deferred,
+// returning 'y', 'n' or 'd'
+func (p *Prompter) ConfirmReview(item notes.WorkItem) (rune, error) {
+	prompt := promptui.Prompt{
+		Label:   Tf(MsgReviewChoice, item.Text),
+		Default: "n",
+		Validate: func(input string) error {
+			switch strings.ToLower(strings.TrimSpace(input)) {
+			case "y", "n", "d", "":
+				return nil
+			}
+			return fmt.Errorf("answer y, n or d")
+		},
+	}
 
-	fmt.Println(RenderInfo("Review pending items:"))
-	fmt.Println()
+	result, err := prompt.Run()
+	if err != nil {
+		return 'n', err
+	}
 
-	for i, item := range items {
-		completed, err := p.ConfirmCompletion(item)
-		if err != nil {
-			return selectedIndices, err
-		}
-		if completed {
-			selectedIndices = append(selectedIndices, i)
-		}
+	switch strings.ToLower(strings.TrimSpace(result)) {
+	case "y":
+		return 'y', nil
+	case "d":
+		return 'd', nil
 	}
+	return 'n', nil
+}
 
-	// Suppress unused variable warning
-	_ = templates
+// PromptForDeferDate asks until when an item should be deferred
+func (p *Prompter) PromptForDeferDate(item notes.WorkItem, validate func(string) error) (string, error) {
+	prompt := promptui.Prompt{
+		Label:    Tf(MsgDeferUntil, item.Text),
+		Default:  "tomorrow",
+		Validate: validate,
+	}
 
-	return selectedIndices, nil
+	result, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(result), nil
 }
 
 // PromptForNewItem asks for a new work item
 func (p *Prompter) PromptForNewItem() (string, error) {
 	prompt := promptui.Prompt{
-		Label: "Enter new work item (leave empty to skip)",
+		Label: T(MsgEnterNewItem),
 	}
 
 	result, err := prompt.Run()
@@ -88,7 +112,7 @@ func (p *Prompter) PromptForNewItem() (string, error) {
 
 // PromptForTaskInLoop prompts for a task and returns it with a flag indicating if interrupted
 func (p *Prompter) PromptForTaskInLoop(taskNumber int) (string, bool, error) {
-	label := PromptStyle.Render(fmt.Sprintf("Task #%d", taskNumber))
+	label := PromptStyle.Render(Tf(MsgTaskNumber, taskNumber))
 	prompt := promptui.Prompt{
 		Label: label,
 	}
@@ -122,6 +146,29 @@ func (p *Prompter) ConfirmAction(message string) (bool, error) {
 	return true, nil
 }
 
+// PromptForDay asks for a day to show, returning "" when the user is done
+func (p *Prompter) PromptForDay(validate func(string) error) (string, error) {
+	prompt := promptui.Prompt{
+		Label: T(MsgPickDay),
+		Validate: func(input string) error {
+			if strings.TrimSpace(input) == "" {
+				return nil
+			}
+			return validate(strings.TrimSpace(input))
+		},
+	}
+
+	result, err := prompt.Run()
+	if err != nil {
+		if err == promptui.ErrInterrupt || err == promptui.ErrEOF {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(result), nil
+}
+
 // SelectFromList allows selecting an item from a list
 func (p *Prompter) SelectFromList(label string, items []string) (int, error) {
 	prompt := promptui.Select{
@@ -140,30 +187,30 @@ func (p *Prompter) SelectFromList(label string, items []string) (int, error) {
 // DisplayWorkItems shows a formatted list of work items with modern styling
 func (p *Prompter) DisplayWorkItems(pending, completed []notes.WorkItem) {
 	// Pending section
-	pendingHeader := HeaderStyle.Render("Pending") + " " + RenderBadge(len(pending), PendingBadgeStyle)
+	pendingHeader := HeaderStyle.Render(T(MsgPendingHeader)) + " " + RenderBadge(len(pending), PendingBadgeStyle)
 	fmt.Println(pendingHeader)
 
 	if len(pending) == 0 {
-		fmt.Println(RenderEmptyState("  No pending items — you're all caught up!"))
+		fmt.Println(RenderEmptyState("  " + T(MsgNoPending)))
 	} else {
 		var pendingItems []string
 		for i, item := range pending {
-			pendingItems = append(pendingItems, RenderPendingItem(i+1, item.Text))
+			pendingItems = append(pendingItems, renderItem(i+1, item))
 		}
 		content := strings.Join(pendingItems, "\n")
 		fmt.Println(PendingCardStyle.Render(content))
 	}
 
 	// Completed section
-	completedHeader := HeaderStyle.Render("Done") + " " + RenderBadge(len(completed), CompletedBadgeStyle)
+	completedHeader := HeaderStyle.Render(T(MsgDoneHeader)) + " " + RenderBadge(len(completed), CompletedBadgeStyle)
 	fmt.Println(completedHeader)
 
 	if len(completed) == 0 {
-		fmt.Println(RenderEmptyState("  No completed items yet"))
+		fmt.Println(RenderEmptyState("  " + T(MsgNoCompleted)))
 	} else {
 		var completedItems []string
 		for i, item := range completed {
-			completedItems = append(completedItems, RenderCompletedItem(i+1, item.Text))
+			completedItems = append(completedItems, renderItem(i+1, item))
 		}
 		content := strings.Join(completedItems, "\n")
 		fmt.Println(CompletedCardStyle.Render(content))
@@ -173,21 +220,38 @@ func (p *Prompter) DisplayWorkItems(pending, completed []notes.WorkItem) {
 // DisplayPendingOnly shows only pending work items with modern styling
 func (p *Prompter) DisplayPendingOnly(pending []notes.WorkItem) {
 	// Pending section header
-	pendingHeader := HeaderStyle.Render("Pending") + " " + RenderBadge(len(pending), PendingBadgeStyle)
+	pendingHeader := HeaderStyle.Render(T(MsgPendingHeader)) + " " + RenderBadge(len(pending), PendingBadgeStyle)
 	fmt.Println(pendingHeader)
 
 	if len(pending) == 0 {
-		fmt.Println(RenderEmptyState("  No pending items — you're all caught up!"))
+		fmt.Println(RenderEmptyState("  " + T(MsgNoPending)))
 	} else {
 		var pendingItems []string
 		for i, item := range pending {
-			pendingItems = append(pendingItems, RenderPendingItem(i+1, item.Text))
+			pendingItems = append(pendingItems, renderItem(i+1, item))
 		}
 		content := strings.Join(pendingItems, "\n")
 		fmt.Println(PendingCardStyle.Render(content))
 	}
 }
 
+// renderItem renders a work item according to its state
+func renderItem(index int, item notes.WorkItem) string {
+	switch {
+	case item.Cancelled():
+		return RenderMarkedItem(index, IconCancelled, CancelledItemStyle, item.Text)
+	case item.Deferred():
+		return RenderMarkedItem(index, IconDeferred, DeferredItemStyle, item.Text)
+	case item.State == notes.StateWaiting:
+		return RenderMarkedItem(index, IconWaiting, BlockedItemStyle, item.Text)
+	case item.State == notes.StateBlocked:
+		return RenderMarkedItem(index, IconBlocked, BlockedItemStyle, item.Text)
+	case item.Completed:
+		return RenderCompletedItem(index, item.Text)
+	}
+	return RenderPendingItem(index, item.Text)
+}
+
 // DisplayMessage shows a message to the user
 func (p *Prompter) DisplayMessage(message string) {
 	fmt.Println(RenderInfo(message))
@@ -233,44 +297,43 @@ func (p *Prompter) DisplayDateHeader(date string) {
 func (p *Prompter) DisplayStats(pending, completed int) {
 	stats := lipgloss.JoinHorizontal(
 		lipgloss.Center,
-		MutedStyle.Render("Tasks: "),
+		MutedStyle.Render(T(MsgStatsTasks)),
 		RenderBadge(pending, PendingBadgeStyle),
-		MutedStyle.Render(" pending  "),
+		MutedStyle.Render(T(MsgStatsPending)),
 		RenderBadge(completed, CompletedBadgeStyle),
-		MutedStyle.Render(" completed"),
+		MutedStyle.Render(T(MsgStatsCompleted)),
 	)
 	fmt.Println(stats)
 	fmt.Println()
 }
 
-// SelectTasksToDelete allows selecting tasks to delete from a list
+// SelectTasksToDelete allows selecting multiple tasks to delete.
+// taskType is MsgTaskTypePending or MsgTaskTypeCompleted.
 func (p *Prompter) SelectTasksToDelete(items []notes.WorkItem, taskType string) ([]int, error) {
-	if len(items) == 0 {
-		return nil, nil
-	}
-
-	var selectedIndices []int
-
-	fmt.Println(RenderInfo(fmt.Sprintf("Select %s tasks to delete:", taskType)))
-	fmt.Println()
-
-	for i, item := range items {
+	return p.Checklist(Tf(MsgSelectTasksToDelete, T(taskType)), itemTexts(items), func(i int) (bool, error) {
 		prompt := promptui.Prompt{
-			Label:     fmt.Sprintf("Delete %s task: \"%s\"", taskType, item.Text),
+			Label:     Tf(MsgDeleteTask, T(taskType), items[i].Text),
 			IsConfirm: true,
 		}
 
 		_, err := prompt.Run()
 		if err != nil {
 			if err == promptui.ErrAbort {
-				continue // User said no, skip this item
+				return false, nil // User said no, skip this item
 			}
-			return selectedIndices, err
+			return false, err
 		}
-		selectedIndices = append(selectedIndices, i)
-	}
+		return true, nil
+	})
+}
 
-	return selectedIndices, nil
+// itemTexts returns the text of each item
+func itemTexts(items []notes.WorkItem) []string {
+	texts := make([]string, len(items))
+	for i, item := range items {
+		texts[i] = item.Text
+	}
+	return texts
 }
 
 // SelectWorkplace allows selecting a workplace from the configured list
@@ -285,10 +348,10 @@ func (p *Prompter) SelectWorkplace(workplaces []string) (string, error) {
 	}
 
 	fmt.Println()
-	fmt.Println(RenderInfo("Select workplace"))
+	fmt.Println(RenderInfo(T(MsgSelectWorkplace)))
 
 	prompt := promptui.Select{
-		Label: "Workplace",
+		Label: T(MsgWorkplace),
 		Items: workplaces,
 		Size:  10,
 		Templates: &promptui.SelectTemplates{
@@ -312,10 +375,10 @@ func (p *Prompter) PromptForWorkplaceName(label string) (string, error) {
 	validate := func(input string) error {
 		trimmed := strings.TrimSpace(input)
 		if trimmed == "" {
-			return fmt.Errorf("workplace name cannot be empty")
+			return fmt.Errorf("%s", T(MsgNameEmpty))
 		}
 		if strings.Contains(trimmed, ",") {
-			return fmt.Errorf("workplace name cannot contain commas")
+			return fmt.Errorf("%s", T(MsgNameCommas))
 		}
 		return nil
 	}
@@ -343,10 +406,10 @@ func (p *Prompter) SelectWorkplaceToRename(workplaces []string) (string, error)
 	}
 
 	fmt.Println()
-	fmt.Println(RenderInfo("Select workplace to rename"))
+	fmt.Println(RenderInfo(T(MsgSelectRename)))
 
 	prompt := promptui.Select{
-		Label: "Workplace",
+		Label: T(MsgWorkplace),
 		Items: workplaces,
 		Size:  10,
 		Templates: &promptui.SelectTemplates{
@@ -23,6 +23,15 @@ var (
 	Gray      = lipgloss.Color("#6C757D")
 	DarkGray  = lipgloss.Color("#495057")
 	Subtle    = lipgloss.Color("#383838")
+
+	// Heatmap levels, from no activity to the busiest days
+	HeatColors = []lipgloss.Color{
+		Subtle,
+		lipgloss.Color("#0E4429"),
+		lipgloss.Color("#006D32"),
+		lipgloss.Color("#26A641"),
+		Green,
+	}
 )
 
 // Icons for different states
@@ -36,6 +45,10 @@ const (
 	IconError     = "✗"
 	IconArrow     = "→"
 	IconBullet    = "•"
+	IconCancelled = "⊘"
+	IconBlocked   = "!"
+	IconWaiting   = "?"
+	IconDeferred  = "»"
 )
 
 // Base styles
@@ -79,6 +92,16 @@ var (
 	CompletedItemStyle = lipgloss.NewStyle().
 		Foreground(Green)
 
+	CancelledItemStyle = lipgloss.NewStyle().
+		Foreground(Gray).
+		Strikethrough(true)
+
+	BlockedItemStyle = lipgloss.NewStyle().
+		Foreground(Orange)
+
+	DeferredItemStyle = lipgloss.NewStyle().
+		Foreground(Blue)
+
 	// Status message styles
 	SuccessStyle = lipgloss.NewStyle().
 		Foreground(Green).
@@ -192,6 +215,12 @@ func RenderCompletedItem(index int, text string) string {
 	return fmt.Sprintf("  %s %s %s", num, icon, CompletedItemStyle.Render(text))
 }
 
+// RenderMarkedItem renders a cancelled, blocked or deferred task item
+func RenderMarkedItem(index int, icon string, style lipgloss.Style, text string) string {
+	num := MutedStyle.Render(fmt.Sprintf("%2d.", index))
+	return fmt.Sprintf("  %s %s %s", num, style.UnsetStrikethrough().Render(icon), style.Render(text))
+}
+
 // RenderEmptyState renders an empty state message
 func RenderEmptyState(text string) string {
 	return EmptyStateStyle.Render(text)